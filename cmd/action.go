@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lighttiger2505/huc/internal/cmdutil"
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+// customAction returns the user-defined action in the config. Built-in actions
+// are not overridden, the caller checks them before.
+func customAction(cfg *config.Config, name string) (config.Action, error) {
+	action, ok := cfg.Actions[name]
+	if !ok {
+		return config.Action{}, fmt.Errorf("Invalid action, '%s'", name)
+	}
+	return action, nil
+}
+
+func runCustomAction(name string, action config.Action, pInfo *git.GitLabProjectInfo, items []cmdutil.ActionItem) error {
+	return cmdutil.NewActionRunner().Run(name, action, pInfo, items)
+}
+
+func issueActionItem(p provider.Provider, i *provider.Issue) cmdutil.ActionItem {
+	return cmdutil.ActionItem{
+		Number:      i.Number,
+		Title:       i.Title,
+		Author:      i.Author,
+		PublishedAt: i.PublishedAt,
+		Body:        i.Body,
+		URL:         p.IssueURL(i.Number),
+	}
+}
+
+func pullRequestActionItem(p provider.Provider, pr *provider.PullRequest) cmdutil.ActionItem {
+	return cmdutil.ActionItem{
+		Number:      pr.Number,
+		Title:       pr.Title,
+		Author:      pr.Author,
+		PublishedAt: pr.PublishedAt,
+		Body:        pr.Body,
+		URL:         p.PullRequestURL(pr.Number),
+	}
+}
+
+func releaseActionItem(p provider.Provider, r *provider.Release) cmdutil.ActionItem {
+	return cmdutil.ActionItem{
+		TagName: r.TagName,
+		Title:   r.Name,
+		Body:    r.Description,
+		URL:     p.ReleaseURL(r.TagName),
+	}
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/alias"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var aliasCmd = &cobra.Command{
+	Use:   "alias",
+	Short: "Manage aliases of subcommands",
+	Long: `Manage aliases of subcommands.
+
+An alias expands to the subcommand of huc, "$1", "$2" and so on are replaced
+by the arguments and the rest of arguments are appended. An alias starting
+with "!" runs on the shell, the arguments are passed as "$1", "$2" and so on.
+Aliases are stored in the config, they can be shared by copying "aliases".`,
+	Example: `  huc alias set bugs 'issue --labels bug --states OPEN'
+  huc alias set label 'issue --labels "$1"'
+  huc alias set co '!git fetch origin "pull/$1/head:pr-$1" && git checkout "pr-$1"'`,
+}
+
+var aliasSetCmd = &cobra.Command{
+	Use:   "set <name> <expansion>",
+	Short: "Create or update the alias",
+	Args:  cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return setAlias(args[0], args[1])
+	},
+}
+
+var aliasListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List aliases",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listAlias()
+	},
+}
+
+var aliasDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete the alias",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return deleteAlias(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(aliasCmd)
+	aliasCmd.AddCommand(aliasSetCmd)
+	aliasCmd.AddCommand(aliasListCmd)
+	aliasCmd.AddCommand(aliasDeleteCmd)
+}
+
+func setAlias(name, expansion string) error {
+	if isBuiltinCommand([]string{name}) {
+		return fmt.Errorf("cannot override the built-in command, %s", name)
+	}
+	if !alias.IsShell(expansion) {
+		args, err := alias.Split(expansion)
+		if err != nil {
+			return fmt.Errorf("Invalid expansion, %s", err)
+		}
+		if !isBuiltinCommand(args) {
+			return fmt.Errorf("Invalid expansion, '%s' is not a huc subcommand. Prefix '!' to run on the shell", expansion)
+		}
+	}
+
+	cfg, err := factory.Config()
+	if err != nil {
+		return fmt.Errorf("cannot load config, %s", err)
+	}
+	if cfg.Aliases == nil {
+		cfg.Aliases = map[string]string{}
+	}
+	cfg.Aliases[name] = expansion
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("cannot save config, %s", err)
+	}
+	return nil
+}
+
+func listAlias() error {
+	cfg, err := factory.Config()
+	if err != nil {
+		return fmt.Errorf("cannot load config, %s", err)
+	}
+	names := make([]string, 0, len(cfg.Aliases))
+	for name := range cfg.Aliases {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(factory.Out, "%s\t%s\n", name, cfg.Aliases[name])
+	}
+	return nil
+}
+
+func deleteAlias(name string) error {
+	cfg, err := factory.Config()
+	if err != nil {
+		return fmt.Errorf("cannot load config, %s", err)
+	}
+	if _, ok := cfg.Aliases[name]; !ok {
+		return fmt.Errorf("Not found alias, %s", name)
+	}
+	delete(cfg.Aliases, name)
+	if err := cfg.Save(); err != nil {
+		return fmt.Errorf("cannot save config, %s", err)
+	}
+	return nil
+}
+
+// isBuiltinCommand returns true when the first argument is the subcommand of huc.
+func isBuiltinCommand(args []string) bool {
+	if len(args) == 0 {
+		return false
+	}
+	cmd, _, err := rootCmd.Find(args)
+	return err == nil && cmd != rootCmd
+}
+
+// expandAlias returns the arguments that the alias of the first argument
+// after the global flags is expanded, e.g. "--verbose bugs". The shell command
+// is returned with true when the alias starts with "!".
+func expandAlias(args []string) ([]string, bool, error) {
+	n := leadingFlags(args)
+	if n == len(args) || isBuiltinCommand(args[n:]) {
+		return args, false, nil
+	}
+
+	cfg, err := factory.Config()
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot load config, %s", err)
+	}
+	expansion, ok := cfg.Aliases[args[n]]
+	if !ok {
+		return args, false, nil
+	}
+
+	if alias.IsShell(expansion) {
+		return append([]string{expansion[len(alias.ShellPrefix):]}, args[n+1:]...), true, nil
+	}
+	expanded, err := alias.Expand(expansion, args[n+1:])
+	if err != nil {
+		return nil, false, fmt.Errorf("cannot expand alias '%s', %s", args[n], err)
+	}
+	return append(append([]string{}, args[:n]...), expanded...), false, nil
+}
+
+// leadingFlags returns the number of the arguments of the global flags before
+// the subcommand, e.g. 2 of "--profile github.com issue".
+func leadingFlags(args []string) int {
+	flags := rootCmd.PersistentFlags()
+	i := 0
+	for i < len(args) {
+		arg := args[i]
+		if arg == "--" || arg == "-" || !strings.HasPrefix(arg, "-") {
+			break
+		}
+		i++
+		if strings.Contains(arg, "=") {
+			continue
+		}
+		var f *pflag.Flag
+		if strings.HasPrefix(arg, "--") {
+			f = flags.Lookup(arg[2:])
+		} else {
+			// The value of the combined shorthands, e.g. "-vy", is of the last one
+			f = flags.ShorthandLookup(arg[len(arg)-1:])
+		}
+		// The next argument is the value unless the flag is a boolean
+		if f != nil && f.NoOptDefVal == "" {
+			i++
+		}
+	}
+	if i > len(args) {
+		return len(args)
+	}
+	return i
+}
+
+// runShellAlias runs the command on the shell with the arguments as "$1", "$2" and so on.
+// sh is required on Windows too, e.g. the one of Git for Windows.
+func runShellAlias(command string, args []string) error {
+	cmd := exec.Command("sh", append([]string{"-c", command, "huc"}, args...)...)
+	cmd.Stdin = factory.In
+	cmd.Stdout = factory.Out
+	cmd.Stderr = factory.ErrOut
+	return cmd.Run()
+}
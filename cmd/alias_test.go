@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/config"
+)
+
+func TestExpandAlias(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.Aliases = map[string]string{
+		"bugs": "issue --labels bug",
+		"co":   "!git checkout",
+	}
+	f := NewFactory()
+	f.Config = func() (*config.Config, error) {
+		return cfg, nil
+	}
+	defer func(saved *Factory) { factory = saved }(factory)
+	factory = f
+
+	tests := []struct {
+		args      []string
+		want      []string
+		wantShell bool
+	}{
+		{
+			args: []string{"bugs", "--num", "10"},
+			want: []string{"issue", "--labels", "bug", "--num", "10"},
+		},
+		{
+			args: []string{"--verbose", "bugs"},
+			want: []string{"--verbose", "issue", "--labels", "bug"},
+		},
+		{
+			args: []string{"-vy", "--profile", "github.com", "--timeout=10s", "bugs"},
+			want: []string{"-vy", "--profile", "github.com", "--timeout=10s", "issue", "--labels", "bug"},
+		},
+		{
+			args:      []string{"--debug", "co", "main"},
+			want:      []string{"git checkout", "main"},
+			wantShell: true,
+		},
+		{
+			// The value of flag is not the alias
+			args: []string{"--profile", "bugs"},
+			want: []string{"--profile", "bugs"},
+		},
+		{
+			args: []string{"--verbose", "issue", "bugs"},
+			want: []string{"--verbose", "issue", "bugs"},
+		},
+	}
+	for _, tt := range tests {
+		got, shell, err := expandAlias(tt.args)
+		if err != nil {
+			t.Errorf("%v: unexpected error, %s", tt.args, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.want, got) || shell != tt.wantShell {
+			t.Errorf("%v: \nwant %#v %v \ngot  %#v %v", tt.args, tt.want, tt.wantShell, got, shell)
+		}
+	}
+}
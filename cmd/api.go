@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+var apiCmd = &cobra.Command{
+	Use:   "api",
+	Short: "Inspect the API of hosting service",
+}
+
+var apiRateLimitCmd = &cobra.Command{
+	Use:   "rate-limit",
+	Short: "Show the quota of API",
+	Long: `Show the quota of API.
+
+The requests failed by the rate limit are retried when the quota is reset
+within a minute, otherwise they are failed with the time of reset.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return showRateLimit(cmd.Context(), factory.Out)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(apiCmd)
+	apiCmd.AddCommand(apiRateLimitCmd)
+}
+
+func showRateLimit(ctx context.Context, w io.Writer) error {
+	_, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+
+	p, err := newProvider(ctx, pInfo)
+	if err != nil {
+		return err
+	}
+	limiter, ok := p.(provider.RateLimiter)
+	if !ok {
+		return fmt.Errorf("Not supported rate limit, '%s'", providerName(pInfo))
+	}
+	rateLimit, err := limiter.RateLimit()
+	if err != nil {
+		return fmt.Errorf("cannot get rate limit, %w", err)
+	}
+	fmt.Fprint(w, formatRateLimit(rateLimit, time.Now()))
+	return nil
+}
+
+func formatRateLimit(r *provider.RateLimit, now time.Time) string {
+	return fmt.Sprintf("Limit:     %d\nUsed:      %d\nRemaining: %d\nReset:     %s (in %s)\n",
+		r.Limit,
+		r.Used,
+		r.Remaining,
+		r.ResetAt.Local().Format("2006-01-02 15:04:05"),
+		r.ResetAt.Sub(now).Round(time.Second),
+	)
+}
@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+
+	"github.com/lighttiger2505/huc/internal/cmdutil"
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/spf13/cobra"
+)
+
+var browseCmd = &cobra.Command{
+	Use:   "browse [path[:line[-line]]]",
+	Short: "Open the repository page in browser",
+	Long: `Open the repository page in browser.
+
+With no path, open the repository home. A path is resolved relative to the git root
+at the current branch, or at --commit or --branch.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return browse(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(browseCmd)
+	browseCmd.Flags().StringP("commit", "c", "", "Open the path at the commit.")
+	browseCmd.Flags().StringP("branch", "b", "", "Open the path at the branch.")
+	browseCmd.Flags().Bool("commits", false, "Open the commits page.")
+	browseCmd.Flags().Bool("settings", false, "Open the settings page.")
+	browseCmd.Flags().Bool("wiki", false, "Open the wiki page.")
+	browseCmd.Flags().Bool("actions", false, "Open the actions page. It's the pipelines page on GitLab.")
+	browseCmd.Flags().BoolP("no-browser", "n", false, "Print the url instead of opening browser.")
+	browseCmd.Flags().BoolP("permalink", "p", false, "Pin the url to the commit SHA.")
+}
+
+type browseOption struct {
+	Commit    string
+	Branch    string
+	Commits   bool
+	Settings  bool
+	Wiki      bool
+	Actions   bool
+	NoBrowser bool
+	Permalink bool
+}
+
+func browse(cmd *cobra.Command, args []string) error {
+	cfg, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+
+	opt, err := toBrowseOption(cmd)
+	if err != nil {
+		return err
+	}
+
+	p, err := newProvider(cmd.Context(), pInfo)
+	if err != nil {
+		return err
+	}
+
+	url, err := browseURL(p, factory.GitClient(), opt, args)
+	if err != nil {
+		return err
+	}
+
+	b := factory.browser(cfg)
+	if opt.NoBrowser {
+		b.Launcher = cmdutil.BrowserPrint
+	}
+	if err := b.Open(url); err != nil {
+		return err
+	}
+	return nil
+}
+
+func toBrowseOption(cmd *cobra.Command) (*browseOption, error) {
+	flags := cmd.Flags()
+	opt := &browseOption{}
+	var err error
+	if opt.Commit, err = flags.GetString("commit"); err != nil {
+		return nil, err
+	}
+	if opt.Branch, err = flags.GetString("branch"); err != nil {
+		return nil, err
+	}
+	if opt.Commits, err = flags.GetBool("commits"); err != nil {
+		return nil, err
+	}
+	if opt.Settings, err = flags.GetBool("settings"); err != nil {
+		return nil, err
+	}
+	if opt.Wiki, err = flags.GetBool("wiki"); err != nil {
+		return nil, err
+	}
+	if opt.Actions, err = flags.GetBool("actions"); err != nil {
+		return nil, err
+	}
+	if opt.NoBrowser, err = flags.GetBool("no-browser"); err != nil {
+		return nil, err
+	}
+	if opt.Permalink, err = flags.GetBool("permalink"); err != nil {
+		return nil, err
+	}
+
+	if opt.Commit != "" && opt.Branch != "" {
+		return nil, fmt.Errorf("--commit and --branch can not be used together")
+	}
+	return opt, nil
+}
+
+func browseURL(p provider.Provider, client git.Client, opt *browseOption, args []string) (string, error) {
+	switch {
+	case opt.Settings:
+		return p.SettingsURL(), nil
+	case opt.Wiki:
+		return p.WikiURL(), nil
+	case opt.Actions:
+		return p.ActionsURL(), nil
+	}
+
+	if len(args) == 0 && !opt.Commits && opt.Commit == "" && opt.Branch == "" && !opt.Permalink {
+		return p.RepositoryURL(), nil
+	}
+
+	ref, err := browseRef(client, opt)
+	if err != nil {
+		return "", err
+	}
+	if opt.Commits {
+		return p.CommitsURL(ref), nil
+	}
+
+	var path string
+	var startLine, endLine int
+	if len(args) > 0 {
+		path, startLine, endLine, err = parsePathWithLine(args[0])
+		if err != nil {
+			return "", err
+		}
+		ctx, err := client.RepositoryContext()
+		if err != nil {
+			return "", err
+		}
+		if path, err = ctx.RelativePath(path); err != nil {
+			return "", err
+		}
+	}
+	return p.TreeURL(ref, path, startLine, endLine), nil
+}
+
+// browseRef returns the ref to browse, it's pinned to the commit SHA when permalink is enabled.
+// The commit of permalink must be pushed, the url of the local commit is not found.
+func browseRef(client git.Client, opt *browseOption) (provider.Ref, error) {
+	if opt.Commit != "" {
+		return provider.Ref{Name: opt.Commit, Commit: true}, nil
+	}
+
+	ctx, err := client.RepositoryContext()
+	if err != nil && (opt.Branch == "" || opt.Permalink) {
+		return provider.Ref{}, err
+	}
+
+	branch := opt.Branch
+	if branch == "" {
+		if ctx.Detached() {
+			return provider.Ref{Name: ctx.Head, Commit: true}, nil
+		}
+		branch = ctx.Branch
+	}
+
+	if !opt.Permalink {
+		return provider.Ref{Name: branch}, nil
+	}
+	sha := ctx.ResolveBranch(branch)
+	if sha == "" {
+		return provider.Ref{}, fmt.Errorf("Not found commit of the branch, %s", branch)
+	}
+	pushed, err := client.IsPushed(sha)
+	if err != nil {
+		return provider.Ref{}, fmt.Errorf("cannot check the commit is pushed, %s", err)
+	}
+	if !pushed {
+		return provider.Ref{}, fmt.Errorf("Not found commit %s in remote branches, push it before --permalink", sha)
+	}
+	return provider.Ref{Name: sha, Commit: true}, nil
+}
+
+var pathWithLineRe = regexp.MustCompile(`^(.*?)(?::(\d+)(?:-(\d+))?)?$`)
+
+// parsePathWithLine parses "path", "path:10" and "path:10-20".
+func parsePathWithLine(arg string) (string, int, int, error) {
+	matches := pathWithLineRe.FindStringSubmatch(arg)
+	path := matches[1]
+	if path == "" {
+		return "", 0, 0, fmt.Errorf("Invalid path, '%s'", arg)
+	}
+
+	var startLine, endLine int
+	if matches[2] != "" {
+		startLine, _ = strconv.Atoi(matches[2])
+	}
+	if matches[3] != "" {
+		endLine, _ = strconv.Atoi(matches[3])
+		if endLine < startLine {
+			return "", 0, 0, fmt.Errorf("Invalid line range, '%s'", arg)
+		}
+	}
+	return path, startLine, endLine, nil
+}
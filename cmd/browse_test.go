@@ -0,0 +1,262 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/github"
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+const (
+	pushedSHA   = "0123456789abcdef0123456789abcdef01234567"
+	unpushedSHA = "89abcdef0123456789abcdef0123456789abcdef"
+)
+
+// newBrowseGitClient returns the client of the repository on the branch, the
+// branch "main" is pushed and "local" is not pushed.
+func newBrowseGitClient(t *testing.T, branch string) (*git.MockClient, func()) {
+	dir, err := ioutil.TempDir("", "huc-browse")
+	if err != nil {
+		t.Fatal(err)
+	}
+	refs := map[string]string{"main": pushedSHA, "local": unpushedSHA}
+	for name, sha := range refs {
+		path := filepath.Join(dir, "refs", "heads", name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(sha+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	workTree, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := &git.RepositoryContext{
+		GitDir:    dir,
+		CommonDir: dir,
+		WorkTree:  workTree,
+		Branch:    branch,
+		Head:      refs[branch],
+	}
+	if branch == "" {
+		ctx.Head = pushedSHA
+	}
+	client := &git.MockClient{
+		MockRepositoryContext: func() (*git.RepositoryContext, error) {
+			return ctx, nil
+		},
+		MockIsPushed: func(sha string) (bool, error) {
+			return sha == pushedSHA, nil
+		},
+	}
+	return client, func() { os.RemoveAll(dir) }
+}
+
+func TestParsePathWithLine(t *testing.T) {
+	tests := []struct {
+		arg       string
+		path      string
+		startLine int
+		endLine   int
+		wantErr   string
+	}{
+		{arg: "main.go", path: "main.go"},
+		{arg: "cmd/root.go:10", path: "cmd/root.go", startLine: 10},
+		{arg: "cmd/root.go:10-20", path: "cmd/root.go", startLine: 10, endLine: 20},
+		{arg: "cmd/root.go:10-10", path: "cmd/root.go", startLine: 10, endLine: 10},
+		{arg: "cmd/root.go:20-10", wantErr: "Invalid line range, 'cmd/root.go:20-10'"},
+		{arg: ":10", wantErr: "Invalid path, ':10'"},
+		// The suffix not of lines is the part of path
+		{arg: "a:b.go", path: "a:b.go"},
+		{arg: "main.go:10-", path: "main.go:10-"},
+	}
+	for _, tt := range tests {
+		path, startLine, endLine, err := parsePathWithLine(tt.arg)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: error \nwant %#v \ngot  %v", tt.arg, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error, %s", tt.arg, err)
+			continue
+		}
+		got := []interface{}{path, startLine, endLine}
+		want := []interface{}{tt.path, tt.startLine, tt.endLine}
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("%s: \nwant %#v \ngot  %#v", tt.arg, want, got)
+		}
+	}
+}
+
+func TestBrowseRef(t *testing.T) {
+	tests := []struct {
+		name    string
+		branch  string
+		opt     browseOption
+		want    provider.Ref
+		wantErr string
+	}{
+		{
+			name:   "current branch",
+			branch: "main",
+			want:   provider.Ref{Name: "main"},
+		},
+		{
+			name:   "detached head",
+			branch: "",
+			want:   provider.Ref{Name: pushedSHA, Commit: true},
+		},
+		{
+			name:   "commit",
+			branch: "main",
+			opt:    browseOption{Commit: "abc1234"},
+			want:   provider.Ref{Name: "abc1234", Commit: true},
+		},
+		{
+			name:   "branch",
+			branch: "main",
+			opt:    browseOption{Branch: "develop"},
+			want:   provider.Ref{Name: "develop"},
+		},
+		{
+			name:   "permalink",
+			branch: "main",
+			opt:    browseOption{Permalink: true},
+			want:   provider.Ref{Name: pushedSHA, Commit: true},
+		},
+		{
+			name:    "permalink of unpushed commit",
+			branch:  "local",
+			opt:     browseOption{Permalink: true},
+			wantErr: "Not found commit " + unpushedSHA + " in remote branches, push it before --permalink",
+		},
+		{
+			name:    "permalink of unknown branch",
+			branch:  "main",
+			opt:     browseOption{Branch: "unknown", Permalink: true},
+			wantErr: "Not found commit of the branch, unknown",
+		},
+	}
+	for _, tt := range tests {
+		client, cleanup := newBrowseGitClient(t, tt.branch)
+		got, err := browseRef(client, &tt.opt)
+		cleanup()
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: error \nwant %#v \ngot  %v", tt.name, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error, %s", tt.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(tt.want, got) {
+			t.Errorf("%s: \nwant %#v \ngot  %#v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestBrowseRef_OutsideRepository(t *testing.T) {
+	client := &git.MockClient{
+		MockRepositoryContext: func() (*git.RepositoryContext, error) {
+			return nil, git.ErrNotGitRepository
+		},
+	}
+	// The branch is browsed without the local repository
+	got, err := browseRef(client, &browseOption{Branch: "develop"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (provider.Ref{Name: "develop"}); got != want {
+		t.Errorf("\nwant %#v \ngot  %#v", want, got)
+	}
+	if _, err := browseRef(client, &browseOption{}); !errors.Is(err, git.ErrNotGitRepository) {
+		t.Errorf("error \nwant %#v \ngot  %v", git.ErrNotGitRepository, err)
+	}
+}
+
+func TestBrowseURL(t *testing.T) {
+	p, err := github.NewProvider(context.Background(), &git.GitLabProjectInfo{
+		Domain:  "github.com",
+		Project: "octocat/hello-world",
+		Token:   "token",
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, cleanup := newBrowseGitClient(t, "main")
+	defer cleanup()
+
+	tests := []struct {
+		name    string
+		opt     browseOption
+		args    []string
+		want    string
+		wantErr string
+	}{
+		{
+			name: "repository",
+			want: "https://github.com/octocat/hello-world",
+		},
+		{
+			name: "settings",
+			opt:  browseOption{Settings: true},
+			want: "https://github.com/octocat/hello-world/settings",
+		},
+		{
+			name: "commits",
+			opt:  browseOption{Commits: true},
+			want: "https://github.com/octocat/hello-world/commits/main",
+		},
+		{
+			name: "path relative to the git root",
+			args: []string{"browse.go"},
+			want: "https://github.com/octocat/hello-world/tree/main/browse.go",
+		},
+		{
+			name: "line range",
+			args: []string{"browse.go:10-20"},
+			want: "https://github.com/octocat/hello-world/tree/main/browse.go#L10-L20",
+		},
+		{
+			name: "permalink",
+			opt:  browseOption{Permalink: true},
+			args: []string{"browse.go:10"},
+			want: "https://github.com/octocat/hello-world/tree/" + pushedSHA + "/browse.go#L10",
+		},
+		{
+			name:    "invalid line range",
+			args:    []string{"browse.go:20-10"},
+			wantErr: "Invalid line range, 'browse.go:20-10'",
+		},
+	}
+	for _, tt := range tests {
+		got, err := browseURL(p, client, &tt.opt, tt.args)
+		if tt.wantErr != "" {
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("%s: error \nwant %#v \ngot  %v", tt.name, tt.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error, %s", tt.name, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%s: \nwant %#v \ngot  %#v", tt.name, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,205 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/lighttiger2505/huc/internal/fakeserver"
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/github"
+	"github.com/lighttiger2505/huc/internal/selector"
+	"github.com/lighttiger2505/huc/internal/ui"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+var update = flag.Bool("update", false, "update the golden files of commands")
+
+func TestMain(m *testing.M) {
+	// The commands must not touch the cache of user
+	dir, err := ioutil.TempDir("", "huc-cmd")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("XDG_CACHE_HOME", dir)
+	// The commands must not open the browser of user
+	os.Setenv("BROWSER", "print")
+	os.Unsetenv("HUB_VERBOSE")
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// fakeSelector selects the items of the indices without the user.
+type fakeSelector struct {
+	indices []int
+}
+
+func (s *fakeSelector) Select(items []string, opt *selector.Option) ([]int, error) {
+	if len(s.indices) == 0 {
+		return nil, selector.ErrAbort
+	}
+	return s.indices, nil
+}
+
+// commandTest runs the command against the fake server of the fixture
+// "testdata/fixtures/<name>.json", the output and the error are compared with
+// the golden file "testdata/<name>.golden". The golden files are updated by
+// "go test ./cmd -update", and the fixtures are recorded from the real API by
+// "HUC_RECORD=1 HUC_TEST_TOKEN=<token> go test ./cmd -update".
+type commandTest struct {
+	name     string
+	domain   string
+	provider string
+	// remote is the url of git remote in the current directory, the command runs outside of repository when it's empty
+	remote   string
+	args     []string
+	selected []int
+	// answers are the answers of prompts, the prompts read the empty input when it's nil
+	answers []ui.Answer
+	// stdin is the input of command, e.g. the body of "--body-file -"
+	stdin string
+	// checkout is the files of local checkout by the path, the command runs outside of checkout when it's nil
+	checkout map[string]string
+	wantCode int
+}
+
+func (tt *commandTest) run(t *testing.T) {
+	server := fakeserver.New(t, filepath.Join("testdata", "fixtures", tt.name+".json"))
+	defer server.Close()
+
+	token := "test-token"
+	if fakeserver.Recording() {
+		if token = os.Getenv("HUC_TEST_TOKEN"); token == "" {
+			t.Fatal("HUC_TEST_TOKEN is required to record the fixtures")
+		}
+	}
+	cfg := config.NewConfig()
+	cfg.SetProfile(tt.domain, config.Profile{Token: token, Provider: tt.provider})
+
+	out := &bytes.Buffer{}
+	f := NewFactory()
+	f.In = strings.NewReader(tt.stdin)
+	f.Out = out
+	f.ErrOut = out
+	f.Config = func() (*config.Config, error) {
+		return cfg, nil
+	}
+	f.GitClient = func() git.Client {
+		return &git.MockClient{
+			MockRemoteInfos: func() ([]*git.RemoteInfo, error) {
+				return []*git.RemoteInfo{git.NewRemoteInfo("origin", tt.remote)}, nil
+			},
+			MockCurrentRemoteBranch: func() (string, error) {
+				return "main", nil
+			},
+			MockRepositoryContext: func() (*git.RepositoryContext, error) {
+				if tt.remote == "" {
+					return nil, git.ErrNotGitRepository
+				}
+				return &git.RepositoryContext{Branch: "main"}, nil
+			},
+		}
+	}
+	scripted := &ui.ScriptedUi{Answers: tt.answers}
+	if tt.answers != nil {
+		f.UI = func() ui.UI {
+			return scripted
+		}
+	}
+	checkoutDir, checkoutErr := tt.writeCheckout(t)
+	if checkoutDir != "" {
+		defer os.RemoveAll(checkoutDir)
+	}
+	f.RepositoryDir = func() (string, error) {
+		return checkoutDir, checkoutErr
+	}
+	f.Selector = func(name string) selector.Selector {
+		return &fakeSelector{indices: tt.selected}
+	}
+	f.HTTPTransport = func(profile *config.Profile) (http.RoundTripper, error) {
+		return github.NewVerboseTransport(http.DefaultTransport, server.URL, VerboseFlag), nil
+	}
+	defer func(saved *Factory) { factory = saved }(factory)
+	factory = f
+
+	resetFlags(rootCmd)
+	rootCmd.SetArgs(tt.args)
+	code := 0
+	if err := rootCmd.ExecuteContext(context.Background()); err != nil {
+		code = printError(out, err, false)
+	}
+	if code != tt.wantCode {
+		t.Errorf("%s: want exit code %d, got %d", tt.name, tt.wantCode, code)
+	}
+	if len(scripted.Unanswered()) > 0 {
+		t.Errorf("%s: unanswered prompts %#v", tt.name, scripted.Unanswered())
+	}
+
+	// The path of config file depends on the home of user
+	got := strings.Replace(out.String(), config.NewConfig().Path(), "$CONFIG", -1)
+	golden := filepath.Join("testdata", tt.name+".golden")
+	if *update {
+		if err := ioutil.WriteFile(golden, []byte(got), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := ioutil.ReadFile(golden)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != string(want) {
+		t.Errorf("%s: output \nwant %q \ngot  %q", tt.name, want, got)
+	}
+}
+
+func (tt *commandTest) writeCheckout(t *testing.T) (string, error) {
+	if tt.checkout == nil {
+		return "", fmt.Errorf("Can't read git root")
+	}
+	dir, err := ioutil.TempDir("", "huc-checkout")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for path, content := range tt.checkout {
+		path = filepath.Join(dir, filepath.FromSlash(path))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir, nil
+}
+
+// resetFlags restores the flags to the defaults, the commands are executed
+// repeatedly in the tests.
+func resetFlags(cmd *cobra.Command) {
+	reset := func(f *pflag.Flag) {
+		// The slice values append to the previous values after they are set once
+		if f.Value.Type() == "stringSlice" {
+			fs := pflag.NewFlagSet(f.Name, pflag.ContinueOnError)
+			fs.StringSlice(f.Name, nil, "")
+			f.Value = fs.Lookup(f.Name).Value
+		} else {
+			f.Value.Set(f.DefValue)
+		}
+		f.Changed = false
+	}
+	cmd.Flags().VisitAll(reset)
+	cmd.PersistentFlags().VisitAll(reset)
+	for _, c := range cmd.Commands() {
+		resetFlags(c)
+	}
+}
@@ -0,0 +1,239 @@
+package cmd
+
+import (
+	"fmt"
+	"io/ioutil"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/cmdutil"
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/lighttiger2505/huc/internal/markdown"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/selector"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+const commentEditFilename = "COMMENT_EDITMSG.md"
+
+// commentTarget is the issue or the pull request to comment on.
+type commentTarget struct {
+	// name is the kind in the messages, "issue" or "pull request"
+	name   string
+	number int
+	url    string
+	list   func(number int) ([]provider.Comment, error)
+	add    func(number int, body string) error
+	edit   func(number int, commentID string, body string) error
+}
+
+func issueCommentTarget(p provider.Provider, number int) *commentTarget {
+	return &commentTarget{
+		name:   "issue",
+		number: number,
+		url:    p.IssueURL(number),
+		list:   p.ListIssueComments,
+		add:    p.CommentIssue,
+		edit:   p.EditIssueComment,
+	}
+}
+
+func pullRequestCommentTarget(p provider.Provider, number int) *commentTarget {
+	return &commentTarget{
+		name:   "pull request",
+		number: number,
+		url:    p.PullRequestURL(number),
+		list:   p.ListPullRequestComments,
+		add:    p.CommentPullRequest,
+		edit:   p.EditPullRequestComment,
+	}
+}
+
+// commentOption is the flags of comment, the body is opened in the editor
+// when Body and BodyFile are empty.
+type commentOption struct {
+	Body string
+	// BodyFile is the file of body, "-" is stdin
+	BodyFile string
+	EditLast bool
+	ReplyTo  bool
+}
+
+func addCommentFlags(cmd *cobra.Command, kind string) {
+	cmd.Flags().StringP("body", "b", "", "Body of the comment, it's opened in the editor when empty")
+	cmd.Flags().StringP("body-file", "F", "", "Read the body from the file, \"-\" is stdin")
+	cmd.Flags().Bool("edit-last", false, fmt.Sprintf("Edit your last comment on the %s", kind))
+	cmd.Flags().Bool("reply-to", false, "Quote the comment chosen by the selector in the body")
+}
+
+func toCommentOption(flags *pflag.FlagSet) (*commentOption, error) {
+	body, err := flags.GetString("body")
+	if err != nil {
+		return nil, err
+	}
+	bodyFile, err := flags.GetString("body-file")
+	if err != nil {
+		return nil, err
+	}
+	editLast, err := flags.GetBool("edit-last")
+	if err != nil {
+		return nil, err
+	}
+	replyTo, err := flags.GetBool("reply-to")
+	if err != nil {
+		return nil, err
+	}
+	if body != "" && bodyFile != "" {
+		return nil, fmt.Errorf("Invalid flags, --body and --body-file can't be used together")
+	}
+	if editLast && replyTo {
+		return nil, fmt.Errorf("Invalid flags, --edit-last and --reply-to can't be used together")
+	}
+	return &commentOption{
+		Body:     body,
+		BodyFile: bodyFile,
+		EditLast: editLast,
+		ReplyTo:  replyTo,
+	}, nil
+}
+
+// comment adds the comment, or edits the last comment of the user by --edit-last.
+func comment(cmd *cobra.Command, cfg *config.Config, p provider.Provider, target *commentTarget, opt *commentOption) error {
+	if opt.EditLast {
+		return editLastComment(p, target, opt)
+	}
+
+	var text string
+	if opt.ReplyTo {
+		c, err := selectComment(cmd, cfg, target)
+		if err != nil {
+			return err
+		}
+		text = quoteComment(c)
+	}
+	body, err := readCommentBody(opt, text)
+	if err != nil {
+		return err
+	}
+	if opt.Body != "" || opt.BodyFile != "" {
+		body = text + body
+	}
+	if strings.TrimSpace(body) == strings.TrimSpace(text) {
+		return fmt.Errorf("Invalid body, the comment is empty")
+	}
+
+	if err := target.add(target.number, strings.TrimSpace(body)); err != nil {
+		return fmt.Errorf("cannot comment on %s #%d, %w", target.name, target.number, err)
+	}
+	fmt.Fprintln(factory.Out, target.url)
+	return nil
+}
+
+func editLastComment(p provider.Provider, target *commentTarget, opt *commentOption) error {
+	login, err := p.CurrentUser()
+	if err != nil {
+		return fmt.Errorf("cannot get current user, %w", err)
+	}
+	comments, err := target.list(target.number)
+	if err != nil {
+		return err
+	}
+	var last *provider.Comment
+	for i := range comments {
+		if comments[i].Author == login {
+			last = &comments[i]
+		}
+	}
+	if last == nil {
+		return &provider.NotFoundError{Err: fmt.Errorf("Not found your comment on %s #%d", target.name, target.number)}
+	}
+
+	body, err := readCommentBody(opt, last.Body)
+	if err != nil {
+		return err
+	}
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return fmt.Errorf("Invalid body, the comment is empty")
+	}
+	if body == strings.TrimSpace(last.Body) {
+		factory.UI().Message("The comment is not changed.")
+		return nil
+	}
+
+	if err := target.edit(target.number, last.ID, body); err != nil {
+		return fmt.Errorf("cannot edit comment on %s #%d, %w", target.name, target.number, err)
+	}
+	fmt.Fprintln(factory.Out, target.url)
+	return nil
+}
+
+// readCommentBody returns the body of --body or --body-file, or the text edited in the editor.
+func readCommentBody(opt *commentOption, text string) (string, error) {
+	switch {
+	case opt.Body != "":
+		return opt.Body, nil
+	case opt.BodyFile == "-":
+		b, err := ioutil.ReadAll(factory.In)
+		if err != nil {
+			return "", fmt.Errorf("cannot read body from stdin, %s", err)
+		}
+		return string(b), nil
+	case opt.BodyFile != "":
+		b, err := ioutil.ReadFile(opt.BodyFile)
+		if err != nil {
+			return "", fmt.Errorf("cannot read body file, %s", err)
+		}
+		return string(b), nil
+	}
+	return factory.UI().Editor(commentEditFilename, text)
+}
+
+func selectComment(cmd *cobra.Command, cfg *config.Config, target *commentTarget) (*provider.Comment, error) {
+	sel, err := newSelector(cmd, cfg)
+	if err != nil {
+		return nil, err
+	}
+	comments, err := target.list(target.number)
+	if err != nil {
+		return nil, err
+	}
+	if len(comments) == 0 {
+		return nil, &provider.NotFoundError{Err: fmt.Errorf("Not found comment on %s #%d", target.name, target.number)}
+	}
+	items := make([]string, len(comments))
+	for i, c := range comments {
+		items[i] = fmt.Sprintf("%s %s %s", c.PublishedAt.Format("2006-01-02"), c.Author, firstLine(c.Body))
+	}
+	indices, err := sel.Select(items, &selector.Option{
+		Preview: func(i, w, h int) string {
+			if i == -1 {
+				return ""
+			}
+			return markdown.Render(comments[i].Body, cmdutil.PreviewWidth(w), false)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &comments[indices[0]], nil
+}
+
+// quoteComment returns the comment quoted as markdown with the mention of author.
+func quoteComment(c *provider.Comment) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "> @%s wrote:\n", c.Author)
+	for _, line := range strings.Split(strings.TrimSpace(c.Body), "\n") {
+		b.WriteString(strings.TrimRight("> "+line, " ") + "\n")
+	}
+	b.WriteString("\n")
+	return b.String()
+}
+
+func firstLine(s string) string {
+	s = strings.TrimSpace(s)
+	if i := strings.Index(s, "\n"); i >= 0 {
+		return strings.TrimSpace(s[:i])
+	}
+	return s
+}
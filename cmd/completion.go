@@ -0,0 +1,247 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/cache"
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish>",
+	Short: "Generate the shell completion script",
+	Long: `Generate the shell completion script.
+
+Issue numbers, pull request numbers and release tags are completed from the
+hosting service, they are cached for a minute.
+
+  bash: source <(huc completion bash)
+  zsh:  huc completion zsh > "${fpath[1]}/_huc"
+  fish: huc completion fish > ~/.config/fish/completions/huc.fish`,
+	Args:      cobra.ExactValidArgs(1),
+	ValidArgs: []string{"bash", "zsh", "fish"},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return genCompletion(factory.Out, args[0])
+	},
+}
+
+// completionCacheTTL is short because the candidates are changed by the other commands, e.g. closing issue
+const completionCacheTTL = time.Minute
+
+func init() {
+	rootCmd.AddCommand(completionCmd)
+
+	issueShowCmd.ValidArgsFunction = completeIssues
+	issueBrowseCmd.ValidArgsFunction = completeIssues
+	issueCommentCmd.ValidArgsFunction = completeIssues
+	pullRequestShowCmd.ValidArgsFunction = completePullRequests
+	pullRequestBrowseCmd.ValidArgsFunction = completePullRequests
+	pullRequestCommentCmd.ValidArgsFunction = completePullRequests
+	releaseCmd.ValidArgsFunction = completeReleases
+}
+
+func genCompletion(w io.Writer, shell string) error {
+	switch shell {
+	case "bash":
+		return rootCmd.GenBashCompletion(w)
+	case "zsh":
+		_, err := fmt.Fprintf(w, zshCompletion, rootCmd.Name())
+		return err
+	case "fish":
+		return rootCmd.GenFishCompletion(w, true)
+	}
+	return fmt.Errorf("Invalid shell, '%s'", shell)
+}
+
+// isCompletionRequest returns true when the shell requests the candidates, the aliases and the extensions are not expanded.
+func isCompletionRequest(args []string) bool {
+	return len(args) > 0 && (args[0] == cobra.ShellCompRequestCmd || args[0] == cobra.ShellCompNoDescRequestCmd)
+}
+
+func completeIssues(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeFromProvider("issues", func(p provider.Provider) ([]string, error) {
+		issues, err := p.ListIssue(&provider.ListIssueOption{
+			Num:       100,
+			Sort:      provider.SortUpdatedAt,
+			Direction: provider.DirectionDesc,
+			States:    provider.StateOpen,
+		})
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]string, len(issues))
+		for i := range issues {
+			candidates[i] = strconv.Itoa(issues[i].Number) + "\t" + issues[i].Title
+		}
+		return candidates, nil
+	})
+}
+
+func completePullRequests(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeFromProvider("pull_requests", func(p provider.Provider) ([]string, error) {
+		pullRequests, err := p.ListPullRequest(&provider.ListPullRequestOption{
+			Num:       100,
+			Sort:      provider.SortUpdatedAt,
+			Direction: provider.DirectionDesc,
+			States:    provider.StateOpen,
+		})
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]string, len(pullRequests))
+		for i := range pullRequests {
+			candidates[i] = strconv.Itoa(pullRequests[i].Number) + "\t" + pullRequests[i].Title
+		}
+		return candidates, nil
+	})
+}
+
+func completeReleases(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) != 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return completeFromProvider("releases", func(p provider.Provider) ([]string, error) {
+		releases, err := p.ListRelease(&provider.ListReleaseOption{
+			Num:       100,
+			Sort:      provider.SortCreatedAt,
+			Direction: provider.DirectionDesc,
+		})
+		if err != nil {
+			return nil, err
+		}
+		candidates := make([]string, len(releases))
+		for i := range releases {
+			candidates[i] = releases[i].TagName + "\t" + releases[i].Name
+		}
+		return candidates, nil
+	})
+}
+
+func completeProfiles(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	cfg, err := factory.Config()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	profiles := make([]string, 0, len(cfg.Profiles))
+	for name, profile := range cfg.Profiles {
+		if profile.Provider != "" {
+			name += "\t" + profile.Provider
+		}
+		profiles = append(profiles, name)
+	}
+	sort.Strings(profiles)
+	return profiles, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeFromProvider returns the candidates of the repository. They are
+// cached because completion is requested on every <TAB>. It never asks
+// anything, the output is read by the shell.
+func completeFromProvider(kind string, list func(p provider.Provider) ([]string, error)) ([]string, cobra.ShellCompDirective) {
+	cfg, err := factory.Config()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	silent := &ui.BasicUi{
+		Reader:         strings.NewReader(""),
+		Writer:         ioutil.Discard,
+		ErrorWriter:    ioutil.Discard,
+		NonInteractive: true,
+	}
+	remoteCollecter := git.NewRemoteCollecter(silent, cfg, factory.GitClient())
+	pInfo, err := remoteCollecter.CollectTarget(
+		"",
+		ProfileFlag,
+	)
+	if err != nil || pInfo.Token == "" {
+		return nil, cobra.ShellCompDirectiveError
+	}
+
+	c := cache.New(filepath.Join(cache.DefaultDir(), "completion"), completionCacheTTL)
+	key := strings.Join([]string{kind, pInfo.Domain, pInfo.Project}, ":")
+	var candidates []string
+	if c.Get(key, &candidates) {
+		return candidates, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	p, err := newProvider(context.Background(), pInfo)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	if candidates, err = list(p); err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	// Completion works without cache
+	_ = c.Set(key, candidates)
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
+
+// zshCompletion requests the candidates with descriptions by "huc __complete",
+// the same as the scripts of bash and fish generated by cobra.
+const zshCompletion = `#compdef %[1]s
+
+_%[1]s() {
+    local -a lines completions
+    local directive comp flagPrefix
+    local tab=$'\t'
+
+    # Complete the value of --flag=value with the prefix of flag
+    if [[ "${words[CURRENT]}" == -*=* ]]; then
+        flagPrefix="${words[CURRENT]%%%%=*}="
+    fi
+
+    # The last line is the directive, e.g. ":4"
+    lines=("${(@f)$(${words[1]} __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}")
+    directive=${lines[-1]#:}
+    lines=("${(@)lines[1,-2]}")
+
+    # ShellCompDirectiveError
+    if (( directive & 1 )); then
+        return 1
+    fi
+
+    for comp in "${(@)lines}"; do
+        [[ -z "$comp" ]] && continue
+        comp=${comp//:/\\:}
+        comp=${comp/$tab/:}
+        completions+=("${flagPrefix}${comp}")
+    done
+
+    if (( ${#completions} > 0 )); then
+        # ShellCompDirectiveNoSpace
+        if (( directive & 2 )); then
+            _describe -t completions 'completions' completions -S ''
+        else
+            _describe -t completions 'completions' completions
+        fi
+        return
+    fi
+
+    # ShellCompDirectiveNoFileComp
+    if (( ! (directive & 4) )); then
+        _files
+    fi
+}
+
+if [[ "$funcstack[1]" = "_%[1]s" ]]; then
+    _%[1]s "$@"
+else
+    compdef _%[1]s %[1]s
+fi
+`
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/retry"
+	"github.com/lighttiger2505/huc/internal/selector"
+	"github.com/lighttiger2505/huc/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// The exit codes of huc, scripts can branch on the type of failure.
+const (
+	ExitError     = 1
+	ExitUsage     = 2
+	ExitNotFound  = 3
+	ExitAuth      = 4
+	ExitRateLimit = 5
+	ExitNetwork   = 6
+	ExitAbort     = 130
+)
+
+const exitCodesHelp = `Exit status:
+  0    success
+  1    error
+  2    invalid flags, or the prompt is required with --yes
+  3    the issue, pull request or repository is not found
+  4    authentication failed, the token is invalid or lacks the scopes
+  5    API rate limit exceeded
+  6    network error, e.g. timeout or the proxy is not reachable
+  130  aborted by the user`
+
+// usageError is the error of invalid flags.
+type usageError struct {
+	cmd *cobra.Command
+	err error
+}
+
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+func flagError(cmd *cobra.Command, err error) error {
+	return &usageError{cmd: cmd, err: err}
+}
+
+// describeError returns the exit code, the message and the suggestion of err.
+func describeError(err error) (int, string, string) {
+	var usageErr *usageError
+	var notFoundErr *provider.NotFoundError
+	var authErr *provider.AuthError
+	var rateLimitErr *retry.RateLimitError
+	var urlErr *url.Error
+
+	switch {
+	case errors.Is(err, selector.ErrAbort), errors.Is(err, ui.ErrInterrupted), errors.Is(err, context.Canceled):
+		return ExitAbort, "", ""
+	case errors.Is(err, ui.ErrNonInteractive):
+		return ExitUsage, err.Error(), "Pass the value by the flags or the config, the prompts are disabled by --yes."
+	case errors.As(err, &usageErr):
+		return ExitUsage, err.Error(), fmt.Sprintf("Run '%s --help' for usage.", usageErr.cmd.CommandPath())
+	case errors.As(err, &notFoundErr):
+		return ExitNotFound, notFoundErr.Error(), "Check the number and the repository, the private resources are not found without the permission."
+	case errors.As(err, &authErr):
+		hint := fmt.Sprintf("Check the token of profile in %s", config.NewConfig().Path())
+		if len(authErr.Scopes) > 0 {
+			hint += fmt.Sprintf(", it requires the scopes: %s", strings.Join(authErr.Scopes, ", "))
+		}
+		return ExitAuth, authErr.Error(), hint + "."
+	case errors.As(err, &rateLimitErr):
+		return ExitRateLimit, rateLimitErr.Error(), "Wait for the reset, or reuse the responses by --cache."
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &urlErr) && urlErr.Timeout():
+		return ExitNetwork, err.Error(), "Increase --timeout, or check the network connection."
+	case errors.As(err, &urlErr):
+		// The other errors of the HTTP clients are the failures of connection
+		return ExitNetwork, err.Error(), "Check the network connection and the proxy, e.g. HTTPS_PROXY and NO_PROXY."
+	}
+	return ExitError, err.Error(), ""
+}
+
+// printError prints the message and the suggestion of err, the wrapped causes are printed when debug is true.
+func printError(w io.Writer, err error, debug bool) int {
+	code, msg, hint := describeError(err)
+	if msg != "" {
+		fmt.Fprintln(w, msg)
+	}
+	if hint != "" {
+		fmt.Fprintln(w, "hint: "+hint)
+	}
+	if debug {
+		for e := err; e != nil; e = errors.Unwrap(e) {
+			fmt.Fprintf(w, "caused by %T: %s\n", e, e)
+		}
+	}
+	return code
+}
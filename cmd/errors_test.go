@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/retry"
+	"github.com/lighttiger2505/huc/internal/selector"
+	"github.com/lighttiger2505/huc/internal/ui"
+)
+
+func TestDescribeError(t *testing.T) {
+	dialErr := &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	tests := []struct {
+		name     string
+		err      error
+		wantCode int
+		wantMsg  string
+	}{
+		{
+			name:     "error",
+			err:      errors.New("Invalid args, please input issue number"),
+			wantCode: ExitError,
+			wantMsg:  "Invalid args, please input issue number",
+		},
+		{
+			name:     "usage",
+			err:      flagError(issueCmd, errors.New("unknown flag: --foo")),
+			wantCode: ExitUsage,
+			wantMsg:  "unknown flag: --foo",
+		},
+		{
+			name:     "non-interactive",
+			err:      fmt.Errorf("cannot read private token, %w", fmt.Errorf("%w, Please enter GitLab private token:", ui.ErrNonInteractive)),
+			wantCode: ExitUsage,
+			wantMsg:  "cannot read private token, cannot ask in non-interactive mode, Please enter GitLab private token:",
+		},
+		{
+			name:     "not found in url error",
+			err:      &url.Error{Op: "Post", URL: "https://api.github.com/graphql", Err: &provider.NotFoundError{Err: errors.New("Could not resolve to an Issue")}},
+			wantCode: ExitNotFound,
+			wantMsg:  "Could not resolve to an Issue",
+		},
+		{
+			name:     "auth",
+			err:      &provider.AuthError{Scopes: []string{"api"}, Err: errors.New("GitLab API error, 401 Unauthorized")},
+			wantCode: ExitAuth,
+			wantMsg:  "GitLab API error, 401 Unauthorized",
+		},
+		{
+			name:     "wrapped rate limit",
+			err:      fmt.Errorf("cannot get rate limit, %w", &url.Error{Op: "Get", URL: "https://gitlab.com", Err: &retry.RateLimitError{}}),
+			wantCode: ExitRateLimit,
+			wantMsg:  "API rate limit exceeded",
+		},
+		{
+			name:     "network",
+			err:      &url.Error{Op: "Get", URL: "https://gitlab.com", Err: dialErr},
+			wantCode: ExitNetwork,
+			wantMsg:  `Get "https://gitlab.com": dial tcp: connection refused`,
+		},
+		{
+			name:     "timeout",
+			err:      &url.Error{Op: "Get", URL: "https://gitlab.com", Err: context.DeadlineExceeded},
+			wantCode: ExitNetwork,
+			wantMsg:  `Get "https://gitlab.com": context deadline exceeded`,
+		},
+		{
+			name:     "abort",
+			err:      selector.ErrAbort,
+			wantCode: ExitAbort,
+		},
+		{
+			name:     "canceled",
+			err:      &url.Error{Op: "Get", URL: "https://gitlab.com", Err: context.Canceled},
+			wantCode: ExitAbort,
+		},
+	}
+	for _, tt := range tests {
+		code, msg, _ := describeError(tt.err)
+		if code != tt.wantCode || msg != tt.wantMsg {
+			t.Errorf("%s: \nwant %d %q \ngot  %d %q", tt.name, tt.wantCode, tt.wantMsg, code, msg)
+		}
+	}
+}
+
+func TestPrintError(t *testing.T) {
+	err := fmt.Errorf("cannot get rate limit, %w", &provider.NotFoundError{Err: errors.New("404 Not Found")})
+
+	out := &bytes.Buffer{}
+	if code := printError(out, err, true); code != ExitNotFound {
+		t.Errorf("want exit code %d, got %d", ExitNotFound, code)
+	}
+	want := `404 Not Found
+hint: Check the number and the repository, the private resources are not found without the permission.
+caused by *fmt.wrapError: cannot get rate limit, 404 Not Found
+caused by *provider.NotFoundError: 404 Not Found
+caused by *errors.errorString: 404 Not Found
+`
+	if out.String() != want {
+		t.Errorf("\nwant %q \ngot  %q", want, out.String())
+	}
+}
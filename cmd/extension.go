@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/extension"
+	"github.com/spf13/cobra"
+)
+
+var extensionCmd = &cobra.Command{
+	Use:   "extension",
+	Short: "Manage extensions",
+	Long: `Manage extensions.
+
+An extension is the executable named "huc-<name>" that runs as "huc <name>".
+Extensions are found in ~/.local/share/huc/extensions and $PATH. The
+repository context is passed by the environment variables, HUC_HOST, HUC_REPO,
+HUC_TOKEN, HUC_BRANCH and HUC_PROVIDER.`,
+	Aliases: []string{"ext"},
+}
+
+var extensionInstallCmd = &cobra.Command{
+	Use:   "install <path|url|owner/repo>",
+	Short: "Install the extension from the local directory or git repository",
+	Long: `Install the extension from the local directory or git repository.
+
+The local directory is linked, the changes in it are used without upgrade. The
+name of directory or repository must start with "huc-" and it must have the
+executable of same name.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ext, err := extension.NewManager().Install(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(factory.Out, "Installed extension, %s\n", ext.Name)
+		return nil
+	},
+}
+
+var extensionListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List installed extensions",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		exts, err := extension.NewManager().List()
+		if err != nil {
+			return err
+		}
+		for _, ext := range exts {
+			fmt.Fprintf(factory.Out, "%s\t%s\t%s\n", ext.Name, ext.Kind, ext.Source)
+		}
+		return nil
+	},
+}
+
+var extensionRemoveCmd = &cobra.Command{
+	Use:   "remove <name>",
+	Short: "Remove the installed extension",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return extension.NewManager().Remove(args[0])
+	},
+}
+
+var extensionUpgradeCmd = &cobra.Command{
+	Use:   "upgrade [<name>]",
+	Short: "Upgrade the extension installed from git repository",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return upgradeExtension(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(extensionCmd)
+	extensionCmd.AddCommand(extensionInstallCmd)
+	extensionCmd.AddCommand(extensionListCmd)
+	extensionCmd.AddCommand(extensionRemoveCmd)
+	extensionCmd.AddCommand(extensionUpgradeCmd)
+	extensionUpgradeCmd.Flags().Bool("all", false, "Upgrade all extensions installed from git repository.")
+}
+
+func upgradeExtension(cmd *cobra.Command, args []string) error {
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return err
+	}
+	if all == (len(args) == 1) {
+		return fmt.Errorf("Specify the name of extension or --all")
+	}
+
+	m := extension.NewManager()
+	if !all {
+		return m.Upgrade(args[0])
+	}
+
+	exts, err := m.List()
+	if err != nil {
+		return err
+	}
+	for _, ext := range exts {
+		if ext.Kind != extension.KindGit {
+			continue
+		}
+		fmt.Fprintf(factory.Out, "Upgrading extension, %s\n", ext.Name)
+		if err := m.Upgrade(ext.Name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// findExtension returns the executable of extension and the number of the
+// global flags before it, e.g. 2 of "--profile work myext", when the first
+// argument after the global flags is not the subcommand of huc.
+func findExtension(args []string) (string, int, bool) {
+	n := leadingFlags(args)
+	if n == len(args) || strings.HasPrefix(args[n], "-") || isBuiltinCommand(args[n:]) {
+		return "", 0, false
+	}
+	path, ok := extension.NewManager().Find(args[n])
+	return path, n, ok
+}
+
+// runExtension runs the extension with the repository context. The global
+// flags, e.g. --profile, are applied to the context. The context is empty
+// when it's not resolved, e.g. outside of repository.
+func runExtension(path string, flags, args []string) error {
+	if err := rootCmd.PersistentFlags().Parse(flags); err != nil {
+		return flagError(rootCmd, err)
+	}
+	cmd := exec.Command(path, args...)
+	cmd.Env = append(os.Environ(), extensionEnv()...)
+	cmd.Stdin = factory.In
+	cmd.Stdout = factory.Out
+	cmd.Stderr = factory.ErrOut
+	return cmd.Run()
+}
+
+func extensionEnv() []string {
+	pInfo, err := factory.peekTarget()
+	if err != nil {
+		return []string{}
+	}
+	return []string{
+		extension.EnvHost + "=" + pInfo.Domain,
+		extension.EnvRepo + "=" + pInfo.Project,
+		extension.EnvToken + "=" + pInfo.Token,
+		extension.EnvBranch + "=" + pInfo.CurrentBranch,
+		extension.EnvProvider + "=" + providerName(pInfo),
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/lighttiger2505/huc/internal/extension"
+	"github.com/lighttiger2505/huc/internal/git"
+)
+
+func TestFindExtension(t *testing.T) {
+	dir, err := ioutil.TempDir("", "huc-extension")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := ioutil.WriteFile(filepath.Join(dir, "huc-myext"), []byte("#!/bin/sh\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	defer func(path, data string) {
+		os.Setenv("PATH", path)
+		os.Setenv("XDG_DATA_HOME", data)
+	}(os.Getenv("PATH"), os.Getenv("XDG_DATA_HOME"))
+	os.Setenv("PATH", dir)
+	os.Setenv("XDG_DATA_HOME", dir)
+
+	tests := []struct {
+		args   []string
+		wantN  int
+		wantOK bool
+	}{
+		{args: []string{"myext", "arg"}, wantN: 0, wantOK: true},
+		{args: []string{"--profile", "work", "myext"}, wantN: 2, wantOK: true},
+		{args: []string{"-v", "--profile=work", "myext"}, wantN: 2, wantOK: true},
+		{args: []string{"--profile", "work", "issue"}, wantOK: false},
+		{args: []string{"--profile", "myext"}, wantOK: false},
+		{args: []string{"unknown"}, wantOK: false},
+	}
+	for _, tt := range tests {
+		path, n, ok := findExtension(tt.args)
+		if ok != tt.wantOK || n != tt.wantN {
+			t.Errorf("%v: \nwant %d %v \ngot  %d %v", tt.args, tt.wantN, tt.wantOK, n, ok)
+		}
+		if ok && path != filepath.Join(dir, "huc-myext") {
+			t.Errorf("%v: unexpected path, %s", tt.args, path)
+		}
+	}
+}
+
+func TestExtensionEnv_Profile(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.SetProfile("work.example.com", config.Profile{Token: "work-token"})
+	f := NewFactory()
+	f.Config = func() (*config.Config, error) {
+		return cfg, nil
+	}
+	f.GitClient = func() git.Client {
+		return &git.MockClient{
+			MockRepositoryContext: func() (*git.RepositoryContext, error) {
+				return nil, git.ErrNotGitRepository
+			},
+		}
+	}
+	defer func(saved *Factory, profile string) {
+		factory = saved
+		ProfileFlag = profile
+	}(factory, ProfileFlag)
+	factory = f
+
+	if err := rootCmd.PersistentFlags().Parse([]string{"--profile", "work.example.com"}); err != nil {
+		t.Fatal(err)
+	}
+	env := extensionEnv()
+	got := []string{env[0], env[2]}
+	want := []string{
+		extension.EnvHost + "=work.example.com",
+		extension.EnvToken + "=work-token",
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("extensionEnv() \nwant %#v \ngot  %#v", want, got)
+	}
+}
@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	"github.com/lighttiger2505/huc/internal/cmdutil"
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/github"
+	"github.com/lighttiger2505/huc/internal/selector"
+	"github.com/lighttiger2505/huc/internal/ui"
+)
+
+// Factory creates the dependencies of commands. The commands get them from
+// the factory instead of creating them directly, so that the tests replace
+// them with the fakes.
+type Factory struct {
+	In     io.Reader
+	Out    io.Writer
+	ErrOut io.Writer
+
+	Config    func() (*config.Config, error)
+	GitClient func() git.Client
+	// RepositoryDir returns the root of the local checkout
+	RepositoryDir func() (string, error)
+	UI            func() ui.UI
+	Selector      func(name string) selector.Selector
+	// HTTPTransport returns the base transport of the API clients for the profile
+	HTTPTransport func(profile *config.Profile) (http.RoundTripper, error)
+}
+
+var factory = NewFactory()
+
+// NewFactory returns the factory of the real dependencies. The API requests
+// are sent to $HUB_TEST_HOST instead of the hosting service when it's set.
+func NewFactory() *Factory {
+	f := &Factory{
+		In:            os.Stdin,
+		Out:           os.Stdout,
+		ErrOut:        os.Stderr,
+		Config:        config.GetConfig,
+		GitClient:     git.NewGitClient,
+		RepositoryDir: git.Root,
+		Selector:      selector.New,
+	}
+	f.UI = func() ui.UI {
+		return &ui.BasicUi{
+			Reader:         f.In,
+			Writer:         f.Out,
+			ErrorWriter:    f.ErrOut,
+			Selector:       f.Selector(""),
+			EditText:       cmdutil.EditText,
+			NonInteractive: YesFlag,
+		}
+	}
+	f.HTTPTransport = func(profile *config.Profile) (http.RoundTripper, error) {
+		base, err := newHTTPTransport(profile)
+		if err != nil {
+			return nil, err
+		}
+		return github.NewVerboseTransport(base, os.Getenv("HUB_TEST_HOST"), VerboseFlag), nil
+	}
+	return f
+}
+
+// collectTarget loads the config and returns the repository of the current
+// directory, or the default project of --profile.
+func (f *Factory) collectTarget() (*config.Config, *git.GitLabProjectInfo, error) {
+	cfg, err := f.Config()
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot load config, %s", err)
+	}
+	remoteCollecter := git.NewRemoteCollecter(f.UI(), cfg, f.GitClient())
+
+	pInfo, err := remoteCollecter.CollectTarget(
+		"",
+		ProfileFlag,
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, pInfo, nil
+}
+
+// peekTarget returns the repository like collectTarget, but it never asks
+// the token and never saves the config.
+func (f *Factory) peekTarget() (*git.GitLabProjectInfo, error) {
+	cfg, err := f.Config()
+	if err != nil {
+		return nil, fmt.Errorf("cannot load config, %s", err)
+	}
+	remoteCollecter := &git.RemoteCollecter{
+		UI: &ui.BasicUi{
+			Reader:         f.In,
+			Writer:         f.Out,
+			ErrorWriter:    f.ErrOut,
+			NonInteractive: true,
+		},
+		Cfg:       cfg,
+		GitClient: f.GitClient(),
+		ReadOnly:  true,
+	}
+	return remoteCollecter.CollectTarget("", ProfileFlag)
+}
+
+func (f *Factory) pager(cfg *config.Config) *cmdutil.Pager {
+	p := cmdutil.NewPager(cfg)
+	p.Out = f.Out
+	p.Err = f.ErrOut
+	return p
+}
+
+func (f *Factory) browser(cfg *config.Config) *cmdutil.Browser {
+	b := cmdutil.NewBrowser(cfg)
+	b.Out = f.Out
+	b.Terminal = f.ErrOut
+	return b
+}
@@ -3,14 +3,11 @@ package cmd
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
-	"github.com/ktr0731/go-fuzzyfinder"
+	"github.com/lighttiger2505/huc/internal/cmdutil"
 	"github.com/lighttiger2505/huc/internal/config"
-	"github.com/lighttiger2505/huc/internal/git"
-	"github.com/lighttiger2505/huc/internal/github"
-	"github.com/lighttiger2505/huc/internal/ui"
-	"github.com/shurcooL/githubv4"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/selector"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -31,8 +28,9 @@ to quickly create a Cobra application.`,
 }
 
 const (
-	IssueActionBrowse = "browse"
-	IssueActionShow   = "show"
+	IssueActionBrowse  = "browse"
+	IssueActionShow    = "show"
+	IssueActionComment = "comment"
 )
 
 func init() {
@@ -42,31 +40,31 @@ func init() {
 	issueCmd.Flags().StringP("sort", "", "CREATED_AT", "What to sort results by. Can be either COMMENTS, CREATED_AT or UPDATED_AT")
 	issueCmd.Flags().StringP("states", "", "OPEN", "Indicates the state of the issues to display. OPEN or CLOSED")
 	issueCmd.Flags().StringP("labels", "", "", "A list of comma separated label names.")
-	issueCmd.Flags().StringP("action", "", "browse", "Action to the selected issue. browse, show")
+	issueCmd.PersistentFlags().Bool("raw", false, "Show the body as raw markdown.")
+	issueCmd.Flags().StringP("action", "", "browse", "Action to the selected issue. browse, show, comment or the name of actions in config")
+	issueCmd.Flags().Duration("cache", 0, "Use the responses cached within the duration, e.g. 10m. The expired responses are revalidated by ETag.")
 }
 
 func findIssue(cmd *cobra.Command, args []string) error {
-	cfg, err := config.GetConfig()
+	cfg, pInfo, err := factory.collectTarget()
 	if err != nil {
-		return fmt.Errorf("cannot load config, %s", err)
+		return err
 	}
-	remoteCollecter := git.NewRemoteCollecter(ui.NewBasicUi(), cfg, git.NewGitClient())
 
-	pInfo, err := remoteCollecter.CollectTarget(
-		"",
-		"",
-	)
+	actionFlag, err := cmd.Flags().GetString("action")
 	if err != nil {
 		return err
 	}
-
-	actionFlag, err := cmd.Flags().GetString("action")
+	raw, err := cmd.Flags().GetBool("raw")
 	if err != nil {
 		return err
 	}
 
+	var action config.Action
 	if !isValidIssueAction(actionFlag) {
-		return fmt.Errorf("Invalid action, '%s'", actionFlag)
+		if action, err = customAction(cfg, actionFlag); err != nil {
+			return err
+		}
 	}
 
 	opt, err := toListProjectIssueOption(cmd.Flags())
@@ -74,44 +72,68 @@ func findIssue(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	spProject := strings.Split(pInfo.Project, "/")
-	issues, err := github.ListIssue(pInfo.Token, spProject[0], spProject[1], opt)
+	sel, err := newSelector(cmd, cfg)
 	if err != nil {
 		return err
 	}
 
-	indices, err := fuzzyfinder.FindMulti(
-		issues,
-		func(i int) string {
-			return strconv.Itoa(int(issues[i].Number)) + " " + string(issues[i].Title)
-		},
-		fuzzyfinder.WithPreviewWindow(func(i, w, h int) string {
+	cacheTTL, err := cmd.Flags().GetDuration("cache")
+	if err != nil {
+		return err
+	}
+	p, err := newCachedProvider(cmd.Context(), pInfo, cacheTTL)
+	if err != nil {
+		return err
+	}
+	issues, err := p.ListIssue(opt)
+	if err != nil {
+		return err
+	}
+	items := make([]string, len(issues))
+	for i := range issues {
+		items[i] = strconv.Itoa(issues[i].Number) + " " + issues[i].Title
+	}
+	indices, err := sel.Select(items, &selector.Option{
+		Multi: true,
+		Preview: func(i, w, h int) string {
 			if i == -1 {
 				return ""
 			}
-			return issues[i].ToString()
-		}),
-	)
-
+			if raw {
+				return issues[i].ToRawString()
+			}
+			return issues[i].Render(cmdutil.PreviewWidth(w), false)
+		},
+	})
 	if err != nil {
-		if err.Error() == fuzzyfinder.ErrAbort.Error() {
-			return nil
-		}
 		return err
 	}
 
 	switch actionFlag {
-	case IssueActionBrowse:
+	case "", IssueActionBrowse:
 		for _, index := range indices {
-			issue := issues[int(index)]
-			if err := browseIssue(pInfo, &issue); err != nil {
+			issue := issues[index]
+			if err := browseIssue(factory.browser(cfg), p, &issue); err != nil {
 				return err
 			}
 		}
 	case IssueActionShow:
-		issue := issues[int(indices[0])]
-		if err := showIssue(&issue); err != nil {
-			return nil
+		issue := issues[indices[0]]
+		if err := showIssue(factory.pager(cfg), &issue, raw); err != nil {
+			return err
+		}
+	case IssueActionComment:
+		target := issueCommentTarget(p, issues[indices[0]].Number)
+		if err := comment(cmd, cfg, p, target, &commentOption{}); err != nil {
+			return err
+		}
+	default:
+		actionItems := make([]cmdutil.ActionItem, len(indices))
+		for i, index := range indices {
+			actionItems[i] = issueActionItem(p, &issues[index])
+		}
+		if err := runCustomAction(actionFlag, action, pInfo, actionItems); err != nil {
+			return err
 		}
 	}
 
@@ -119,13 +141,13 @@ func findIssue(cmd *cobra.Command, args []string) error {
 }
 
 func isValidIssueAction(val string) bool {
-	if val == "" || val == IssueActionBrowse || val == IssueActionShow {
+	if val == "" || val == IssueActionBrowse || val == IssueActionShow || val == IssueActionComment {
 		return true
 	}
 	return false
 }
 
-func toListProjectIssueOption(flags *pflag.FlagSet) (*github.ListProjectIssueOption, error) {
+func toListProjectIssueOption(flags *pflag.FlagSet) (*provider.ListIssueOption, error) {
 	num, err := flags.GetInt("num")
 	if err != nil {
 		return nil, err
@@ -135,12 +157,8 @@ func toListProjectIssueOption(flags *pflag.FlagSet) (*github.ListProjectIssueOpt
 	if err != nil {
 		return nil, err
 	}
-	var directionOpt githubv4.OrderDirection
 	switch direction {
-	case "ASC":
-		directionOpt = githubv4.OrderDirectionAsc
-	case "DESC":
-		directionOpt = githubv4.OrderDirectionDesc
+	case provider.DirectionAsc, provider.DirectionDesc:
 	default:
 		return nil, fmt.Errorf("Invalid issue order option, %s", direction)
 	}
@@ -149,14 +167,8 @@ func toListProjectIssueOption(flags *pflag.FlagSet) (*github.ListProjectIssueOpt
 	if err != nil {
 		return nil, err
 	}
-	var sortOpt githubv4.IssueOrderField
 	switch sort {
-	case "COMMENTS":
-		sortOpt = githubv4.IssueOrderFieldComments
-	case "CREATED_AT":
-		sortOpt = githubv4.IssueOrderFieldCreatedAt
-	case "UPDATED_AT":
-		sortOpt = githubv4.IssueOrderFieldUpdatedAt
+	case provider.SortComments, provider.SortCreatedAt, provider.SortUpdatedAt:
 	default:
 		return nil, fmt.Errorf("Invalid issue sort option, %s", sort)
 	}
@@ -165,20 +177,16 @@ func toListProjectIssueOption(flags *pflag.FlagSet) (*github.ListProjectIssueOpt
 	if err != nil {
 		return nil, err
 	}
-	var statesOpt githubv4.IssueState
 	switch states {
-	case "OPEN":
-		statesOpt = githubv4.IssueStateOpen
-	case "CLOSED":
-		statesOpt = githubv4.IssueStateClosed
+	case provider.StateOpen, provider.StateClosed:
 	default:
 		return nil, fmt.Errorf("Invalid issue sort option, %s", states)
 	}
 
-	return &github.ListProjectIssueOption{
+	return &provider.ListIssueOption{
 		Num:       num,
-		Sort:      sortOpt,
-		Direction: directionOpt,
-		States:    statesOpt,
+		Sort:      sort,
+		Direction: direction,
+		States:    states,
 	}, nil
 }
@@ -1,15 +1,8 @@
 package cmd
 
 import (
-	"fmt"
-	"strconv"
-	"strings"
-
 	"github.com/lighttiger2505/huc/internal/cmdutil"
-	"github.com/lighttiger2505/huc/internal/config"
-	"github.com/lighttiger2505/huc/internal/git"
-	"github.com/lighttiger2505/huc/internal/github"
-	"github.com/lighttiger2505/huc/internal/ui"
+	"github.com/lighttiger2505/huc/internal/provider"
 	"github.com/spf13/cobra"
 )
 
@@ -32,41 +25,33 @@ func init() {
 }
 
 func browseIssueMain(cmd *cobra.Command, args []string) error {
-	cfg, err := config.GetConfig()
+	cfg, pInfo, err := factory.collectTarget()
 	if err != nil {
-		return fmt.Errorf("cannot load config, %s", err)
+		return err
 	}
-	remoteCollecter := git.NewRemoteCollecter(ui.NewBasicUi(), cfg, git.NewGitClient())
 
-	pInfo, err := remoteCollecter.CollectTarget(
-		"",
-		"",
-	)
+	number, err := getIssueNumber(args)
 	if err != nil {
 		return err
 	}
 
-	number, err := getIssueNumber(args)
+	p, err := newProvider(cmd.Context(), pInfo)
 	if err != nil {
 		return err
 	}
-
-	spProject := strings.Split(pInfo.Project, "/")
-	issue, err := github.ShowIssue(pInfo.Token, spProject[0], spProject[1], number)
+	issue, err := p.ShowIssue(number)
 	if err != nil {
 		return err
 	}
 
-	if err := browseIssue(pInfo, issue); err != nil {
+	if err := browseIssue(factory.browser(cfg), p, issue); err != nil {
 		return err
 	}
 	return nil
 }
 
-func browseIssue(pInfo *git.GitLabProjectInfo, issue *github.Issue) error {
-	b := &cmdutil.Browser{}
-	selectedIssueNumber := int(issue.Number)
-	url := strings.Join([]string{pInfo.SubpageUrl("issues"), strconv.Itoa(selectedIssueNumber)}, "/")
+func browseIssue(b cmdutil.URLOpener, p provider.Provider, issue *provider.Issue) error {
+	url := p.IssueURL(issue.Number)
 
 	if err := b.Open(url); err != nil {
 		return err
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var issueCommentCmd = &cobra.Command{
+	Use:   "comment <number>",
+	Short: "Comment on the issue",
+	Long: `Comment on the issue by --body, --body-file or the editor.
+
+--edit-last edits your last comment on the issue, and --reply-to quotes the
+comment chosen by the selector.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return commentIssueMain(cmd, args)
+	},
+}
+
+func init() {
+	issueCmd.AddCommand(issueCommentCmd)
+	addCommentFlags(issueCommentCmd, "issue")
+}
+
+func commentIssueMain(cmd *cobra.Command, args []string) error {
+	cfg, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+
+	number, err := getIssueNumber(args)
+	if err != nil {
+		return err
+	}
+	opt, err := toCommentOption(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	p, err := newProvider(cmd.Context(), pInfo)
+	if err != nil {
+		return err
+	}
+	return comment(cmd, cfg, p, issueCommentTarget(p, number), opt)
+}
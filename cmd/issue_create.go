@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lighttiger2505/huc/internal/issuetemplate"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/selector"
+	"github.com/spf13/cobra"
+)
+
+var issueCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create the issue",
+	Long: `Create the issue by the templates in .github/ISSUE_TEMPLATE of repository.
+
+The template is chosen when there are some templates. The YAML issue form is
+asked field by field, and the markdown template is opened in the editor. The
+labels and the assignees of template are added to the issue.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return createIssueMain(cmd, args)
+	},
+}
+
+func init() {
+	issueCmd.AddCommand(issueCreateCmd)
+	addCreateFlags(issueCreateCmd, "issue")
+}
+
+func createIssueMain(cmd *cobra.Command, args []string) error {
+	_, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+	p, err := newProvider(cmd.Context(), pInfo)
+	if err != nil {
+		return err
+	}
+
+	u := factory.UI()
+	content, err := askContent(cmd, u, p, issuetemplate.Issue, "ISSUE_EDITMSG.md")
+	if err != nil {
+		return err
+	}
+	ok, err := u.Confirm(fmt.Sprintf("Create the issue '%s'?", content.Title), true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return selector.ErrAbort
+	}
+
+	issue, err := p.CreateIssue(&provider.CreateIssueOption{
+		Title: content.Title,
+		Body:  content.Body,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create issue, %w", err)
+	}
+	if len(content.Labels) > 0 {
+		if err := p.LabelIssue(issue.Number, content.Labels); err != nil {
+			return fmt.Errorf("cannot add labels to issue #%d, %w", issue.Number, err)
+		}
+	}
+	if len(content.Assignees) > 0 {
+		if err := p.AssignIssue(issue.Number, content.Assignees); err != nil {
+			return fmt.Errorf("cannot assign issue #%d, %w", issue.Number, err)
+		}
+	}
+	fmt.Fprintln(factory.Out, p.IssueURL(issue.Number))
+	return nil
+}
@@ -3,13 +3,9 @@ package cmd
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/lighttiger2505/huc/internal/cmdutil"
-	"github.com/lighttiger2505/huc/internal/config"
-	"github.com/lighttiger2505/huc/internal/git"
-	"github.com/lighttiger2505/huc/internal/github"
-	"github.com/lighttiger2505/huc/internal/ui"
+	"github.com/lighttiger2505/huc/internal/provider"
 	"github.com/spf13/cobra"
 )
 
@@ -32,47 +28,49 @@ func init() {
 }
 
 func showIssueMain(cmd *cobra.Command, args []string) error {
-	cfg, err := config.GetConfig()
+	cfg, pInfo, err := factory.collectTarget()
 	if err != nil {
-		return fmt.Errorf("cannot load config, %s", err)
+		return err
 	}
-	remoteCollecter := git.NewRemoteCollecter(ui.NewBasicUi(), cfg, git.NewGitClient())
 
-	pInfo, err := remoteCollecter.CollectTarget(
-		"",
-		"",
-	)
+	number, err := getIssueNumber(args)
 	if err != nil {
 		return err
 	}
 
-	number, err := getIssueNumber(args)
+	p, err := newProvider(cmd.Context(), pInfo)
 	if err != nil {
 		return err
 	}
-
-	spProject := strings.Split(pInfo.Project, "/")
-	issue, err := github.ShowIssue(pInfo.Token, spProject[0], spProject[1], number)
+	issue, err := p.ShowIssue(number)
 	if err != nil {
 		return err
 	}
 
-	if err := showIssue(issue); err != nil {
-		return nil
+	raw, err := cmd.Flags().GetBool("raw")
+	if err != nil {
+		return err
+	}
+	if err := showIssue(factory.pager(cfg), issue, raw); err != nil {
+		return err
 	}
 	return nil
 }
 
-func showIssue(issue *github.Issue) error {
-	contents := issue.ToString()
-	if !cmdutil.IsOverScreeenRow(contents) {
-		fmt.Println(issue.ToString())
-		return nil
+func showIssue(pager *cmdutil.Pager, issue *provider.Issue, raw bool) error {
+	contents := issue.ToRawString()
+	if !raw {
+		contents = issue.Render(cmdutil.TerminalWidth(), cmdutil.IsTerminal())
+	}
+	w, err := pager.Start()
+	if err != nil {
+		return err
 	}
-	if err := cmdutil.ShowPager(contents); err != nil {
+	if _, err := fmt.Fprintln(w, contents); err != nil {
+		pager.Close()
 		return err
 	}
-	return nil
+	return pager.Close()
 }
 
 func getIssueNumber(args []string) (int, error) {
@@ -0,0 +1,213 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/ui"
+)
+
+const bugReportForm = `name: Bug report
+description: File a bug report
+title: "[BUG] "
+labels: ["bug"]
+body:
+  - type: markdown
+    attributes:
+      value: Thanks for taking the time to fill out this bug report!
+  - type: input
+    id: version
+    attributes:
+      label: Version
+      description: What version of huc are you running?
+    validations:
+      required: true
+  - type: textarea
+    id: what-happened
+    attributes:
+      label: What happened?
+      placeholder: Tell us what you see!
+  - type: dropdown
+    id: os
+    attributes:
+      label: OS
+      options:
+        - Linux
+        - macOS
+        - Windows
+  - type: checkboxes
+    id: terms
+    attributes:
+      label: Code of Conduct
+      options:
+        - label: I agree to follow the Code of Conduct
+          required: true
+`
+
+const featureRequestTemplate = `---
+name: Feature request
+about: Suggest an idea
+labels: enhancement
+---
+**Describe the solution you'd like**
+`
+
+func TestIssueCommand(t *testing.T) {
+	tests := []commandTest{
+		{
+			name:     "issue_show_github",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"issue", "show", "1", "--raw"},
+		},
+		{
+			name:     "issue_show_github_not_found",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"issue", "show", "999", "--raw"},
+			wantCode: ExitNotFound,
+		},
+		{
+			name:     "issue_select_github",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"issue", "--action", "show", "--raw"},
+			selected: []int{1},
+		},
+		{
+			// The empty action is the default action, browse
+			name:     "issue_select_github_empty_action",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"issue", "--action", ""},
+			selected: []int{1},
+		},
+		{
+			name:     "issue_select_github_yes",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"issue", "--action", "show", "--raw", "--yes"},
+			wantCode: ExitUsage,
+		},
+		{
+			name:     "issue_show_gitlab",
+			domain:   "gitlab.com",
+			provider: "gitlab",
+			remote:   "git@gitlab.com:group/project.git",
+			args:     []string{"issue", "show", "4", "--raw"},
+		},
+		{
+			name:     "issue_show_gitlab_unauthorized",
+			domain:   "gitlab.com",
+			provider: "gitlab",
+			remote:   "git@gitlab.com:group/project.git",
+			args:     []string{"issue", "show", "4", "--raw"},
+			wantCode: ExitAuth,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+func TestIssueCreateCommand(t *testing.T) {
+	tests := []commandTest{
+		{
+			name:     "issue_create_github_form",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"issue", "create"},
+			checkout: map[string]string{
+				".github/ISSUE_TEMPLATE/bug_report.yml":     bugReportForm,
+				".github/ISSUE_TEMPLATE/config.yml":         "blank_issues_enabled: false\n",
+				".github/ISSUE_TEMPLATE/feature_request.md": featureRequestTemplate,
+			},
+			answers: []ui.Answer{
+				{Prompt: ui.PromptSelect, Query: "Choose a template", Indices: []int{0}},
+				{Prompt: ui.PromptAsk, Query: "Title:", Text: "Crash on start"},
+				{Prompt: ui.PromptAsk, Query: "Version:", Text: ""},
+				{Prompt: ui.PromptAsk, Query: "Version:", Text: "v0.3.0"},
+				{Prompt: ui.PromptEditor, Query: "WHAT-HAPPENED.md", Text: "It crashes after the update."},
+				{Prompt: ui.PromptSelect, Query: "OS", Indices: []int{1}},
+				{Prompt: ui.PromptMultiSelect, Query: "Code of Conduct", Indices: []int{0}},
+				{Prompt: ui.PromptConfirm, Query: "Create the issue '[BUG] Crash on start'?", Yes: true},
+			},
+		},
+		{
+			name:     "issue_create_gitlab_yes",
+			domain:   "gitlab.com",
+			provider: "gitlab",
+			remote:   "git@gitlab.com:group/project.git",
+			args:     []string{"issue", "create", "--title", "Update the README", "--body", "The install section is outdated.", "--label", "docs", "--yes"},
+		},
+		{
+			name:     "issue_create_yes_without_title",
+			domain:   "gitlab.com",
+			provider: "gitlab",
+			remote:   "git@gitlab.com:group/project.git",
+			args:     []string{"issue", "create", "--body", "The install section is outdated.", "--yes"},
+			wantCode: ExitUsage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+func TestIssueCommentCommand(t *testing.T) {
+	tests := []commandTest{
+		{
+			name:     "issue_comment_github_body_file",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"issue", "comment", "1", "--body-file", "-"},
+			stdin:    "Thanks for the report!\n",
+		},
+		{
+			name:     "issue_comment_github_edit_last",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"issue", "comment", "1", "--edit-last"},
+			answers: []ui.Answer{
+				{Prompt: ui.PromptEditor, Query: "COMMENT_EDITMSG.md", Text: "It's fixed in v0.3.1, please try it."},
+			},
+		},
+		{
+			// The last comment of user is on the second page
+			name:     "issue_comment_gitlab_edit_last",
+			domain:   "gitlab.com",
+			provider: "gitlab",
+			remote:   "git@gitlab.com:group/project.git",
+			args:     []string{"issue", "comment", "5", "--edit-last", "--body", "It's fixed in v0.3.2, please try it."},
+		},
+		{
+			name:     "issue_select_gitea_comment",
+			domain:   "gitea.com",
+			provider: "gitea",
+			remote:   "git@gitea.com:owner/repo.git",
+			args:     []string{"issue", "--action", "comment"},
+			selected: []int{0},
+			answers: []ui.Answer{
+				{Prompt: ui.PromptEditor, Query: "COMMENT_EDITMSG.md", Text: "I'll take this one."},
+			},
+		},
+		{
+			name:     "issue_comment_yes_without_body",
+			domain:   "gitlab.com",
+			provider: "gitlab",
+			remote:   "git@gitlab.com:group/project.git",
+			args:     []string{"issue", "comment", "4", "--yes"},
+			wantCode: ExitUsage,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
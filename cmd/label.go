@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/labelsync"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/selector"
+	"github.com/spf13/cobra"
+)
+
+// defaultLabelColor is the color of the label created without --color, it's same as GitHub.
+const defaultLabelColor = "ededed"
+
+var labelCmd = &cobra.Command{
+	Use:   "label",
+	Short: "Manage the labels of repository",
+	Long: `Manage the labels of repository.
+
+The labels are synced with the manifest by "label sync", it's applied to all
+repositories of the organization by --org.`,
+	Example: `  huc label list
+  huc label create triage --color fbca04 --description "Needs triage"
+  huc label edit triage --name needs-triage
+  huc label sync .github/labels.yml --prune --dry-run`,
+}
+
+var labelListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List the labels",
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return listLabelMain(cmd, args)
+	},
+}
+
+var labelCreateCmd = &cobra.Command{
+	Use:   "create <name>",
+	Short: "Create the label",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return createLabelMain(cmd, args)
+	},
+}
+
+var labelEditCmd = &cobra.Command{
+	Use:   "edit <name>",
+	Short: "Edit the name, the color or the description of the label",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return editLabelMain(cmd, args)
+	},
+}
+
+var labelDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Delete the label, it's removed from all issues and pull requests",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return deleteLabelMain(cmd, args)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(labelCmd)
+	labelCmd.AddCommand(labelListCmd)
+	labelCmd.AddCommand(labelCreateCmd)
+	labelCmd.AddCommand(labelEditCmd)
+	labelCmd.AddCommand(labelDeleteCmd)
+
+	labelCreateCmd.Flags().StringP("color", "c", defaultLabelColor, "Color of the label in hex code, e.g. d73a4a")
+	labelCreateCmd.Flags().StringP("description", "d", "", "Description of the label")
+	labelEditCmd.Flags().String("name", "", "New name of the label")
+	labelEditCmd.Flags().StringP("color", "c", "", "Color of the label in hex code, e.g. d73a4a")
+	labelEditCmd.Flags().StringP("description", "d", "", "Description of the label")
+}
+
+func listLabelMain(cmd *cobra.Command, args []string) error {
+	cfg, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+	p, err := newProvider(cmd.Context(), pInfo)
+	if err != nil {
+		return err
+	}
+	labels, err := p.ListLabels()
+	if err != nil {
+		return err
+	}
+
+	pager := factory.pager(cfg)
+	w, err := pager.Start()
+	if err != nil {
+		return err
+	}
+	for _, l := range labels {
+		if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", l.Name, l.Color, l.Description); err != nil {
+			pager.Close()
+			return err
+		}
+	}
+	return pager.Close()
+}
+
+func createLabelMain(cmd *cobra.Command, args []string) error {
+	_, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+	color, err := cmd.Flags().GetString("color")
+	if err != nil {
+		return err
+	}
+	if !labelsync.ValidColor(color) {
+		return flagError(cmd, fmt.Errorf("Invalid color, '%s'. Please enter hex code, e.g. d73a4a", color))
+	}
+	description, err := cmd.Flags().GetString("description")
+	if err != nil {
+		return err
+	}
+
+	p, err := newProvider(cmd.Context(), pInfo)
+	if err != nil {
+		return err
+	}
+	label := &provider.Label{
+		Name:        strings.TrimSpace(args[0]),
+		Color:       labelsync.NormalizeColor(color),
+		Description: description,
+	}
+	if err := p.CreateLabel(label); err != nil {
+		return fmt.Errorf("cannot create label %s, %w", label.Name, err)
+	}
+	return nil
+}
+
+func editLabelMain(cmd *cobra.Command, args []string) error {
+	_, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+	flags := cmd.Flags()
+	if !flags.Changed("name") && !flags.Changed("color") && !flags.Changed("description") {
+		return flagError(cmd, fmt.Errorf("Invalid flags, --name, --color or --description is required"))
+	}
+
+	p, err := newProvider(cmd.Context(), pInfo)
+	if err != nil {
+		return err
+	}
+	current, err := findLabel(p, args[0])
+	if err != nil {
+		return err
+	}
+
+	label := *current
+	if flags.Changed("name") {
+		if label.Name, err = flags.GetString("name"); err != nil {
+			return err
+		}
+		label.Name = strings.TrimSpace(label.Name)
+	}
+	if flags.Changed("color") {
+		color, err := flags.GetString("color")
+		if err != nil {
+			return err
+		}
+		if !labelsync.ValidColor(color) {
+			return flagError(cmd, fmt.Errorf("Invalid color, '%s'. Please enter hex code, e.g. d73a4a", color))
+		}
+		label.Color = labelsync.NormalizeColor(color)
+	}
+	if flags.Changed("description") {
+		if label.Description, err = flags.GetString("description"); err != nil {
+			return err
+		}
+	}
+
+	if err := p.UpdateLabel(current.Name, &label); err != nil {
+		return fmt.Errorf("cannot edit label %s, %w", current.Name, err)
+	}
+	return nil
+}
+
+func deleteLabelMain(cmd *cobra.Command, args []string) error {
+	_, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+	p, err := newProvider(cmd.Context(), pInfo)
+	if err != nil {
+		return err
+	}
+	label, err := findLabel(p, args[0])
+	if err != nil {
+		return err
+	}
+
+	ok, err := factory.UI().Confirm(fmt.Sprintf("Delete the label '%s' of %s?", label.Name, pInfo.Project), false)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return selector.ErrAbort
+	}
+	if err := p.DeleteLabel(label.Name); err != nil {
+		return fmt.Errorf("cannot delete label %s, %w", label.Name, err)
+	}
+	return nil
+}
+
+// findLabel returns the label of the name ignoring case, the labels of hosting services are case-insensitive.
+func findLabel(p provider.Provider, name string) (*provider.Label, error) {
+	labels, err := p.ListLabels()
+	if err != nil {
+		return nil, err
+	}
+	for i := range labels {
+		if strings.EqualFold(labels[i].Name, name) {
+			return &labels[i], nil
+		}
+	}
+	return nil, &provider.NotFoundError{Err: fmt.Errorf("Not found label, %s", name)}
+}
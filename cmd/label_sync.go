@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/labelsync"
+	"github.com/lighttiger2505/huc/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+var labelSyncCmd = &cobra.Command{
+	Use:   "sync <manifest>",
+	Short: "Sync the labels with the manifest",
+	Long: `Sync the labels with the manifest, the YAML list of labels.
+
+  - name: bug
+    color: d73a4a
+    description: Something isn't working
+    aliases: [defect, "type: bug"]
+
+The labels are matched by the name ignoring case, and the label of the aliases
+is renamed to the name. The labels not in the manifest are kept unless --prune
+is specified, the deletion is confirmed for each repository.
+
+The changes are printed as diff, "+" is created, "~" is updated and "-" is
+deleted. --dry-run prints the changes without applying them.`,
+	Example: `  huc label sync .github/labels.yml --dry-run
+  huc label sync .github/labels.yml --org my-org --prune --yes`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return syncLabelMain(cmd, args)
+	},
+}
+
+func init() {
+	labelCmd.AddCommand(labelSyncCmd)
+	labelSyncCmd.Flags().Bool("dry-run", false, "Print the changes without applying them")
+	labelSyncCmd.Flags().Bool("prune", false, "Delete the labels not in the manifest")
+	labelSyncCmd.Flags().String("org", "", "Sync all repositories of the organization, or the group of GitLab, except archived ones")
+}
+
+func syncLabelMain(cmd *cobra.Command, args []string) error {
+	b, err := ioutil.ReadFile(args[0])
+	if err != nil {
+		return fmt.Errorf("cannot read label manifest, %s", err)
+	}
+	manifest, err := labelsync.Parse(b)
+	if err != nil {
+		return err
+	}
+
+	flags := cmd.Flags()
+	dryRun, err := flags.GetBool("dry-run")
+	if err != nil {
+		return err
+	}
+	prune, err := flags.GetBool("prune")
+	if err != nil {
+		return err
+	}
+	org, err := flags.GetString("org")
+	if err != nil {
+		return err
+	}
+
+	cfg, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+	repos := []string{pInfo.Project}
+	if org != "" {
+		if repos, err = listRepositories(cmd.Context(), pInfo, org); err != nil {
+			return fmt.Errorf("cannot list repositories of %s, %w", org, err)
+		}
+		if len(repos) == 0 {
+			return fmt.Errorf("Not found repository in %s", org)
+		}
+	}
+
+	// The changes are paged by --dry-run, the pager can't be used with the prompts
+	var out io.Writer = factory.Out
+	if dryRun {
+		pager := factory.pager(cfg)
+		if out, err = pager.Start(); err != nil {
+			return err
+		}
+		defer pager.Close()
+	}
+
+	u := factory.UI()
+	failed := []string{}
+	for _, repo := range repos {
+		repoInfo := *pInfo
+		repoInfo.Project = repo
+		err := syncLabels(cmd, out, u, &repoInfo, manifest, prune, dryRun)
+		if err == nil {
+			continue
+		}
+		if len(repos) == 1 {
+			return err
+		}
+		// The other repositories are synced, the labels of an organization drift independently
+		u.Error(fmt.Sprintf("%s: %s", repo, err))
+		failed = append(failed, repo)
+	}
+
+	if len(failed) == 0 {
+		return nil
+	}
+	return fmt.Errorf("cannot sync labels of %d repositories, %s", len(failed), strings.Join(failed, ", "))
+}
+
+// syncLabels prints the changes of the repository to out and applies them unless dryRun.
+func syncLabels(cmd *cobra.Command, out io.Writer, u ui.UI, pInfo *git.GitLabProjectInfo, manifest []labelsync.Label, prune, dryRun bool) error {
+	p, err := newProvider(cmd.Context(), pInfo)
+	if err != nil {
+		return err
+	}
+	current, err := p.ListLabels()
+	if err != nil {
+		return err
+	}
+	changes := labelsync.Plan(manifest, current, prune)
+
+	fmt.Fprintln(out, pInfo.Project)
+	if len(changes) == 0 {
+		fmt.Fprintln(out, "  No changes")
+		return nil
+	}
+	deletes := 0
+	for _, c := range changes {
+		fmt.Fprintf(out, "  %s\n", c.String())
+		if c.Kind == labelsync.Delete {
+			deletes++
+		}
+	}
+	if dryRun {
+		return nil
+	}
+
+	if deletes > 0 {
+		ok, err := u.Confirm(fmt.Sprintf("Delete %d labels of %s?", deletes, pInfo.Project), false)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			u.Message(fmt.Sprintf("Skipped deleting the labels of %s.", pInfo.Project))
+			// The deletions are the last of the changes
+			changes = changes[:len(changes)-deletes]
+		}
+	}
+	return labelsync.Apply(p, changes)
+}
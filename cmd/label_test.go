@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/ui"
+)
+
+func TestLabelCommand(t *testing.T) {
+	tests := []commandTest{
+		{
+			name:     "label_list_gitea",
+			domain:   "gitea.com",
+			provider: "gitea",
+			remote:   "git@gitea.com:owner/repo.git",
+			args:     []string{"label", "list"},
+		},
+		{
+			name:     "label_edit_github",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"label", "edit", "Good First Issue", "--color", "#7057FF"},
+		},
+		{
+			name:     "label_create_invalid_color",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"label", "create", "triage", "--color", "yellow"},
+			wantCode: ExitUsage,
+		},
+		{
+			name:     "label_delete_github_declined",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"label", "delete", "wontfix"},
+			answers: []ui.Answer{
+				{Prompt: ui.PromptConfirm, Query: "Delete the label 'wontfix' of octocat/hello-world?", Yes: false},
+			},
+			wantCode: ExitAbort,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+func TestLabelSyncCommand(t *testing.T) {
+	tests := []commandTest{
+		{
+			name:     "label_sync_github_dry_run",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"label", "sync", "testdata/labels.yml", "--prune", "--dry-run"},
+		},
+		{
+			// The labels are created and updated, the deletion is declined
+			name:     "label_sync_github_prune_declined",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"label", "sync", "testdata/labels.yml", "--prune"},
+			answers: []ui.Answer{
+				{Prompt: ui.PromptConfirm, Query: "Delete 2 labels of octocat/hello-world?", Yes: false},
+			},
+		},
+		{
+			name:     "label_sync_gitlab_org",
+			domain:   "gitlab.com",
+			provider: "gitlab",
+			remote:   "git@gitlab.com:group/project.git",
+			args:     []string{"label", "sync", "testdata/labels.yml", "--org", "group", "--prune", "--yes"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/lighttiger2505/huc/internal/selector"
+	"github.com/spf13/cobra"
+)
+
+var previewCmd = &cobra.Command{
+	Use:   "preview <index|line>",
+	Short: "Print the preview of the item for the external selector",
+	Long: `Print the preview of the item for the external selector.
+
+It's used in the preview command of external selector, e.g.
+
+  selector: fzf --multi --preview 'huc preview {n}'`,
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir := os.Getenv(selector.PreviewDirEnv)
+		if dir == "" {
+			return fmt.Errorf("%s is not set, preview is available only in the selector", selector.PreviewDirEnv)
+		}
+		contents, err := selector.ReadPreview(dir, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Fprintln(factory.Out, contents)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(previewCmd)
+}
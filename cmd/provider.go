@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/gitea"
+	"github.com/lighttiger2505/huc/internal/github"
+	"github.com/lighttiger2505/huc/internal/gitlab"
+	"github.com/lighttiger2505/huc/internal/httpcache"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/retry"
+)
+
+// newProvider returns the implementation of the hosting service selected by "provider" of profile.
+// The API requests are canceled by ctx.
+func newProvider(ctx context.Context, pInfo *git.GitLabProjectInfo) (provider.Provider, error) {
+	return newCachedProvider(ctx, pInfo, 0)
+}
+
+// newCachedProvider returns the provider that uses the cached responses within ttl.
+// The cache is cleared by the requests that change the resources even if ttl is 0.
+func newCachedProvider(ctx context.Context, pInfo *git.GitLabProjectInfo, ttl time.Duration) (provider.Provider, error) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	httpClient, err := newHTTPClient(pInfo, ttl)
+	if err != nil {
+		return nil, err
+	}
+	switch providerName(pInfo) {
+	case provider.GitHub:
+		return github.NewProvider(ctx, pInfo, httpClient)
+	case provider.GitLab:
+		return gitlab.NewProvider(ctx, pInfo, httpClient), nil
+	case provider.Gitea, provider.Forgejo:
+		return gitea.NewProvider(ctx, pInfo, httpClient)
+	}
+	return nil, fmt.Errorf("Invalid provider, '%s'", pInfo.Profile.Provider)
+}
+
+// listRepositories returns the full names of the repositories of the
+// organization, or the group of GitLab, on the host of pInfo.
+func listRepositories(ctx context.Context, pInfo *git.GitLabProjectInfo, org string) ([]string, error) {
+	httpClient, err := newHTTPClient(pInfo, 0)
+	if err != nil {
+		return nil, err
+	}
+	switch providerName(pInfo) {
+	case provider.GitHub:
+		return github.ListRepositories(ctx, pInfo, httpClient, org)
+	case provider.GitLab:
+		return gitlab.ListRepositories(ctx, pInfo, httpClient, org)
+	case provider.Gitea, provider.Forgejo:
+		return gitea.ListRepositories(ctx, pInfo, httpClient, org)
+	}
+	return nil, fmt.Errorf("Invalid provider, '%s'", pInfo.Profile.Provider)
+}
+
+// newHTTPClient returns the client of the API requests for the profile of
+// pInfo, the responses are cached within ttl and the failed requests are retried.
+// The warning is written to stderr when the quota of API runs low.
+func newHTTPClient(pInfo *git.GitLabProjectInfo, ttl time.Duration) (*http.Client, error) {
+	base, err := factory.HTTPTransport(pInfo.Profile)
+	if err != nil {
+		return nil, err
+	}
+	retryTransport := retry.NewTransport(base)
+	retryTransport.Warn = factory.ErrOut
+	return &http.Client{
+		Transport: httpcache.NewTransport(retryTransport, ttl),
+		Timeout:   TimeoutFlag,
+	}, nil
+}
+
+// newHTTPTransport returns the transport connected through the proxy or the unix socket of profile.
+func newHTTPTransport(profile *config.Profile) (*http.Transport, error) {
+	if profile == nil {
+		return provider.NewHTTPTransport(nil, ""), nil
+	}
+	proxy, err := provider.ParseProxy(profile.Proxy)
+	if err != nil {
+		return nil, err
+	}
+	return provider.NewHTTPTransport(proxy, os.ExpandEnv(profile.UnixSocket)), nil
+}
+
+func providerName(pInfo *git.GitLabProjectInfo) string {
+	if pInfo.Profile != nil && pInfo.Profile.Provider != "" {
+		return pInfo.Profile.Provider
+	}
+	if strings.HasPrefix(pInfo.Domain, "gitlab") {
+		return provider.GitLab
+	}
+	return provider.GitHub
+}
@@ -3,14 +3,11 @@ package cmd
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
-	"github.com/ktr0731/go-fuzzyfinder"
+	"github.com/lighttiger2505/huc/internal/cmdutil"
 	"github.com/lighttiger2505/huc/internal/config"
-	"github.com/lighttiger2505/huc/internal/git"
-	"github.com/lighttiger2505/huc/internal/github"
-	"github.com/lighttiger2505/huc/internal/ui"
-	"github.com/shurcooL/githubv4"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/selector"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
@@ -37,36 +34,37 @@ func init() {
 	pullRequestCmd.Flags().StringP("sort", "", "CREATED_AT", "What to sort results by. Can be either COMMENTS, CREATED_AT or UPDATED_AT")
 	pullRequestCmd.Flags().StringP("states", "", "OPEN", "Indicates the state of the pull requests to display. OPEN or CLOSED, MERGED")
 	pullRequestCmd.Flags().StringP("labels", "", "", "A list of comma separated label names.")
-	pullRequestCmd.Flags().StringP("action", "", "browse", "Action to the selected pull request. browse, show")
+	pullRequestCmd.PersistentFlags().Bool("raw", false, "Show the body as raw markdown.")
+	pullRequestCmd.Flags().StringP("action", "", "browse", "Action to the selected pull request. browse, show, comment or the name of actions in config")
+	pullRequestCmd.Flags().Duration("cache", 0, "Use the responses cached within the duration, e.g. 10m. The expired responses are revalidated by ETag.")
 }
 
 const (
-	PullRequestActionBrowse = "browse"
-	PullRequestActionShow   = "show"
+	PullRequestActionBrowse  = "browse"
+	PullRequestActionShow    = "show"
+	PullRequestActionComment = "comment"
 )
 
 func findPullRequest(cmd *cobra.Command, args []string) error {
-	cfg, err := config.GetConfig()
+	cfg, pInfo, err := factory.collectTarget()
 	if err != nil {
-		return fmt.Errorf("cannot load config, %s", err)
+		return err
 	}
-	remoteCollecter := git.NewRemoteCollecter(ui.NewBasicUi(), cfg, git.NewGitClient())
 
-	pInfo, err := remoteCollecter.CollectTarget(
-		"",
-		"",
-	)
+	actionFlag, err := cmd.Flags().GetString("action")
 	if err != nil {
 		return err
 	}
-
-	actionFlag, err := cmd.Flags().GetString("action")
+	raw, err := cmd.Flags().GetBool("raw")
 	if err != nil {
 		return err
 	}
 
+	var action config.Action
 	if !isValidPullRequestAction(actionFlag) {
-		return fmt.Errorf("Invalid action, '%s'", actionFlag)
+		if action, err = customAction(cfg, actionFlag); err != nil {
+			return err
+		}
 	}
 
 	opt, err := toListProjectPullReqeustOption(cmd.Flags())
@@ -74,43 +72,67 @@ func findPullRequest(cmd *cobra.Command, args []string) error {
 		return err
 	}
 
-	spProject := strings.Split(pInfo.Project, "/")
-	pullRequests, err := github.ListPullRequest(pInfo.Token, spProject[0], spProject[1], opt)
+	sel, err := newSelector(cmd, cfg)
 	if err != nil {
 		return err
 	}
 
-	indices, err := fuzzyfinder.FindMulti(
-		pullRequests,
-		func(i int) string {
-			return strconv.Itoa(int(pullRequests[i].Number)) + " " + string(pullRequests[i].Title)
-		},
-		fuzzyfinder.WithPreviewWindow(func(i, w, h int) string {
+	cacheTTL, err := cmd.Flags().GetDuration("cache")
+	if err != nil {
+		return err
+	}
+	p, err := newCachedProvider(cmd.Context(), pInfo, cacheTTL)
+	if err != nil {
+		return err
+	}
+	pullRequests, err := p.ListPullRequest(opt)
+	if err != nil {
+		return err
+	}
+	items := make([]string, len(pullRequests))
+	for i := range pullRequests {
+		items[i] = strconv.Itoa(pullRequests[i].Number) + " " + pullRequests[i].Title
+	}
+	indices, err := sel.Select(items, &selector.Option{
+		Multi: true,
+		Preview: func(i, w, h int) string {
 			if i == -1 {
 				return ""
 			}
-			return pullRequests[i].ToString()
-		}),
-	)
-
+			if raw {
+				return pullRequests[i].ToRawString()
+			}
+			return pullRequests[i].Render(cmdutil.PreviewWidth(w), false)
+		},
+	})
 	if err != nil {
-		if err.Error() == fuzzyfinder.ErrAbort.Error() {
-			return nil
-		}
 		return err
 	}
 
 	switch actionFlag {
-	case PullRequestActionBrowse:
+	case "", PullRequestActionBrowse:
 		for _, index := range indices {
-			pullRequest := pullRequests[int(index)]
-			if err := browsePullRequest(pInfo, &pullRequest); err != nil {
+			pullRequest := pullRequests[index]
+			if err := browsePullRequest(factory.browser(cfg), p, &pullRequest); err != nil {
 				return err
 			}
 		}
 	case PullRequestActionShow:
-		pullRequest := pullRequests[int(indices[0])]
-		if err := showPullRequest(&pullRequest); err != nil {
+		pullRequest := pullRequests[indices[0]]
+		if err := showPullRequest(factory.pager(cfg), &pullRequest, raw); err != nil {
+			return err
+		}
+	case PullRequestActionComment:
+		target := pullRequestCommentTarget(p, pullRequests[indices[0]].Number)
+		if err := comment(cmd, cfg, p, target, &commentOption{}); err != nil {
+			return err
+		}
+	default:
+		actionItems := make([]cmdutil.ActionItem, len(indices))
+		for i, index := range indices {
+			actionItems[i] = pullRequestActionItem(p, &pullRequests[index])
+		}
+		if err := runCustomAction(actionFlag, action, pInfo, actionItems); err != nil {
 			return err
 		}
 	}
@@ -119,13 +141,13 @@ func findPullRequest(cmd *cobra.Command, args []string) error {
 }
 
 func isValidPullRequestAction(val string) bool {
-	if val == "" || val == PullRequestActionBrowse || val == PullRequestActionShow {
+	if val == "" || val == PullRequestActionBrowse || val == PullRequestActionShow || val == PullRequestActionComment {
 		return true
 	}
 	return false
 }
 
-func toListProjectPullReqeustOption(flags *pflag.FlagSet) (*github.ListProjectPullRequestOption, error) {
+func toListProjectPullReqeustOption(flags *pflag.FlagSet) (*provider.ListPullRequestOption, error) {
 	num, err := flags.GetInt("num")
 	if err != nil {
 		return nil, err
@@ -135,12 +157,8 @@ func toListProjectPullReqeustOption(flags *pflag.FlagSet) (*github.ListProjectPu
 	if err != nil {
 		return nil, err
 	}
-	var directionOpt githubv4.OrderDirection
 	switch direction {
-	case "ASC":
-		directionOpt = githubv4.OrderDirectionAsc
-	case "DESC":
-		directionOpt = githubv4.OrderDirectionDesc
+	case provider.DirectionAsc, provider.DirectionDesc:
 	default:
 		return nil, fmt.Errorf("Invalid issue order option, %s", direction)
 	}
@@ -149,14 +167,8 @@ func toListProjectPullReqeustOption(flags *pflag.FlagSet) (*github.ListProjectPu
 	if err != nil {
 		return nil, err
 	}
-	var sortOpt githubv4.IssueOrderField
 	switch sort {
-	case "COMMENTS":
-		sortOpt = githubv4.IssueOrderFieldComments
-	case "CREATED_AT":
-		sortOpt = githubv4.IssueOrderFieldCreatedAt
-	case "UPDATED_AT":
-		sortOpt = githubv4.IssueOrderFieldUpdatedAt
+	case provider.SortComments, provider.SortCreatedAt, provider.SortUpdatedAt:
 	default:
 		return nil, fmt.Errorf("Invalid issue sort option, %s", sort)
 	}
@@ -165,22 +177,16 @@ func toListProjectPullReqeustOption(flags *pflag.FlagSet) (*github.ListProjectPu
 	if err != nil {
 		return nil, err
 	}
-	var statesOpt githubv4.PullRequestState
 	switch states {
-	case "OPEN":
-		statesOpt = githubv4.PullRequestStateOpen
-	case "MERGED":
-		statesOpt = githubv4.PullRequestStateMerged
-	case "CLOSED":
-		statesOpt = githubv4.PullRequestStateClosed
+	case provider.StateOpen, provider.StateMerged, provider.StateClosed:
 	default:
 		return nil, fmt.Errorf("Invalid issue sort option, %s", states)
 	}
 
-	return &github.ListProjectPullRequestOption{
+	return &provider.ListPullRequestOption{
 		Num:       num,
-		Sort:      sortOpt,
-		Direction: directionOpt,
-		States:    statesOpt,
+		Sort:      sort,
+		Direction: direction,
+		States:    states,
 	}, nil
 }
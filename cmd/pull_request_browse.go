@@ -1,15 +1,8 @@
 package cmd
 
 import (
-	"fmt"
-	"strconv"
-	"strings"
-
 	"github.com/lighttiger2505/huc/internal/cmdutil"
-	"github.com/lighttiger2505/huc/internal/config"
-	"github.com/lighttiger2505/huc/internal/git"
-	"github.com/lighttiger2505/huc/internal/github"
-	"github.com/lighttiger2505/huc/internal/ui"
+	"github.com/lighttiger2505/huc/internal/provider"
 	"github.com/spf13/cobra"
 )
 
@@ -32,41 +25,33 @@ func init() {
 }
 
 func browsePullRequestMain(cmd *cobra.Command, args []string) error {
-	cfg, err := config.GetConfig()
+	cfg, pInfo, err := factory.collectTarget()
 	if err != nil {
-		return fmt.Errorf("cannot load config, %s", err)
+		return err
 	}
-	remoteCollecter := git.NewRemoteCollecter(ui.NewBasicUi(), cfg, git.NewGitClient())
 
-	pInfo, err := remoteCollecter.CollectTarget(
-		"",
-		"",
-	)
+	number, err := getPullRequestNumber(args)
 	if err != nil {
 		return err
 	}
 
-	number, err := getPullRequestNumber(args)
+	p, err := newProvider(cmd.Context(), pInfo)
 	if err != nil {
 		return err
 	}
-
-	spProject := strings.Split(pInfo.Project, "/")
-	pullRequest, err := github.ShowPullRequest(pInfo.Token, spProject[0], spProject[1], number)
+	pullRequest, err := p.ShowPullRequest(number)
 	if err != nil {
 		return err
 	}
 
-	if err := browsePullRequest(pInfo, pullRequest); err != nil {
+	if err := browsePullRequest(factory.browser(cfg), p, pullRequest); err != nil {
 		return err
 	}
 	return nil
 }
 
-func browsePullRequest(pInfo *git.GitLabProjectInfo, pullRequest *github.PullRequest) error {
-	b := &cmdutil.Browser{}
-	selectedPullRequestNumber := int(pullRequest.Number)
-	url := strings.Join([]string{pInfo.SubpageUrl("pull"), strconv.Itoa(selectedPullRequestNumber)}, "/")
+func browsePullRequest(b cmdutil.URLOpener, p provider.Provider, pullRequest *provider.PullRequest) error {
+	url := p.PullRequestURL(pullRequest.Number)
 
 	if err := b.Open(url); err != nil {
 		return err
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var pullRequestCommentCmd = &cobra.Command{
+	Use:   "comment <number>",
+	Short: "Comment on the pull request",
+	Long: `Comment on the pull request by --body, --body-file or the editor.
+
+--edit-last edits your last comment on the pull request, and --reply-to quotes
+the comment chosen by the selector.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return commentPullRequestMain(cmd, args)
+	},
+}
+
+func init() {
+	pullRequestCmd.AddCommand(pullRequestCommentCmd)
+	addCommentFlags(pullRequestCommentCmd, "pull request")
+}
+
+func commentPullRequestMain(cmd *cobra.Command, args []string) error {
+	cfg, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+
+	number, err := getPullRequestNumber(args)
+	if err != nil {
+		return err
+	}
+	opt, err := toCommentOption(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	p, err := newProvider(cmd.Context(), pInfo)
+	if err != nil {
+		return err
+	}
+	return comment(cmd, cfg, p, pullRequestCommentTarget(p, number), opt)
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/issuetemplate"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/selector"
+	"github.com/spf13/cobra"
+)
+
+var pullRequestCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create the pull request",
+	Long: `Create the pull request from the current branch by the template
+.github/pull_request_template.md or .github/PULL_REQUEST_TEMPLATE of repository.
+
+The template is chosen when there are some templates, and it's opened in the
+editor. The labels and the assignees of template are added to the pull request.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return createPullRequestMain(cmd, args)
+	},
+}
+
+func init() {
+	pullRequestCmd.AddCommand(pullRequestCreateCmd)
+	addCreateFlags(pullRequestCreateCmd, "pull request")
+	pullRequestCreateCmd.Flags().String("base", "", "Branch that the changes are merged into, default is the default branch of origin")
+	pullRequestCreateCmd.Flags().String("head", "", "Branch of the changes, default is the current branch")
+}
+
+func createPullRequestMain(cmd *cobra.Command, args []string) error {
+	_, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+
+	base, err := cmd.Flags().GetString("base")
+	if err != nil {
+		return err
+	}
+	if base == "" {
+		if base, err = git.DefaultBranch("origin"); err != nil {
+			return fmt.Errorf("%s, please input --base", err)
+		}
+	}
+	head, err := cmd.Flags().GetString("head")
+	if err != nil {
+		return err
+	}
+	if head == "" {
+		head = pInfo.CurrentBranch
+	}
+	if head == "" || head == base {
+		return fmt.Errorf("Invalid head branch '%s', please input --head", head)
+	}
+
+	p, err := newProvider(cmd.Context(), pInfo)
+	if err != nil {
+		return err
+	}
+
+	u := factory.UI()
+	content, err := askContent(cmd, u, p, issuetemplate.PullRequest, "PULLREQ_EDITMSG.md")
+	if err != nil {
+		return err
+	}
+	ok, err := u.Confirm(fmt.Sprintf("Create the pull request '%s' from %s into %s?", content.Title, head, base), true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return selector.ErrAbort
+	}
+
+	pullRequest, err := p.CreatePullRequest(&provider.CreatePullRequestOption{
+		Title: content.Title,
+		Body:  content.Body,
+		Base:  base,
+		Head:  head,
+	})
+	if err != nil {
+		return fmt.Errorf("cannot create pull request, %w", err)
+	}
+	if len(content.Labels) > 0 {
+		if err := p.LabelPullRequest(pullRequest.Number, content.Labels); err != nil {
+			return fmt.Errorf("cannot add labels to pull request #%d, %w", pullRequest.Number, err)
+		}
+	}
+	if len(content.Assignees) > 0 {
+		if err := p.AssignPullRequest(pullRequest.Number, content.Assignees); err != nil {
+			return fmt.Errorf("cannot assign pull request #%d, %w", pullRequest.Number, err)
+		}
+	}
+	fmt.Fprintln(factory.Out, p.PullRequestURL(pullRequest.Number))
+	return nil
+}
@@ -3,13 +3,9 @@ package cmd
 import (
 	"fmt"
 	"strconv"
-	"strings"
 
 	"github.com/lighttiger2505/huc/internal/cmdutil"
-	"github.com/lighttiger2505/huc/internal/config"
-	"github.com/lighttiger2505/huc/internal/git"
-	"github.com/lighttiger2505/huc/internal/github"
-	"github.com/lighttiger2505/huc/internal/ui"
+	"github.com/lighttiger2505/huc/internal/provider"
 	"github.com/spf13/cobra"
 )
 
@@ -32,47 +28,49 @@ func init() {
 }
 
 func showPullRequestMain(cmd *cobra.Command, args []string) error {
-	cfg, err := config.GetConfig()
+	cfg, pInfo, err := factory.collectTarget()
 	if err != nil {
-		return fmt.Errorf("cannot load config, %s", err)
+		return err
 	}
-	remoteCollecter := git.NewRemoteCollecter(ui.NewBasicUi(), cfg, git.NewGitClient())
 
-	pInfo, err := remoteCollecter.CollectTarget(
-		"",
-		"",
-	)
+	number, err := getPullRequestNumber(args)
 	if err != nil {
 		return err
 	}
 
-	number, err := getPullRequestNumber(args)
+	p, err := newProvider(cmd.Context(), pInfo)
 	if err != nil {
 		return err
 	}
-
-	spProject := strings.Split(pInfo.Project, "/")
-	pullRequest, err := github.ShowPullRequest(pInfo.Token, spProject[0], spProject[1], number)
+	pullRequest, err := p.ShowPullRequest(number)
 	if err != nil {
 		return err
 	}
 
-	if err := showPullRequest(pullRequest); err != nil {
+	raw, err := cmd.Flags().GetBool("raw")
+	if err != nil {
+		return err
+	}
+	if err := showPullRequest(factory.pager(cfg), pullRequest, raw); err != nil {
 		return err
 	}
 	return nil
 }
 
-func showPullRequest(pullRequest *github.PullRequest) error {
-	contents := pullRequest.ToString()
-	if !cmdutil.IsOverScreeenRow(contents) {
-		fmt.Println(pullRequest.ToString())
-		return nil
+func showPullRequest(pager *cmdutil.Pager, pullRequest *provider.PullRequest, raw bool) error {
+	contents := pullRequest.ToRawString()
+	if !raw {
+		contents = pullRequest.Render(cmdutil.TerminalWidth(), cmdutil.IsTerminal())
 	}
-	if err := cmdutil.ShowPager(contents); err != nil {
+	w, err := pager.Start()
+	if err != nil {
 		return err
 	}
-	return nil
+	if _, err := fmt.Fprintln(w, contents); err != nil {
+		pager.Close()
+		return err
+	}
+	return pager.Close()
 }
 
 func getPullRequestNumber(args []string) (int, error) {
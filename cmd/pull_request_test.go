@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/ui"
+)
+
+func TestPullRequestCommand(t *testing.T) {
+	tests := []commandTest{
+		{
+			name:     "pull_request_show_github",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"pull-request", "show", "3", "--raw"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+func TestPullRequestCreateCommand(t *testing.T) {
+	tests := []commandTest{
+		{
+			// The template is read by the API without the checkout
+			name:     "pull_request_create_github_template",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"pull-request", "create", "--base", "main", "--head", "fix-bug", "--title", "Fix the bug"},
+			answers: []ui.Answer{
+				{Prompt: ui.PromptEditor, Query: "PULLREQ_EDITMSG.md", Text: "## Summary\n\nFixes #1\n"},
+				{Prompt: ui.PromptConfirm, Yes: true},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
+
+func TestPullRequestCommentCommand(t *testing.T) {
+	tests := []commandTest{
+		{
+			name:     "pull_request_comment_gitlab_reply_to",
+			domain:   "gitlab.com",
+			provider: "gitlab",
+			remote:   "git@gitlab.com:group/project.git",
+			args:     []string{"pull-request", "comment", "3", "--reply-to", "--body", "Fixed in the latest commit."},
+			selected: []int{1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
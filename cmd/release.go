@@ -2,21 +2,17 @@ package cmd
 
 import (
 	"fmt"
-	"strings"
 
-	"github.com/ktr0731/go-fuzzyfinder"
 	"github.com/lighttiger2505/huc/internal/cmdutil"
 	"github.com/lighttiger2505/huc/internal/config"
-	"github.com/lighttiger2505/huc/internal/git"
-	"github.com/lighttiger2505/huc/internal/github"
-	"github.com/lighttiger2505/huc/internal/ui"
-	"github.com/shurcooL/githubv4"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/selector"
 	"github.com/spf13/cobra"
 	"github.com/spf13/pflag"
 )
 
 var releaseCmd = &cobra.Command{
-	Use:   "release",
+	Use:   "release [tag]",
 	Short: "A brief description of your command",
 	Long: `A longer description that spans multiple lines and likely contains examples
 and usage of using your command. For example:
@@ -24,6 +20,7 @@ and usage of using your command. For example:
 Cobra is a CLI library for Go that empowers applications.
 This application is a tool to generate the needed files
 to quickly create a Cobra application.`,
+	Args: cobra.MaximumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return findRelease(cmd, args)
 	},
@@ -34,67 +31,99 @@ func init() {
 	rootCmd.AddCommand(releaseCmd)
 	releaseCmd.Flags().IntP("num", "n", 50, "Number of lists to display.")
 	releaseCmd.Flags().StringP("direction", "", "DESC", "To sort order. Can be either ASC or DESC")
-	releaseCmd.Flags().StringP("sort", "", "CREATED_AT", "What to sort results by. Can be either COMMENTS, CREATED_AT or UPDATED_AT")
+	releaseCmd.Flags().StringP("sort", "", "CREATED_AT", "What to sort results by. Can be either NAME or CREATED_AT")
+	releaseCmd.Flags().Bool("raw", false, "Show the description as raw markdown.")
+	releaseCmd.Flags().StringP("action", "", "browse", "Action to the selected release. browse or the name of actions in config")
+	releaseCmd.Flags().Duration("cache", 0, "Use the responses cached within the duration, e.g. 10m. The expired responses are revalidated by ETag.")
 }
 
+const (
+	ReleaseActionBrowse = "browse"
+)
+
 func findRelease(cmd *cobra.Command, args []string) error {
-	cfg, err := config.GetConfig()
+	cfg, pInfo, err := factory.collectTarget()
 	if err != nil {
-		return fmt.Errorf("cannot load config, %s", err)
+		return err
 	}
-	remoteCollecter := git.NewRemoteCollecter(ui.NewBasicUi(), cfg, git.NewGitClient())
 
-	pInfo, err := remoteCollecter.CollectTarget(
-		"",
-		"",
-	)
+	raw, err := cmd.Flags().GetBool("raw")
 	if err != nil {
 		return err
 	}
-
-	opt, err := toListProjectReleasesOption(cmd.Flags())
+	actionFlag, err := cmd.Flags().GetString("action")
 	if err != nil {
 		return err
 	}
 
-	spProject := strings.Split(pInfo.Project, "/")
-	releases, err := github.ListRelease(pInfo.Token, spProject[0], spProject[1], opt)
+	var action config.Action
+	if actionFlag != "" && actionFlag != ReleaseActionBrowse {
+		if action, err = customAction(cfg, actionFlag); err != nil {
+			return err
+		}
+	}
+
+	opt, err := toListProjectReleasesOption(cmd.Flags())
 	if err != nil {
 		return err
 	}
 
-	idx, err := fuzzyfinder.Find(
-		releases,
-		func(i int) string {
-			return string(releases[i].Name)
-		},
-		fuzzyfinder.WithPreviewWindow(func(i, w, h int) string {
-			if i == -1 {
-				return ""
-			}
-			return releases[i].ToString()
-		}),
-	)
+	var sel selector.Selector
+	if len(args) == 0 {
+		if sel, err = newSelector(cmd, cfg); err != nil {
+			return err
+		}
+	}
 
+	cacheTTL, err := cmd.Flags().GetDuration("cache")
+	if err != nil {
+		return err
+	}
+	p, err := newCachedProvider(cmd.Context(), pInfo, cacheTTL)
 	if err != nil {
-		if err.Error() == fuzzyfinder.ErrAbort.Error() {
-			return nil
-		}
 		return err
 	}
 
-	b := &cmdutil.Browser{}
-	selectedIssueNumber := string(releases[int(idx)].TagName)
-	url := strings.Join([]string{pInfo.SubpageUrl("releases/tag"), selectedIssueNumber}, "/")
+	// The release of tag is fetched directly without selector
+	var releases []provider.Release
+	var indices []int
+	if len(args) > 0 {
+		release, err := p.ShowRelease(args[0])
+		if err != nil {
+			return err
+		}
+		releases = []provider.Release{*release}
+		indices = []int{0}
+	} else {
+		if releases, err = p.ListRelease(opt); err != nil {
+			return err
+		}
+		if indices, err = selectReleases(sel, releases, actionFlag, raw); err != nil {
+			return err
+		}
+	}
 
-	if err := b.Open(url); err != nil {
-		return err
+	switch actionFlag {
+	case "", ReleaseActionBrowse:
+		b := factory.browser(cfg)
+		url := p.ReleaseURL(releases[indices[0]].TagName)
+		if err := b.Open(url); err != nil {
+			return err
+		}
+	default:
+		actionItems := make([]cmdutil.ActionItem, len(indices))
+		for i, index := range indices {
+			actionItems[i] = releaseActionItem(p, &releases[index])
+		}
+		if err := runCustomAction(actionFlag, action, pInfo, actionItems); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func toListProjectReleasesOption(flags *pflag.FlagSet) (*github.ListProjectReleaseOption, error) {
+func toListProjectReleasesOption(flags *pflag.FlagSet) (*provider.ListReleaseOption, error) {
 	num, err := flags.GetInt("num")
 	if err != nil {
 		return nil, err
@@ -104,12 +133,8 @@ func toListProjectReleasesOption(flags *pflag.FlagSet) (*github.ListProjectRelea
 	if err != nil {
 		return nil, err
 	}
-	var directionOpt githubv4.OrderDirection
 	switch direction {
-	case "ASC":
-		directionOpt = githubv4.OrderDirectionAsc
-	case "DESC":
-		directionOpt = githubv4.OrderDirectionDesc
+	case provider.DirectionAsc, provider.DirectionDesc:
 	default:
 		return nil, fmt.Errorf("Invalid release order option, %s", direction)
 	}
@@ -118,19 +143,39 @@ func toListProjectReleasesOption(flags *pflag.FlagSet) (*github.ListProjectRelea
 	if err != nil {
 		return nil, err
 	}
-	var sortOpt githubv4.ReleaseOrderField
 	switch sort {
-	case "NAME":
-		sortOpt = githubv4.ReleaseOrderFieldName
-	case "CREATED_AT":
-		sortOpt = githubv4.ReleaseOrderFieldCreatedAt
+	case provider.SortName, provider.SortCreatedAt:
 	default:
 		return nil, fmt.Errorf("Invalid release sort option, %s", sort)
 	}
 
-	return &github.ListProjectReleaseOption{
+	return &provider.ListReleaseOption{
 		Num:       num,
-		Sort:      sortOpt,
-		Direction: directionOpt,
+		Sort:      sort,
+		Direction: direction,
 	}, nil
 }
+
+func selectReleases(sel selector.Selector, releases []provider.Release, actionFlag string, raw bool) ([]int, error) {
+	items := make([]string, len(releases))
+	for i := range releases {
+		items[i] = releases[i].Name
+	}
+	indices, err := sel.Select(items, &selector.Option{
+		// Browser opens only one release, custom actions run for all selected releases
+		Multi: actionFlag != "" && actionFlag != ReleaseActionBrowse,
+		Preview: func(i, w, h int) string {
+			if i == -1 {
+				return ""
+			}
+			if raw {
+				return releases[i].ToRawString()
+			}
+			return releases[i].Render(cmdutil.PreviewWidth(w), false)
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return indices, nil
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import "testing"
+
+func TestReleaseCommand(t *testing.T) {
+	tests := []commandTest{
+		{
+			name:     "release_tag_github",
+			domain:   "github.com",
+			provider: "github",
+			remote:   "git@github.com:octocat/hello-world.git",
+			args:     []string{"release", "v1.0.0"},
+		},
+		{
+			// The release of tag is fetched directly, not searched in the list
+			name:     "release_tag_gitlab",
+			domain:   "gitlab.com",
+			provider: "gitlab",
+			remote:   "git@gitlab.com:group/project.git",
+			args:     []string{"release", "v1.0.0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, tt.run)
+	}
+}
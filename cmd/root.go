@@ -1,8 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+	"time"
 
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/cobra"
@@ -14,30 +19,97 @@ var cfgFile string
 var rootCmd = &cobra.Command{
 	Use:   "huc",
 	Short: "A brief description of your command",
-	Long: `A longer description that spans multiple lines and likely contains
-examples and usage of using your application. For example:
-
-Cobra is a CLI library for Go that empowers applications.
-This application is a tool to generate the needed files
-to quickly create a Cobra application.`,
-	// Uncomment the following line if your bare application
-	// has an action associated with it:
-	//	Run: func(cmd *cobra.Command, args []string) { },
+	Long: `huc works with the issues, pull requests and releases of the repository
+on GitHub, GitLab, Gitea and Forgejo.
+
+` + exitCodesHelp,
+	// The errors are printed with the suggestions by Execute
+	SilenceErrors: true,
+	SilenceUsage:  true,
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
-		os.Exit(1)
+	if isCompletionRequest(os.Args[1:]) {
+		if err := rootCmd.Execute(); err != nil {
+			os.Exit(1)
+		}
+		return
+	}
+
+	args, shell, err := expandAlias(os.Args[1:])
+	if err != nil {
+		exitWith(err)
+	}
+	if shell {
+		exitWith(runShellAlias(args[0], args[1:]))
+		return
+	}
+	// Unknown subcommands are dispatched to the extensions
+	if path, n, ok := findExtension(args); ok {
+		exitWith(runExtension(path, args[:n], args[n+1:]))
+		return
+	}
+
+	ctx, cancel := notifyContext(context.Background())
+	defer cancel()
+	rootCmd.SetArgs(args)
+	if err := rootCmd.ExecuteContext(ctx); err != nil {
+		cancel()
+		exitWith(err)
+	}
+}
+
+// exitWith prints err to stderr and exits with the exit code of its type, or
+// the exit code of the external command, e.g. the shell alias.
+func exitWith(err error) {
+	if err == nil {
+		return
+	}
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		os.Exit(exitErr.ExitCode())
+	}
+	os.Exit(printError(os.Stderr, err, DebugFlag))
+}
+
+// notifyContext returns the context canceled by the interrupt, the API
+// requests in progress are canceled by Ctrl-C.
+func notifyContext(parent context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(parent)
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		select {
+		case <-sig:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, func() {
+		signal.Stop(sig)
+		cancel()
 	}
 }
 
 var VerboseFlag bool
 
+// DebugFlag prints the wrapped causes of error.
+var DebugFlag bool
+
+// TimeoutFlag is the time limit of each API request, no limit when it's 0.
+var TimeoutFlag time.Duration
+
+// YesFlag answers yes to the confirmations and fails instead of the other
+// prompts, the commands never wait for the input in scripts and CI.
+var YesFlag bool
+
+// ProfileFlag is the name of profile, it's the domain of hosting service, e.g. "github.com"
+var ProfileFlag string
+
 func init() {
 	cobra.OnInitialize(initConfig)
+	rootCmd.SetFlagErrorFunc(flagError)
 
 	// Here you will define your flags and configuration settings.
 	// Cobra supports persistent flags, which, if defined here,
@@ -47,7 +119,15 @@ func init() {
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
 	// 	rootCmd.Flags().BoolP("toggle", "t", false, "Help message for toggle")
-	rootCmd.PersistentFlags().BoolVarP(&VerboseFlag, "verbose", "v", false, "verbose output")
+	rootCmd.PersistentFlags().BoolVarP(&VerboseFlag, "verbose", "v", false, "Print the API requests and responses to stderr, it's also enabled by HUB_VERBOSE")
+	rootCmd.PersistentFlags().BoolVar(&DebugFlag, "debug", false, "Print the wrapped causes of error")
+	rootCmd.PersistentFlags().StringVar(&ProfileFlag, "profile", "", "Profile of the hosting service in config, e.g. github.com")
+	if err := rootCmd.RegisterFlagCompletionFunc("profile", completeProfiles); err != nil {
+		panic(err)
+	}
+	rootCmd.PersistentFlags().BoolVarP(&YesFlag, "yes", "y", false, "Answer yes to the confirmations and fail instead of the other prompts")
+	rootCmd.PersistentFlags().DurationVar(&TimeoutFlag, "timeout", 0, "Time limit of each API request including the retries, e.g. 30s")
+	rootCmd.PersistentFlags().String("selector", "", "Selector of items. fuzzyfinder, prompt or the command, e.g. fzf")
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -59,7 +139,7 @@ func initConfig() {
 		// Find home directory.
 		home, err := homedir.Dir()
 		if err != nil {
-			fmt.Println(err)
+			fmt.Fprintln(os.Stderr, err)
 			os.Exit(1)
 		}
 
@@ -72,6 +152,6 @@ func initConfig() {
 
 	// If a config file is found, read it in.
 	if err := viper.ReadInConfig(); err == nil {
-		fmt.Println("Using config file:", viper.ConfigFileUsed())
+		fmt.Fprintln(factory.ErrOut, "Using config file:", viper.ConfigFileUsed())
 	}
 }
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/lighttiger2505/huc/internal/selector"
+	"github.com/lighttiger2505/huc/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// newSelector returns the selector of --selector flag, or "selector" of config.
+// The built-in selectors fail by --yes, the commands are used in scripts.
+// Call it before the API requests, so that --yes fails without them.
+func newSelector(cmd *cobra.Command, cfg *config.Config) (selector.Selector, error) {
+	name, err := cmd.Flags().GetString("selector")
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = cfg.Selector
+	}
+	switch strings.TrimSpace(name) {
+	case "", selector.FuzzyFinder, selector.Prompt:
+		if YesFlag {
+			return nil, fmt.Errorf("%w, select the item by --selector with the command, e.g. \"head -n 1\"", ui.ErrNonInteractive)
+		}
+	}
+	return factory.Selector(name), nil
+}
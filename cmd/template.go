@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/issuetemplate"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/ui"
+	"github.com/spf13/cobra"
+)
+
+// createContent is the contents of the issue or the pull request to create.
+type createContent struct {
+	Title     string
+	Body      string
+	Labels    []string
+	Assignees []string
+}
+
+func addCreateFlags(cmd *cobra.Command, kind string) {
+	cmd.Flags().StringP("title", "t", "", fmt.Sprintf("Title of the %s, it's asked when empty", kind))
+	cmd.Flags().StringP("body", "b", "", fmt.Sprintf("Body of the %s, the template is opened in the editor when empty", kind))
+	cmd.Flags().StringSliceP("label", "l", nil, "Labels to add in addition to the labels of template, e.g. bug,help")
+	cmd.Flags().StringSliceP("assignee", "a", nil, "Users to assign in addition to the assignees of template")
+	cmd.Flags().String("template", "", "Name or filename of the template, the template is chosen when empty")
+}
+
+// findTemplates returns the templates of the local checkout, or of the default
+// branch by the API when there is no checkout or --profile is specified.
+func findTemplates(p provider.Provider, kind string) ([]*issuetemplate.Template, error) {
+	if dir, err := factory.RepositoryDir(); err == nil && ProfileFlag == "" {
+		return issuetemplate.Find(issuetemplate.DirSource(dir), kind)
+	}
+	reader, ok := p.(provider.FileReader)
+	if !ok {
+		return nil, nil
+	}
+	return issuetemplate.Find(reader, kind)
+}
+
+// askContent builds the contents from the flags and the template. The form of
+// template is asked field by field, and the markdown template is opened in the
+// editor unless --body is specified.
+func askContent(cmd *cobra.Command, u ui.UI, p provider.Provider, kind, editFilename string) (*createContent, error) {
+	flags := cmd.Flags()
+	title, err := flags.GetString("title")
+	if err != nil {
+		return nil, err
+	}
+	body, err := flags.GetString("body")
+	if err != nil {
+		return nil, err
+	}
+	labels, err := flags.GetStringSlice("label")
+	if err != nil {
+		return nil, err
+	}
+	assignees, err := flags.GetStringSlice("assignee")
+	if err != nil {
+		return nil, err
+	}
+	templateName, err := flags.GetString("template")
+	if err != nil {
+		return nil, err
+	}
+
+	var t *issuetemplate.Template
+	if body == "" || templateName != "" {
+		templates, err := findTemplates(p, kind)
+		if err != nil {
+			return nil, err
+		}
+		if t, err = issuetemplate.Choose(templates, templateName, u); err != nil {
+			return nil, err
+		}
+	}
+
+	if title == "" {
+		if title, err = u.Ask("Title:"); err != nil {
+			return nil, err
+		}
+		title = strings.TrimSpace(title)
+	}
+	if t != nil {
+		title = t.ApplyTitle(title)
+		labels = mergeNames(t.Labels, labels)
+		assignees = mergeNames(t.Assignees, assignees)
+	}
+	if title == "" {
+		return nil, fmt.Errorf("Invalid title, the title is required")
+	}
+
+	if body == "" {
+		switch {
+		case t != nil && t.Form != nil:
+			body, err = t.Form.Ask(u)
+		case t != nil:
+			body, err = u.Editor(editFilename, t.Body)
+		default:
+			body, err = u.Editor(editFilename, "")
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return &createContent{
+		Title:     title,
+		Body:      strings.TrimSpace(body),
+		Labels:    labels,
+		Assignees: assignees,
+	}, nil
+}
+
+// mergeNames returns the names without duplicates in order.
+func mergeNames(lists ...[]string) []string {
+	merged := []string{}
+	seen := map[string]bool{}
+	for _, list := range lists {
+		for _, name := range list {
+			if name = strings.TrimSpace(name); name != "" && !seen[name] {
+				seen[name] = true
+				merged = append(merged, name)
+			}
+		}
+	}
+	return merged
+}
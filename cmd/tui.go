@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/tui"
+	"github.com/spf13/cobra"
+)
+
+var tuiCmd = &cobra.Command{
+	Use:   "tui",
+	Short: "Triage issues and pull requests in full-screen",
+	Long: `Triage issues and pull requests in full-screen.
+
+The tabs of issues, pull requests and releases are refreshed in background.
+Press ? to show the keybindings.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return runTui(cmd)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+	tuiCmd.Flags().Duration("refresh", 5*time.Minute, "Interval of background refresh. Disable with 0.")
+}
+
+func runTui(cmd *cobra.Command) error {
+	cfg, pInfo, err := factory.collectTarget()
+	if err != nil {
+		return err
+	}
+
+	interval, err := cmd.Flags().GetDuration("refresh")
+	if err != nil {
+		return err
+	}
+
+	p, err := newProvider(cmd.Context(), pInfo)
+	if err != nil {
+		return err
+	}
+
+	// The url is printed on the status bar instead of the screen on headless sessions
+	b := factory.browser(cfg)
+	b.Out = ioutil.Discard
+
+	app := tui.New(p, b)
+	app.Title = pInfo.Project
+	app.Remote = remoteName(factory.GitClient(), pInfo)
+	app.RefreshInterval = interval
+	return app.Run()
+}
+
+// remoteName returns the name of git remote that points the project, it's "origin" when not found.
+func remoteName(client git.Client, pInfo *git.GitLabProjectInfo) string {
+	infos, err := client.RemoteInfos()
+	if err != nil {
+		return "origin"
+	}
+	for _, info := range infos {
+		if info.Domain == pInfo.Domain && info.RepositoryFullName() == pInfo.Project {
+			return info.Remote
+		}
+	}
+	return "origin"
+}
@@ -0,0 +1,115 @@
+// Package alias expands the user-defined aliases of subcommands.
+package alias
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ShellPrefix is the prefix of the alias that runs the shell command instead of huc.
+const ShellPrefix = "!"
+
+// IsShell returns true when the expansion is the shell command, e.g. "!git log".
+func IsShell(expansion string) bool {
+	return strings.HasPrefix(expansion, ShellPrefix)
+}
+
+// Expand returns the arguments of huc that the positional placeholders, e.g.
+// "$1", are replaced by args. The args that are not referenced are appended.
+// The placeholder escaped by backslash, e.g. `\$1`, is not replaced.
+func Expand(expansion string, args []string) ([]string, error) {
+	used := 0
+	if _, err := split(expansion, func(n int) string {
+		if n > used {
+			used = n
+		}
+		return ""
+	}); err != nil {
+		return nil, err
+	}
+	if used > len(args) {
+		return nil, fmt.Errorf("not enough arguments for alias, want %d, got %d", used, len(args))
+	}
+
+	words, err := split(expansion, func(n int) string {
+		return args[n-1]
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(words, args[used:]...), nil
+}
+
+// Split splits the command line into words like the shell. Words are quoted
+// by single or double quotes, backslash escapes the next character outside
+// of single quotes.
+func Split(s string) ([]string, error) {
+	return split(s, nil)
+}
+
+// split splits s into words, the placeholders "$1", "$2" and so on that are
+// not escaped are replaced by placeholder when it's not nil.
+func split(s string, placeholder func(n int) string) ([]string, error) {
+	words := []string{}
+	var word strings.Builder
+	inWord := false
+	var quote rune
+	escaped := false
+	runes := []rune(s)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		switch {
+		case escaped:
+			word.WriteRune(c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case c == '$' && placeholder != nil && placeholderDigits(runes[i+1:]) > 0:
+			digits := placeholderDigits(runes[i+1:])
+			n, _ := strconv.Atoi(string(runes[i+1 : i+1+digits]))
+			word.WriteString(placeholder(n))
+			i += digits
+			inWord = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '\'' || c == '"':
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+	if quote != 0 || escaped {
+		return nil, fmt.Errorf("unterminated quote or escape, %s", s)
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+// placeholderDigits returns the number of the digits of the placeholder at
+// the head of runes, it's 0 when runes is not a placeholder, e.g. "$0".
+func placeholderDigits(runes []rune) int {
+	n := 0
+	for n < len(runes) && n < 9 && '0' <= runes[n] && runes[n] <= '9' {
+		n++
+	}
+	if strings.TrimLeft(string(runes[:n]), "0") == "" {
+		return 0
+	}
+	return n
+}
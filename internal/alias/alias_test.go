@@ -0,0 +1,101 @@
+package alias
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	tests := []struct {
+		name      string
+		expansion string
+		args      []string
+		want      []string
+		wantErr   bool
+	}{
+		{
+			name:      "no placeholder",
+			expansion: "issue --labels bug --states OPEN",
+			args:      []string{},
+			want:      []string{"issue", "--labels", "bug", "--states", "OPEN"},
+		},
+		{
+			name:      "append args",
+			expansion: "issue --labels bug",
+			args:      []string{"--num", "10"},
+			want:      []string{"issue", "--labels", "bug", "--num", "10"},
+		},
+		{
+			name:      "placeholder",
+			expansion: `issue --labels "$1,priority:$2"`,
+			args:      []string{"bug", "high", "--num", "10"},
+			want:      []string{"issue", "--labels", "bug,priority:high", "--num", "10"},
+		},
+		{
+			name:      "escaped placeholder",
+			expansion: `issue --labels \$1 --states "\$2" --num $1`,
+			args:      []string{"10"},
+			want:      []string{"issue", "--labels", "$1", "--states", "$2", "--num", "10"},
+		},
+		{
+			name:      "not placeholder",
+			expansion: "issue --labels $0,$a",
+			args:      []string{},
+			want:      []string{"issue", "--labels", "$0,$a"},
+		},
+		{
+			name:      "not enough arguments",
+			expansion: "issue --labels $2",
+			args:      []string{"bug"},
+			wantErr:   true,
+		},
+		{
+			name:      "unterminated quote",
+			expansion: `issue --labels "bug`,
+			args:      []string{},
+			wantErr:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Expand(tt.expansion, tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Expand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expand() \nwant %#v \ngot  %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestSplit(t *testing.T) {
+	tests := []struct {
+		s    string
+		want []string
+	}{
+		{s: "", want: []string{}},
+		{s: "  issue   -n 10 ", want: []string{"issue", "-n", "10"}},
+		{s: `issue --labels "help wanted"`, want: []string{"issue", "--labels", "help wanted"}},
+		{s: `echo 'a "b"' "c 'd'" e\ f`, want: []string{"echo", `a "b"`, "c 'd'", "e f"}},
+		{s: `echo '' "\$1"`, want: []string{"echo", "", "$1"}},
+	}
+	for _, tt := range tests {
+		got, err := Split(tt.s)
+		if err != nil {
+			t.Fatalf("Split(%q) error = %v", tt.s, err)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Split(%q) \nwant %#v \ngot  %#v", tt.s, tt.want, got)
+		}
+	}
+}
+
+func TestIsShell(t *testing.T) {
+	if !IsShell("!git log") {
+		t.Errorf("IsShell() want true for shell alias")
+	}
+	if IsShell("issue") {
+		t.Errorf("IsShell() want false for huc alias")
+	}
+}
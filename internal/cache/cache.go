@@ -0,0 +1,117 @@
+// Package cache stores the values in the local files for a short time.
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"time"
+)
+
+// Cache stores the values as json files in Dir, they are expired after TTL
+// from written.
+type Cache struct {
+	Dir string
+	TTL time.Duration
+
+	now func() time.Time
+}
+
+func New(dir string, ttl time.Duration) *Cache {
+	return &Cache{
+		Dir: dir,
+		TTL: ttl,
+		now: time.Now,
+	}
+}
+
+// DefaultDir returns "$XDG_CACHE_HOME/huc", it's "~/.cache/huc" by default.
+func DefaultDir() string {
+	dir := os.Getenv("XDG_CACHE_HOME")
+	if dir == "" {
+		if runtime.GOOS == "windows" {
+			dir = os.Getenv("LOCALAPPDATA")
+		} else {
+			dir = filepath.Join(os.Getenv("HOME"), ".cache")
+		}
+	}
+	return filepath.Join(dir, "huc")
+}
+
+// path returns the file of the key, the key is hashed because it may have the characters not allowed in the file name.
+func (c *Cache) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.Dir, hex.EncodeToString(sum[:]))
+}
+
+// Get decodes the value of key into dest, it returns false when the value is not found or expired.
+func (c *Cache) Get(key string, dest interface{}) bool {
+	path := c.path(key)
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if c.now().Sub(info.ModTime()) > c.TTL {
+		os.Remove(path)
+		return false
+	}
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	if err := json.Unmarshal(b, dest); err != nil {
+		return false
+	}
+	return true
+}
+
+// Set stores the value of key. The file is readable only by the user because the value may be private.
+func (c *Cache) Set(key string, value interface{}) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("cannot marshal cache, %s", err)
+	}
+	if err := os.MkdirAll(c.Dir, 0700); err != nil {
+		return fmt.Errorf("cannot create cache directory, %s", err)
+	}
+
+	// Write to the temporary file and rename, the other process may read the same key
+	tmp, err := ioutil.TempFile(c.Dir, "tmp")
+	if err != nil {
+		return fmt.Errorf("cannot create cache, %s", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(b); err != nil {
+		tmp.Close()
+		return fmt.Errorf("cannot write cache, %s", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("cannot write cache, %s", err)
+	}
+	if err := os.Rename(tmp.Name(), c.path(key)); err != nil {
+		return fmt.Errorf("cannot write cache, %s", err)
+	}
+	return nil
+}
+
+// Clear removes all values.
+func (c *Cache) Clear() error {
+	if err := os.RemoveAll(c.Dir); err != nil {
+		return fmt.Errorf("cannot clear cache, %s", err)
+	}
+	return nil
+}
+
+// Delete removes the value of key.
+func (c *Cache) Delete(key string) error {
+	if err := os.Remove(c.path(key)); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot delete cache, %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package cache
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func setupTestCache(t *testing.T) (*Cache, func()) {
+	dir, err := ioutil.TempDir("", "huc-cache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return New(dir, time.Minute), func() { os.RemoveAll(dir) }
+}
+
+func TestCache(t *testing.T) {
+	c, teardown := setupTestCache(t)
+	defer teardown()
+
+	var got []string
+	if c.Get("issues:github.com/owner/repo", &got) {
+		t.Fatalf("Get() found the value before Set()")
+	}
+
+	want := []string{"1\tfirst", "2\tsecond"}
+	if err := c.Set("issues:github.com/owner/repo", want); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	if !c.Get("issues:github.com/owner/repo", &got) {
+		t.Fatalf("Get() not found the value")
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Get() \nwant %#v \ngot  %#v", want, got)
+	}
+
+	if err := c.Delete("issues:github.com/owner/repo"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if c.Get("issues:github.com/owner/repo", &got) {
+		t.Errorf("Get() found the deleted value")
+	}
+}
+
+func TestCache_Expired(t *testing.T) {
+	c, teardown := setupTestCache(t)
+	defer teardown()
+
+	if err := c.Set("key", "value"); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+	c.now = func() time.Time {
+		return time.Now().Add(2 * time.Minute)
+	}
+	var got string
+	if c.Get("key", &got) {
+		t.Errorf("Get() found the expired value, %q", got)
+	}
+	if _, err := os.Stat(c.path("key")); !os.IsNotExist(err) {
+		t.Errorf("Get() not removed the expired file, %v", err)
+	}
+}
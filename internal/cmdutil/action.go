@@ -0,0 +1,170 @@
+package cmdutil
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/lighttiger2505/huc/internal/git"
+)
+
+// ActionItem is the selected item that is passed to the template of action.
+// TagName is set only for release, Number, Author and PublishedAt are not.
+type ActionItem struct {
+	Number      int
+	TagName     string
+	Title       string
+	Author      string
+	PublishedAt time.Time
+	Body        string
+	URL         string
+}
+
+// ActionContext is the data of the template of action. The fields of item
+// are promoted, e.g. {{.URL}}, they are empty when the action runs once with
+// all items. Items is all of the selected items.
+type ActionContext struct {
+	ActionItem
+	Domain        string
+	Project       string
+	CurrentBranch string
+	Items         []ActionItem
+}
+
+// ActionRunner runs the user-defined actions in the config on the shell.
+type ActionRunner struct {
+	Stdin  io.Reader
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+func NewActionRunner() *ActionRunner {
+	return &ActionRunner{
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+}
+
+// shellQuoted is the value quoted for the shell, it's not quoted again.
+type shellQuoted string
+
+var shellSafeRe = regexp.MustCompile(`^[\w@%+=:,./-]+$`)
+
+var actionFuncs = template.FuncMap{
+	// quote quotes the value for the shell, e.g. {{quote .Title}}
+	"quote": func(v interface{}) shellQuoted {
+		return shellQuoted("'" + strings.Replace(fmt.Sprint(v), "'", `'\''`, -1) + "'")
+	},
+	// autoquote is appended to every action by quoteActions, it quotes the
+	// value unless it's already quoted or has only safe characters.
+	"autoquote": func(v interface{}) shellQuoted {
+		if q, ok := v.(shellQuoted); ok {
+			return q
+		}
+		s := fmt.Sprint(v)
+		if shellSafeRe.MatchString(s) {
+			return shellQuoted(s)
+		}
+		return shellQuoted("'" + strings.Replace(s, "'", `'\''`, -1) + "'")
+	},
+}
+
+// quoteActions appends autoquote to the pipeline of every action in the
+// template. The title and body of items are untrusted, e.g. "$(rm -rf ~)",
+// they must be passed to the shell as the words, not as the commands.
+func quoteActions(tree *parse.Tree, node parse.Node) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, child := range n.Nodes {
+			quoteActions(tree, child)
+		}
+	case *parse.ActionNode:
+		// The declaration, e.g. {{$title := .Title}}, prints nothing
+		if len(n.Pipe.Decl) > 0 {
+			return
+		}
+		ident := parse.NewIdentifier("autoquote").SetTree(tree).SetPos(n.Pos)
+		n.Pipe.Cmds = append(n.Pipe.Cmds, &parse.CommandNode{NodeType: parse.NodeCommand, Pos: n.Pos, Args: []parse.Node{ident}})
+	case *parse.IfNode:
+		quoteActions(tree, n.List)
+		quoteActions(tree, n.ElseList)
+	case *parse.RangeNode:
+		quoteActions(tree, n.List)
+		quoteActions(tree, n.ElseList)
+	case *parse.WithNode:
+		quoteActions(tree, n.List)
+		quoteActions(tree, n.ElseList)
+	}
+}
+
+// Run runs the action once per item, or once with all items. All commands are
+// rendered before running, it runs nothing when the template is broken.
+func (r *ActionRunner) Run(name string, action config.Action, pInfo *git.GitLabProjectInfo, items []ActionItem) error {
+	commands, err := renderAction(name, action, pInfo, items)
+	if err != nil {
+		return err
+	}
+	for _, command := range commands {
+		var cmd *exec.Cmd
+		if runtime.GOOS == "windows" {
+			cmd = exec.Command("cmd", "/c", command)
+		} else {
+			cmd = exec.Command("sh", "-c", command)
+		}
+		cmd.Stdin = r.Stdin
+		cmd.Stdout = r.Stdout
+		cmd.Stderr = r.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("cannot run action '%s', %s", name, err)
+		}
+	}
+	return nil
+}
+
+func renderAction(name string, action config.Action, pInfo *git.GitLabProjectInfo, items []ActionItem) ([]string, error) {
+	tmpl, err := template.New(name).Funcs(actionFuncs).Parse(action.Command)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse action '%s', %s", name, err)
+	}
+	for _, t := range tmpl.Templates() {
+		quoteActions(t.Tree, t.Tree.Root)
+	}
+
+	base := ActionContext{
+		Domain:        pInfo.Domain,
+		Project:       pInfo.Project,
+		CurrentBranch: pInfo.CurrentBranch,
+		Items:         items,
+	}
+	contexts := []ActionContext{base}
+	if !action.All {
+		contexts = make([]ActionContext, len(items))
+		for i, item := range items {
+			contexts[i] = base
+			contexts[i].ActionItem = item
+		}
+	}
+
+	commands := make([]string, len(contexts))
+	for i, ctx := range contexts {
+		var buf bytes.Buffer
+		if err := tmpl.Execute(&buf, ctx); err != nil {
+			return nil, fmt.Errorf("cannot render action '%s', %s", name, err)
+		}
+		commands[i] = buf.String()
+	}
+	return commands, nil
+}
@@ -0,0 +1,103 @@
+package cmdutil
+
+import (
+	"bytes"
+	"reflect"
+	"runtime"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/lighttiger2505/huc/internal/git"
+)
+
+var testActionItems = []ActionItem{
+	{Number: 1, Title: "first", URL: "https://github.com/owner/repo/issues/1"},
+	{Number: 2, Title: "it's second", URL: "https://github.com/owner/repo/issues/2"},
+}
+
+func TestRenderAction(t *testing.T) {
+	pInfo := &git.GitLabProjectInfo{Domain: "github.com", Project: "owner/repo", Token: "token", CurrentBranch: "master"}
+	tests := []struct {
+		name    string
+		action  config.Action
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:   "per item",
+			action: config.Action{Command: "echo {{.URL}} {{.Project}}@{{.CurrentBranch}}"},
+			want: []string{
+				"echo https://github.com/owner/repo/issues/1 owner/repo@master",
+				"echo https://github.com/owner/repo/issues/2 owner/repo@master",
+			},
+		},
+		{
+			name:   "all items",
+			action: config.Action{Command: "echo{{range .Items}} #{{.Number}}{{end}}", All: true},
+			want:   []string{"echo #1 #2"},
+		},
+		{
+			name:   "quote",
+			action: config.Action{Command: "echo {{quote .Title}}"},
+			want:   []string{`echo 'first'`, `echo 'it'\''s second'`},
+		},
+		{
+			name:   "untrusted values are quoted",
+			action: config.Action{Command: `echo {{range .Items}}{{.Title}} {{end}}{{printf "%s" .Project}}`, All: true},
+			want:   []string{`echo first 'it'\''s second' owner/repo`},
+		},
+		{
+			name:    "broken template",
+			action:  config.Action{Command: "echo {{.URL"},
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			action:  config.Action{Command: "echo {{.Token}}"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := renderAction("test", tt.action, pInfo, testActionItems)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderAction() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("renderAction() \nwant %#v \ngot  %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestActionRunner_Run(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("sh is not available")
+	}
+	var stdout bytes.Buffer
+	r := &ActionRunner{Stdout: &stdout, Stderr: &stdout}
+	pInfo := &git.GitLabProjectInfo{Domain: "github.com", Project: "owner/repo"}
+
+	if err := r.Run("test", config.Action{Command: "echo {{.Number}}"}, pInfo, testActionItems); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got, want := stdout.String(), "1\n2\n"; got != want {
+		t.Errorf("Run() output \nwant %q \ngot  %q", want, got)
+	}
+
+	stdout.Reset()
+	injected := []ActionItem{{Title: "$(echo injected); echo `echo injected`"}}
+	if err := r.Run("test", config.Action{Command: "echo {{.Title}}"}, pInfo, injected); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if got, want := stdout.String(), "$(echo injected); echo `echo injected`\n"; got != want {
+		t.Errorf("Run() output \nwant %q \ngot  %q", want, got)
+	}
+
+	if err := r.Run("test", config.Action{Command: "exit 1"}, pInfo, testActionItems); err == nil {
+		t.Errorf("Run() want error for failed command")
+	}
+}
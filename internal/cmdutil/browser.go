@@ -0,0 +1,202 @@
+package cmdutil
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/mattn/go-isatty"
+)
+
+// Special launchers that do not open browser
+const (
+	// BrowserPrint prints url to the output
+	BrowserPrint = "print"
+	// BrowserOSC52 copies url to the clipboard of the terminal by OSC 52 escape sequence
+	BrowserOSC52 = "osc52"
+)
+
+type URLOpener interface {
+	Open(url string) error
+}
+
+type Browser struct {
+	// Launcher is the command of browser or special launcher. It's searched from environment when empty
+	Launcher string
+	Out      io.Writer
+	// Terminal receives the escape sequence of OSC 52
+	Terminal io.Writer
+	Getenv   func(string) string
+	GOOS     string
+	// IsTerminal reports whether Out and Terminal are terminals, it's detected from them when nil
+	IsTerminal func() bool
+}
+
+// NewBrowser returns Browser that uses "browser" of config, or $BROWSER.
+func NewBrowser(cfg *config.Config) *Browser {
+	b := &Browser{
+		Out:      os.Stdout,
+		Terminal: os.Stderr,
+		Getenv:   os.Getenv,
+		GOOS:     runtime.GOOS,
+	}
+	if cfg != nil {
+		b.Launcher = cfg.Browser
+	}
+	return b
+}
+
+func (b *Browser) Open(url string) error {
+	launcher := b.launcher()
+	switch launcher {
+	case BrowserPrint:
+		fmt.Fprintln(b.Out, url)
+		return nil
+	case BrowserOSC52:
+		if err := writeOSC52(b.Terminal, url, b.getenv); err != nil {
+			return err
+		}
+		fmt.Fprintln(b.Out, url)
+		return nil
+	}
+
+	// $BROWSER is a colon separated list of commands, try in order
+	var err error
+	for _, browser := range strings.Split(launcher, ":") {
+		if strings.TrimSpace(browser) == "" {
+			continue
+		}
+		if err = launch(browser, url); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+func (b *Browser) launcher() string {
+	if b.Launcher != "" {
+		return b.Launcher
+	}
+	if browser := b.getenv("BROWSER"); browser != "" {
+		return browser
+	}
+	if isHeadless(b.GOOS, b.getenv) {
+		return b.fallbackLauncher()
+	}
+	if browser := searchBrowserLauncher(b.GOOS); browser != "" {
+		return browser
+	}
+	return b.fallbackLauncher()
+}
+
+// fallbackLauncher returns the launcher without browser. The url is copied to
+// the clipboard of the terminal in addition to printing when the output is a
+// terminal, the escape sequence is garbage for pipes.
+func (b *Browser) fallbackLauncher() string {
+	if b.isTerminal() {
+		return BrowserOSC52
+	}
+	return BrowserPrint
+}
+
+func (b *Browser) isTerminal() bool {
+	if b.IsTerminal != nil {
+		return b.IsTerminal()
+	}
+	out, ok := b.Out.(*os.File)
+	if !ok || !isTerminal(out) {
+		return false
+	}
+	term, ok := b.Terminal.(*os.File)
+	return ok && isTerminal(term)
+}
+
+func (b *Browser) getenv(key string) string {
+	if b.Getenv == nil {
+		return os.Getenv(key)
+	}
+	return b.Getenv(key)
+}
+
+// launch runs browser command, "%s" in the command is replaced to url.
+func launch(browser, url string) error {
+	args := strings.Fields(browser)
+	replaced := false
+	for i, arg := range args {
+		if strings.Contains(arg, "%s") {
+			args[i] = strings.Replace(arg, "%s", url, -1)
+			replaced = true
+		}
+	}
+	if !replaced {
+		args = append(args, url)
+	}
+
+	c := exec.Command(args[0], args[1:]...)
+	if err := c.Run(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// isHeadless returns true when there is no display, e.g. a ssh session.
+func isHeadless(goos string, getenv func(string) string) bool {
+	if getenv("SSH_CONNECTION") != "" || getenv("SSH_TTY") != "" {
+		return true
+	}
+	switch goos {
+	case "darwin", "windows":
+		return false
+	}
+	return getenv("DISPLAY") == "" && getenv("WAYLAND_DISPLAY") == ""
+}
+
+// writeOSC52 writes the escape sequence that copies text to the clipboard.
+// It's wrapped by the passthrough sequence in tmux and screen.
+func writeOSC52(w io.Writer, text string, getenv func(string) string) error {
+	if f, ok := w.(*os.File); ok && !isatty.IsTerminal(f.Fd()) {
+		return fmt.Errorf("cannot copy to clipboard, not a terminal")
+	}
+
+	seq := "\x1b]52;c;" + base64.StdEncoding.EncodeToString([]byte(text)) + "\x07"
+	switch {
+	case getenv("TMUX") != "":
+		seq = "\x1bPtmux;\x1b" + seq + "\x1b\\"
+	case strings.HasPrefix(getenv("TERM"), "screen"):
+		seq = "\x1bP" + seq + "\x1b\\"
+	}
+	_, err := io.WriteString(w, seq)
+	return err
+}
+
+func searchBrowserLauncher(goos string) (browser string) {
+	switch goos {
+	case "darwin":
+		browser = "open"
+	case "windows":
+		browser = "cmd /c start"
+	default:
+		candidates := []string{
+			"xdg-open",
+			"cygstart",
+			"x-www-browser",
+			"firefox",
+			"opera",
+			"mozilla",
+			"netscape",
+		}
+		for _, b := range candidates {
+			path, err := exec.LookPath(b)
+			if err == nil {
+				browser = path
+				break
+			}
+		}
+	}
+	return browser
+}
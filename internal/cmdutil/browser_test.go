@@ -0,0 +1,141 @@
+package cmdutil
+
+import (
+	"bytes"
+	"testing"
+)
+
+func testGetenv(env map[string]string) func(string) string {
+	return func(key string) string {
+		return env[key]
+	}
+}
+
+func TestBrowser_launcher(t *testing.T) {
+	tests := []struct {
+		name     string
+		launcher string
+		goos     string
+		env      map[string]string
+		terminal bool
+		want     string
+	}{
+		{
+			name:     "config",
+			launcher: "w3m",
+			goos:     "linux",
+			env:      map[string]string{"BROWSER": "lynx"},
+			want:     "w3m",
+		},
+		{
+			name: "$BROWSER",
+			goos: "linux",
+			env:  map[string]string{"BROWSER": "lynx:w3m", "SSH_TTY": "/dev/pts/0"},
+			want: "lynx:w3m",
+		},
+		{
+			name: "ssh session",
+			goos: "darwin",
+			env:  map[string]string{"SSH_CONNECTION": "10.0.0.1 22 10.0.0.2 22"},
+			want: BrowserPrint,
+		},
+		{
+			name:     "ssh session on terminal",
+			goos:     "linux",
+			env:      map[string]string{"SSH_TTY": "/dev/pts/0", "DISPLAY": ":0"},
+			terminal: true,
+			want:     BrowserOSC52,
+		},
+		{
+			name: "no display",
+			goos: "linux",
+			env:  map[string]string{},
+			want: BrowserPrint,
+		},
+		{
+			name:     "no display on terminal",
+			goos:     "linux",
+			env:      map[string]string{},
+			terminal: true,
+			want:     BrowserOSC52,
+		},
+		{
+			name: "darwin",
+			goos: "darwin",
+			env:  map[string]string{},
+			want: "open",
+		},
+		{
+			name: "windows",
+			goos: "windows",
+			env:  map[string]string{},
+			want: "cmd /c start",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &Browser{
+				Launcher:   tt.launcher,
+				GOOS:       tt.goos,
+				Getenv:     testGetenv(tt.env),
+				IsTerminal: func() bool { return tt.terminal },
+			}
+			if got := b.launcher(); got != tt.want {
+				t.Errorf("launcher() \nwant %q \ngot  %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestBrowser_Open(t *testing.T) {
+	tests := []struct {
+		name     string
+		launcher string
+		env      map[string]string
+		wantOut  string
+		wantTerm string
+	}{
+		{
+			name:     "print",
+			launcher: BrowserPrint,
+			env:      map[string]string{},
+			wantOut:  "https://github.com/lighttiger2505/huc\n",
+		},
+		{
+			name:     "osc52",
+			launcher: BrowserOSC52,
+			env:      map[string]string{},
+			wantOut:  "https://github.com/lighttiger2505/huc\n",
+			wantTerm: "\x1b]52;c;aHR0cHM6Ly9naXRodWIuY29tL2xpZ2h0dGlnZXIyNTA1L2h1Yw==\x07",
+		},
+		{
+			name:     "osc52 in tmux",
+			launcher: BrowserOSC52,
+			env:      map[string]string{"TMUX": "/tmp/tmux-1000/default,1,0"},
+			wantOut:  "https://github.com/lighttiger2505/huc\n",
+			wantTerm: "\x1bPtmux;\x1b\x1b]52;c;aHR0cHM6Ly9naXRodWIuY29tL2xpZ2h0dGlnZXIyNTA1L2h1Yw==\x07\x1b\\",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			term := &bytes.Buffer{}
+			b := &Browser{
+				Launcher: tt.launcher,
+				Out:      out,
+				Terminal: term,
+				Getenv:   testGetenv(tt.env),
+				GOOS:     "linux",
+			}
+			if err := b.Open("https://github.com/lighttiger2505/huc"); err != nil {
+				t.Fatalf("Open() error = %v", err)
+			}
+			if got := out.String(); got != tt.wantOut {
+				t.Errorf("bad output \nwant %q \ngot  %q", tt.wantOut, got)
+			}
+			if got := term.String(); got != tt.wantTerm {
+				t.Errorf("bad terminal output \nwant %q \ngot  %q", tt.wantTerm, got)
+			}
+		})
+	}
+}
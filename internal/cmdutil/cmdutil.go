@@ -2,71 +2,39 @@ package cmdutil
 
 import (
 	"os"
-	"os/exec"
-	"runtime"
-	"strings"
 
 	"golang.org/x/crypto/ssh/terminal"
 )
 
-type URLOpener interface {
-	Open(url string) error
+// TerminalSize returns the size of terminal on stdout.
+func TerminalSize() (int, int, error) {
+	return terminal.GetSize(int(os.Stdout.Fd()))
 }
 
-type Browser struct{}
-
-func (b *Browser) Open(url string) error {
-	browser := searchBrowserLauncher(runtime.GOOS)
-	c := exec.Command(browser, url)
-	if err := c.Run(); err != nil {
-		return err
+// TerminalWidth returns the width of terminal on stdout, or 80 when stdout is not a terminal.
+func TerminalWidth() int {
+	width, _, err := TerminalSize()
+	if err != nil || width <= 0 {
+		return 80
 	}
-	return nil
+	return width
 }
 
-func searchBrowserLauncher(goos string) (browser string) {
-	switch goos {
-	case "darwin":
-		browser = "open"
-	case "windows":
-		browser = "cmd /c start"
-	default:
-		candidates := []string{
-			"xdg-open",
-			"cygstart",
-			"x-www-browser",
-			"firefox",
-			"opera",
-			"mozilla",
-			"netscape",
-		}
-		for _, b := range candidates {
-			path, err := exec.LookPath(b)
-			if err == nil {
-				browser = path
-				break
-			}
-		}
-	}
-	return browser
+// IsTerminal returns true when stdout is a terminal, the output is colored only then.
+func IsTerminal() bool {
+	return isTerminal(os.Stdout)
 }
 
-func IsOverScreeenRow(contents string) bool {
-	row := strings.Count(contents, "\n")
-	_, height, _ := terminal.GetSize(0)
-	if row > height {
-		return true
+// PreviewWidth returns the width of contents in the preview window of fuzzyfinder.
+// The preview window is the right half of the screen that has borders and padding.
+func PreviewWidth(screenWidth int) int {
+	width := screenWidth - screenWidth/2 - 5
+	if width < 1 {
+		return 1
 	}
-	return false
+	return width
 }
 
-func ShowPager(contents string) error {
-	cmd := exec.Command("less", "-R")
-	cmd.Stdin = strings.NewReader(contents)
-	cmd.Stdout = os.Stdout
-	err := cmd.Run()
-	if err != nil {
-		return err
-	}
-	return nil
+func isTerminal(f *os.File) bool {
+	return terminal.IsTerminal(int(f.Fd()))
 }
@@ -0,0 +1,62 @@
+package cmdutil
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/lighttiger2505/huc/internal/git"
+)
+
+// Editor returns the editor command, it's same as git. $VISUAL and $EDITOR are used outside of repository.
+func Editor() string {
+	if editor, err := git.GitEditor(); err == nil && editor != "" {
+		return editor
+	}
+	for _, key := range []string{"VISUAL", "EDITOR"} {
+		if editor := os.Getenv(key); editor != "" {
+			return editor
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
+
+// EditText opens the text in the editor and returns the edited text. The
+// filename is the name of temporary file, e.g. "COMMENT.md" for syntax highlighting.
+func EditText(filename, text string) (string, error) {
+	dir, err := ioutil.TempDir("", "huc")
+	if err != nil {
+		return "", fmt.Errorf("cannot create temporary directory, %s", err)
+	}
+	defer os.RemoveAll(dir)
+
+	path := dir + string(os.PathSeparator) + filename
+	if err := ioutil.WriteFile(path, []byte(text), 0600); err != nil {
+		return "", fmt.Errorf("cannot write temporary file, %s", err)
+	}
+
+	// The editor may have arguments, e.g. "code --wait"
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = exec.Command("cmd", "/c", Editor()+" "+path)
+	} else {
+		cmd = exec.Command("sh", "-c", Editor()+` "$1"`, "sh", path)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("cannot run editor, %s", err)
+	}
+
+	edited, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read temporary file, %s", err)
+	}
+	return string(edited), nil
+}
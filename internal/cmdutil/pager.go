@@ -0,0 +1,194 @@
+package cmdutil
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"syscall"
+
+	"github.com/lighttiger2505/huc/internal/config"
+	runewidth "github.com/mattn/go-runewidth"
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+const defaultPager = "less -R"
+
+// Pager pages the output by the pager command, e.g. less.
+type Pager struct {
+	// Command is the pager command, the output is not paged when it's empty or "cat"
+	Command string
+	Out     io.Writer
+	Err     io.Writer
+
+	// screenSize returns the size of terminal instead of Out, it's for tests
+	screenSize func() (width, height int, ok bool)
+
+	w    *pagerWriter
+	cmd  *exec.Cmd
+	pipe io.WriteCloser
+}
+
+// NewPager returns Pager that uses $HUC_PAGER, "pager" of config or $PAGER in order.
+func NewPager(cfg *config.Config) *Pager {
+	var configPager string
+	if cfg != nil {
+		configPager = cfg.Pager
+	}
+	return &Pager{
+		Command: pagerCommand(os.LookupEnv, configPager),
+		Out:     os.Stdout,
+		Err:     os.Stderr,
+	}
+}
+
+// pagerCommand returns the pager command. $HUC_PAGER disables pager when it's set to empty.
+func pagerCommand(lookupEnv func(string) (string, bool), configPager string) string {
+	if pager, ok := lookupEnv("HUC_PAGER"); ok {
+		return pager
+	}
+	if configPager != "" {
+		return configPager
+	}
+	if pager, ok := lookupEnv("PAGER"); ok && pager != "" {
+		return pager
+	}
+	return defaultPager
+}
+
+func (p *Pager) enabled() bool {
+	command := strings.TrimSpace(p.Command)
+	return command != "" && command != "cat"
+}
+
+// size returns the size of the terminal of Out, ok is false when Out is not a terminal.
+func (p *Pager) size() (width, height int, ok bool) {
+	if p.screenSize != nil {
+		return p.screenSize()
+	}
+	f, isFile := p.Out.(*os.File)
+	if !isFile || !isTerminal(f) {
+		return 0, 0, false
+	}
+	width, height, err := terminal.GetSize(int(f.Fd()))
+	return width, height, err == nil
+}
+
+// Start returns the writer to page the output, Close must be called after
+// writing. The output is held until it's over the screen, then the pager is
+// launched and the output is streamed into it as it arrives. The output is
+// written to Out directly when pager is disabled or Out is not a terminal.
+func (p *Pager) Start() (io.Writer, error) {
+	if !p.enabled() {
+		return p.Out, nil
+	}
+	width, height, ok := p.size()
+	if !ok {
+		return p.Out, nil
+	}
+	p.w = &pagerWriter{pager: p, width: width, height: height}
+	return p.w, nil
+}
+
+// launch runs the pager command by the shell the same as git, the command may
+// have the quoted arguments, e.g. less -R "+/foo bar".
+func (p *Pager) launch() error {
+	cmd := exec.Command("sh", "-c", p.Command)
+	cmd.Stdout = p.Out
+	cmd.Stderr = p.Err
+	cmd.Env = os.Environ()
+	// Quit if one screen and keep colors, it's same as git
+	if _, ok := os.LookupEnv("LESS"); !ok {
+		cmd.Env = append(cmd.Env, "LESS=FRX")
+	}
+	if _, ok := os.LookupEnv("LV"); !ok {
+		cmd.Env = append(cmd.Env, "LV=-c")
+	}
+
+	pipe, err := cmd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("cannot run pager, %s", err)
+	}
+	p.cmd = cmd
+	p.pipe = pipe
+	return nil
+}
+
+// Close writes the held output that fits in the screen, or closes the input
+// of pager and waits for the user to quit.
+func (p *Pager) Close() error {
+	w := p.w
+	p.w = nil
+	if w == nil {
+		return nil
+	}
+	if p.cmd == nil {
+		_, err := p.Out.Write(w.held)
+		return err
+	}
+	p.pipe.Close()
+	err := p.cmd.Wait()
+	p.cmd = nil
+	p.pipe = nil
+	return err
+}
+
+// pagerWriter holds the output until it's over the screen, and ignores the
+// error of broken pipe after the user quits the pager.
+type pagerWriter struct {
+	pager  *Pager
+	width  int
+	height int
+	held   []byte
+}
+
+func (w *pagerWriter) Write(b []byte) (int, error) {
+	p := w.pager
+	if p.cmd != nil {
+		return w.write(b)
+	}
+
+	w.held = append(w.held, b...)
+	if DisplayRows(string(w.held), w.width) <= w.height {
+		return len(b), nil
+	}
+	if err := p.launch(); err != nil {
+		return 0, err
+	}
+	held := w.held
+	w.held = nil
+	if _, err := w.write(held); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (w *pagerWriter) write(b []byte) (int, error) {
+	n, err := w.pager.pipe.Write(b)
+	if pathErr, ok := err.(*os.PathError); ok && pathErr.Err == syscall.EPIPE {
+		return len(b), nil
+	}
+	return n, err
+}
+
+var ansiRe = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+// DisplayRows returns the number of rows to display the contents on the
+// terminal of the width, lines longer than the width are counted as wrapped.
+func DisplayRows(contents string, width int) int {
+	rows := 0
+	for _, line := range strings.Split(strings.TrimSuffix(contents, "\n"), "\n") {
+		lineWidth := runewidth.StringWidth(ansiRe.ReplaceAllString(line, ""))
+		if width <= 0 || lineWidth <= width {
+			rows++
+			continue
+		}
+		rows += (lineWidth + width - 1) / width
+	}
+	return rows
+}
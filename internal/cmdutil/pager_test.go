@@ -0,0 +1,147 @@
+package cmdutil
+
+import (
+	"bytes"
+	"io"
+	"testing"
+)
+
+func testLookupEnv(env map[string]string) func(string) (string, bool) {
+	return func(key string) (string, bool) {
+		v, ok := env[key]
+		return v, ok
+	}
+}
+
+func TestPagerCommand(t *testing.T) {
+	tests := []struct {
+		name        string
+		env         map[string]string
+		configPager string
+		want        string
+	}{
+		{
+			name:        "$HUC_PAGER",
+			env:         map[string]string{"HUC_PAGER": "more", "PAGER": "lv"},
+			configPager: "most",
+			want:        "more",
+		},
+		{
+			name:        "empty $HUC_PAGER disables pager",
+			env:         map[string]string{"HUC_PAGER": "", "PAGER": "lv"},
+			configPager: "most",
+			want:        "",
+		},
+		{
+			name:        "config",
+			env:         map[string]string{"PAGER": "lv"},
+			configPager: "most",
+			want:        "most",
+		},
+		{
+			name: "$PAGER",
+			env:  map[string]string{"PAGER": "lv"},
+			want: "lv",
+		},
+		{
+			name: "default",
+			env:  map[string]string{"PAGER": ""},
+			want: "less -R",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pagerCommand(testLookupEnv(tt.env), tt.configPager); got != tt.want {
+				t.Errorf("pagerCommand() \nwant %q \ngot  %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestDisplayRows(t *testing.T) {
+	tests := []struct {
+		contents string
+		width    int
+		want     int
+	}{
+		{contents: "a\nb\nc", width: 10, want: 3},
+		{contents: "a\nb\n", width: 10, want: 2},
+		{contents: "0123456789abc", width: 10, want: 2},
+		{contents: "\x1b[1m0123456789\x1b[0m", width: 10, want: 1},
+		{contents: "あいうえおか", width: 10, want: 2},
+		{contents: "\n\n", width: 10, want: 2},
+	}
+	for i, tt := range tests {
+		if got := DisplayRows(tt.contents, tt.width); got != tt.want {
+			t.Errorf("#%d: DisplayRows(%q, %d) want %d, got %d", i, tt.contents, tt.width, tt.want, got)
+		}
+	}
+}
+
+func TestPager_Start_NotTerminal(t *testing.T) {
+	out := &bytes.Buffer{}
+	p := &Pager{Command: "less -R", Out: out}
+	w, err := p.Start()
+	if err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	if w != out {
+		t.Errorf("Start() want Out as is, got %#v", w)
+	}
+	if err := p.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+}
+
+func TestPager_Start(t *testing.T) {
+	// The quoted argument is passed to the pager by the shell
+	command := `sed "s/^/paged: /"`
+	tests := []struct {
+		name   string
+		writes []string
+		want   string
+	}{
+		{
+			name:   "in the screen",
+			writes: []string{"a\n", "b\n", "c\n"},
+			want:   "a\nb\nc\n",
+		},
+		{
+			name:   "over the screen",
+			writes: []string{"a\nb\n", "c\n", "d\ne\n"},
+			want:   "paged: a\npaged: b\npaged: c\npaged: d\npaged: e\n",
+		},
+		{
+			name:   "wrapped over the screen",
+			writes: []string{"a\n", "0123456789abcdefghij0123456789\n"},
+			want:   "paged: a\npaged: 0123456789abcdefghij0123456789\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+			p := &Pager{
+				Command: command,
+				Out:     out,
+				screenSize: func() (int, int, bool) {
+					return 10, 3, true
+				},
+			}
+			w, err := p.Start()
+			if err != nil {
+				t.Fatalf("Start() error = %v", err)
+			}
+			for _, s := range tt.writes {
+				if _, err := io.WriteString(w, s); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := p.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if got := out.String(); got != tt.want {
+				t.Errorf("output \nwant %q \ngot  %q", tt.want, got)
+			}
+		})
+	}
+}
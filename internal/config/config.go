@@ -18,6 +18,44 @@ type Config struct {
 	Version        int                `yaml:"version"`
 	Profiles       map[string]Profile `yaml:"profiles"`
 	DefalutProfile string             `yaml:"default_profile"`
+	// Browser is the command to open url, "print" prints url and "osc52" copies url to the clipboard of the terminal
+	Browser string `yaml:"browser,omitempty"`
+	// Pager is the command to page long output, $HUC_PAGER is preferred and $PAGER is used when it's empty
+	Pager string `yaml:"pager,omitempty"`
+	// Selector is "fuzzyfinder", "prompt" or the command to select items, e.g. "fzf --multi"
+	Selector string `yaml:"selector,omitempty"`
+	// Actions are the named shell command templates to run for the selected items, e.g. {copy: "echo {{.URL}} | xclip"}
+	Actions map[string]Action `yaml:"actions,omitempty"`
+	// Aliases are the names of subcommands, the expansion is run on the shell when it starts with "!"
+	Aliases map[string]string `yaml:"aliases,omitempty"`
+}
+
+// Action is the shell command template. It runs once per selected item, or
+// once with all selected items when All is true. It's written as the command
+// string, or the mapping of "command" and "all". The values of the template
+// are quoted for the shell, e.g. {{.Title}} is a word even if it has spaces.
+type Action struct {
+	Command string `yaml:"command"`
+	All     bool   `yaml:"all,omitempty"`
+}
+
+func (a *Action) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var command string
+	if err := unmarshal(&command); err == nil {
+		a.Command = command
+		a.All = false
+		return nil
+	}
+	type plain Action
+	return unmarshal((*plain)(a))
+}
+
+func (a Action) MarshalYAML() (interface{}, error) {
+	if !a.All {
+		return a.Command, nil
+	}
+	type plain Action
+	return plain(a), nil
 }
 
 type Profile struct {
@@ -25,6 +63,12 @@ type Profile struct {
 	DefaultGroup      string `yaml:"default_group"`
 	DefaultProject    string `yaml:"default_project"`
 	DefaultAssigneeID int    `yaml:"default_assignee_id"`
+	// Provider is the hosting service of the domain, "github", "gitlab", "gitea" or "forgejo". "github" is used when it's empty
+	Provider string `yaml:"provider,omitempty"`
+	// Proxy is the URL of proxy server for the API, e.g. "http://proxy:8080" or "socks5://localhost:1080". HTTPS_PROXY and HTTP_PROXY are used when it's empty
+	Proxy string `yaml:"proxy,omitempty"`
+	// UnixSocket is the path of unix socket connected instead of the domain, environment variables are expanded
+	UnixSocket string `yaml:"unix_socket,omitempty"`
 }
 
 func NewConfig() *Config {
@@ -102,7 +146,7 @@ func (c *Config) Load() error {
 }
 
 func (c *Config) Save() error {
-	file, err := os.OpenFile(configFilePath, os.O_WRONLY, 0666)
+	file, err := os.OpenFile(configFilePath, os.O_WRONLY|os.O_TRUNC, 0666)
 	if err != nil {
 		return fmt.Errorf("cannot open file, %s", err)
 	}
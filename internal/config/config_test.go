@@ -3,7 +3,10 @@ package config
 import (
 	"io/ioutil"
 	"os"
+	"strings"
 	"testing"
+
+	"github.com/google/go-cmp/cmp"
 )
 
 func setupTestConfig(content string) string {
@@ -85,6 +88,42 @@ version: 1
 			},
 			wantErr: false,
 		},
+		{
+			name: "provider",
+			configContents: `profiles:
+  gitlab.example.com:
+    token: token1
+    provider: gitlab
+default_profile: gitlab.example.com
+`,
+			want: &Config{
+				Profiles: map[string]Profile{
+					"gitlab.example.com": Profile{
+						Token:    "token1",
+						Provider: "gitlab",
+					},
+				},
+				DefalutProfile: "gitlab.example.com",
+			},
+			wantErr: false,
+		},
+		{
+			name: "actions",
+			configContents: `actions:
+  copy: echo {{.URL}} | xclip
+  notes:
+    command: echo "{{range .Items}}- {{.Title}}{{end}}"
+    all: true
+`,
+			want: &Config{
+				Profiles: map[string]Profile{},
+				Actions: map[string]Action{
+					"copy":  {Command: "echo {{.URL}} | xclip"},
+					"notes": {Command: `echo "{{range .Items}}- {{.Title}}{{end}}"`, All: true},
+				},
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -107,6 +146,7 @@ func TestConfigV2_Save(t *testing.T) {
 	type fields struct {
 		Profiles       map[string]Profile
 		DefalutProfile string
+		Aliases        map[string]string
 		Version        int
 	}
 	tests := []struct {
@@ -159,19 +199,37 @@ profiles:
     default_project: default_project2
     default_assignee_id: 2
 default_profile: default_profile
+`,
+			wantErr: false,
+		},
+		{
+			name: "aliases",
+			fields: fields{
+				Profiles: map[string]Profile{},
+				Aliases: map[string]string{
+					"bugs": "issue --labels bug --states OPEN",
+				},
+			},
+			want: `version: 0
+profiles: {}
+default_profile: ""
+aliases:
+  bugs: issue --labels bug --states OPEN
 `,
 			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			configFilePath = setupTestConfig("")
+			// The old contents longer than the new one must be truncated
+			configFilePath = setupTestConfig(strings.Repeat("# old config\n", 20))
 			defer os.Remove(configFilePath)
 
 			c := &Config{
 				Profiles:       tt.fields.Profiles,
 				DefalutProfile: tt.fields.DefalutProfile,
 				Version:        tt.fields.Version,
+				Aliases:        tt.fields.Aliases,
 			}
 
 			if err := c.Save(); (err != nil) != tt.wantErr {
@@ -196,12 +254,12 @@ func Test_getXDGConfigPath(t *testing.T) {
 		{
 			name: "windows",
 			goos: "windows",
-			want: "appdata/lab/config.yml",
+			want: "appdata/huc/config.yml",
 		},
 		{
 			name: "other windows",
 			goos: "linux",
-			want: "home/.config/lab/config.yml",
+			want: "home/.config/huc/config.yml",
 		},
 	}
 	for _, tt := range tests {
@@ -0,0 +1,284 @@
+// Package extension manages the executables named "huc-<name>" that run as the subcommands of huc.
+package extension
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+)
+
+// Prefix is the prefix of the executable of extension
+const Prefix = "huc-"
+
+// Kinds of installed extension
+const (
+	KindGit   = "git"
+	KindLocal = "local"
+)
+
+// Environment variables of the repository context passed to extensions
+const (
+	EnvHost     = "HUC_HOST"
+	EnvRepo     = "HUC_REPO"
+	EnvToken    = "HUC_TOKEN"
+	EnvBranch   = "HUC_BRANCH"
+	EnvProvider = "HUC_PROVIDER"
+)
+
+// ErrLocalUpgrade is returned when the extension installed from the local directory is upgraded.
+var ErrLocalUpgrade = errors.New("local extension cannot be upgraded, it's linked to the directory")
+
+type Extension struct {
+	// Name is the name of subcommand without the prefix
+	Name string
+	// Path is the path of executable
+	Path string
+	Kind string
+	// Source is the remote url of git, or the linked path of local
+	Source string
+}
+
+// Manager installs the extensions into Dir. Extensions on $PATH are also
+// found, but they are not managed.
+type Manager struct {
+	Dir string
+	Out io.Writer
+	Err io.Writer
+
+	lookPath func(file string) (string, error)
+}
+
+func NewManager() *Manager {
+	return &Manager{
+		Dir:      DefaultDir(),
+		Out:      os.Stdout,
+		Err:      os.Stderr,
+		lookPath: exec.LookPath,
+	}
+}
+
+// DefaultDir returns "$XDG_DATA_HOME/huc/extensions", it's "~/.local/share/huc/extensions" by default.
+func DefaultDir() string {
+	dir := os.Getenv("XDG_DATA_HOME")
+	if dir == "" {
+		if runtime.GOOS == "windows" {
+			dir = os.Getenv("LOCALAPPDATA")
+		} else {
+			dir = filepath.Join(os.Getenv("HOME"), ".local", "share")
+		}
+	}
+	return filepath.Join(dir, "huc", "extensions")
+}
+
+// Find returns the path of executable of the extension. The installed
+// extensions are preferred to the ones on $PATH.
+func (m *Manager) Find(name string) (string, bool) {
+	if !validName(name) {
+		return "", false
+	}
+	if ext, err := m.installed(name); err == nil {
+		return ext.Path, true
+	}
+	if path, err := m.lookPath(Prefix + name); err == nil {
+		return path, true
+	}
+	return "", false
+}
+
+// List returns the installed extensions sorted by name.
+func (m *Manager) List() ([]*Extension, error) {
+	entries, err := ioutil.ReadDir(m.Dir)
+	if os.IsNotExist(err) {
+		return []*Extension{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read extensions directory, %s", err)
+	}
+
+	exts := []*Extension{}
+	for _, entry := range entries {
+		if !strings.HasPrefix(entry.Name(), Prefix) {
+			continue
+		}
+		ext, err := m.installed(strings.TrimPrefix(entry.Name(), Prefix))
+		if err != nil {
+			continue
+		}
+		exts = append(exts, ext)
+	}
+	sort.Slice(exts, func(i, j int) bool {
+		return exts[i].Name < exts[j].Name
+	})
+	return exts, nil
+}
+
+// installed returns the extension in Dir. It's the symbolic link to the
+// local executable or directory, or the directory cloned by git.
+func (m *Manager) installed(name string) (*Extension, error) {
+	entry := filepath.Join(m.Dir, Prefix+name)
+	linkInfo, err := os.Lstat(entry)
+	if err != nil {
+		return nil, err
+	}
+	info, err := os.Stat(entry)
+	if err != nil {
+		return nil, err
+	}
+
+	ext := &Extension{Name: name, Path: entry, Kind: KindGit}
+	if info.IsDir() {
+		ext.Path = filepath.Join(entry, Prefix+name)
+		if _, err := os.Stat(ext.Path); err != nil {
+			return nil, fmt.Errorf("Not found executable, %s", ext.Path)
+		}
+	}
+	if linkInfo.Mode()&os.ModeSymlink != 0 {
+		ext.Kind = KindLocal
+		ext.Source, _ = os.Readlink(entry)
+		return ext, nil
+	}
+	if !info.IsDir() {
+		ext.Kind = KindLocal
+		return ext, nil
+	}
+	out, _ := exec.Command("git", "-C", entry, "config", "remote.origin.url").Output()
+	ext.Source = strings.TrimSpace(string(out))
+	return ext, nil
+}
+
+// Install installs the extension from the local path, the url of git
+// repository or "owner/repo" of GitHub. The local path is linked, so the
+// changes in the directory are used without upgrade.
+func (m *Manager) Install(source string) (*Extension, error) {
+	if _, err := os.Stat(source); err == nil {
+		return m.installLocal(source)
+	}
+
+	url := source
+	if !isGitURL(source) {
+		if strings.Count(source, "/") != 1 {
+			return nil, fmt.Errorf("Invalid source, '%s'. Use the local path, git url or owner/repo", source)
+		}
+		url = "https://github.com/" + source + ".git"
+	}
+	name, err := nameOf(strings.TrimSuffix(url, ".git"))
+	if err != nil {
+		return nil, err
+	}
+	if err := m.prepare(name); err != nil {
+		return nil, err
+	}
+
+	if err := m.git("clone", url, filepath.Join(m.Dir, Prefix+name)); err != nil {
+		return nil, fmt.Errorf("cannot clone %s, %s", url, err)
+	}
+	ext, err := m.installed(name)
+	if err != nil {
+		os.RemoveAll(filepath.Join(m.Dir, Prefix+name))
+		return nil, fmt.Errorf("cannot install %s, %s", url, err)
+	}
+	return ext, nil
+}
+
+func (m *Manager) installLocal(source string) (*Extension, error) {
+	path, err := filepath.Abs(source)
+	if err != nil {
+		return nil, err
+	}
+	name, err := nameOf(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.prepare(name); err != nil {
+		return nil, err
+	}
+
+	if err := os.Symlink(path, filepath.Join(m.Dir, Prefix+name)); err != nil {
+		return nil, fmt.Errorf("cannot link %s, %s", path, err)
+	}
+	ext, err := m.installed(name)
+	if err != nil {
+		os.Remove(filepath.Join(m.Dir, Prefix+name))
+		return nil, fmt.Errorf("cannot install %s, %s", path, err)
+	}
+	return ext, nil
+}
+
+// prepare creates Dir and checks that the extension is not installed.
+func (m *Manager) prepare(name string) error {
+	if err := os.MkdirAll(m.Dir, 0755); err != nil {
+		return fmt.Errorf("cannot create directory, %s", err)
+	}
+	if _, err := os.Lstat(filepath.Join(m.Dir, Prefix+name)); err == nil {
+		return fmt.Errorf("Already installed extension, %s", name)
+	}
+	return nil
+}
+
+// Remove removes the installed extension. The linked directory is not removed.
+func (m *Manager) Remove(name string) error {
+	if !validName(strings.TrimPrefix(name, Prefix)) {
+		return fmt.Errorf("Invalid extension name, '%s'", name)
+	}
+	entry := filepath.Join(m.Dir, Prefix+strings.TrimPrefix(name, Prefix))
+	if _, err := os.Lstat(entry); err != nil {
+		return fmt.Errorf("Not found extension, %s", name)
+	}
+	if err := os.RemoveAll(entry); err != nil {
+		return fmt.Errorf("cannot remove extension, %s", err)
+	}
+	return nil
+}
+
+// Upgrade pulls the latest commits of the extension installed by git.
+func (m *Manager) Upgrade(name string) error {
+	if !validName(strings.TrimPrefix(name, Prefix)) {
+		return fmt.Errorf("Invalid extension name, '%s'", name)
+	}
+	ext, err := m.installed(strings.TrimPrefix(name, Prefix))
+	if err != nil {
+		return fmt.Errorf("Not found extension, %s", name)
+	}
+	if ext.Kind == KindLocal {
+		return ErrLocalUpgrade
+	}
+	if err := m.git("-C", filepath.Dir(ext.Path), "pull", "--ff-only"); err != nil {
+		return fmt.Errorf("cannot upgrade %s, %s", ext.Name, err)
+	}
+	return nil
+}
+
+func (m *Manager) git(args ...string) error {
+	cmd := exec.Command("git", args...)
+	cmd.Stdout = m.Out
+	cmd.Stderr = m.Err
+	return cmd.Run()
+}
+
+// validName returns false for the name that is not in Dir, e.g. "../bin".
+func validName(name string) bool {
+	return name != "" && name != "." && !strings.Contains(name, "..") && !strings.ContainsAny(name, `/\`)
+}
+
+func isGitURL(source string) bool {
+	return strings.Contains(source, "://") || strings.HasPrefix(source, "git@")
+}
+
+// nameOf returns the name of extension from the last element of path, e.g. "owner/huc-foo" is "foo".
+func nameOf(path string) (string, error) {
+	base := filepath.Base(filepath.ToSlash(path))
+	if i := strings.LastIndexAny(base, ":/"); i >= 0 {
+		base = base[i+1:]
+	}
+	if !strings.HasPrefix(base, Prefix) || base == Prefix {
+		return "", fmt.Errorf("Invalid extension name, '%s'. The name must start with '%s'", base, Prefix)
+	}
+	return strings.TrimPrefix(base, Prefix), nil
+}
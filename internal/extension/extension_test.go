@@ -0,0 +1,190 @@
+package extension
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func setupTestManager(t *testing.T) (*Manager, string, func()) {
+	root, err := ioutil.TempDir("", "huc-extension")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := &Manager{
+		Dir: filepath.Join(root, "extensions"),
+		Out: ioutil.Discard,
+		Err: ioutil.Discard,
+		lookPath: func(file string) (string, error) {
+			if file == "huc-onpath" {
+				return "/usr/local/bin/huc-onpath", nil
+			}
+			return "", errors.New("not found")
+		},
+	}
+	return m, root, func() { os.RemoveAll(root) }
+}
+
+// writeExtension writes the executable of extension into dir/huc-<name>.
+func writeExtension(t *testing.T, dir, name string) string {
+	src := filepath.Join(dir, Prefix+name)
+	if err := os.MkdirAll(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(src, Prefix+name), []byte("#!/bin/sh\necho "+name+"\n"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return src
+}
+
+func TestManager_InstallLocal(t *testing.T) {
+	m, root, teardown := setupTestManager(t)
+	defer teardown()
+	src := writeExtension(t, root, "foo")
+
+	ext, err := m.Install(src)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	want := &Extension{
+		Name:   "foo",
+		Path:   filepath.Join(m.Dir, "huc-foo", "huc-foo"),
+		Kind:   KindLocal,
+		Source: src,
+	}
+	if !reflect.DeepEqual(ext, want) {
+		t.Errorf("Install() \nwant %#v \ngot  %#v", want, ext)
+	}
+
+	if _, err := m.Install(src); err == nil {
+		t.Errorf("Install() want error for installed extension")
+	}
+	if err := m.Upgrade("foo"); err != ErrLocalUpgrade {
+		t.Errorf("Upgrade() want ErrLocalUpgrade, got %v", err)
+	}
+
+	exts, err := m.List()
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if !reflect.DeepEqual(exts, []*Extension{want}) {
+		t.Errorf("List() \nwant %#v \ngot  %#v", []*Extension{want}, exts)
+	}
+
+	if err := m.Remove("foo"); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(src); err != nil {
+		t.Errorf("Remove() removed the linked directory, %v", err)
+	}
+	if _, ok := m.Find("foo"); ok {
+		t.Errorf("Find() found the removed extension")
+	}
+}
+
+func TestManager_InstallGit(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not available")
+	}
+	m, root, teardown := setupTestManager(t)
+	defer teardown()
+	src := writeExtension(t, root, "bar")
+	for _, args := range [][]string{
+		{"init", "-q"},
+		{"add", "."},
+		{"-c", "user.name=test", "-c", "user.email=test@example.com", "commit", "-q", "-m", "init"},
+	} {
+		if out, err := exec.Command("git", append([]string{"-C", src}, args...)...).CombinedOutput(); err != nil {
+			t.Fatalf("git %v, %s", args, out)
+		}
+	}
+
+	ext, err := m.Install("file://" + src + ".git")
+	if err == nil {
+		t.Fatalf("Install() want error for not found repository, got %#v", ext)
+	}
+	ext, err = m.Install("file://" + src)
+	if err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if ext.Kind != KindGit || ext.Source != "file://"+src {
+		t.Errorf("Install() want git extension, got %#v", ext)
+	}
+	if err := m.Upgrade("bar"); err != nil {
+		t.Errorf("Upgrade() error = %v", err)
+	}
+}
+
+func TestManager_Find(t *testing.T) {
+	m, root, teardown := setupTestManager(t)
+	defer teardown()
+	if _, err := m.Install(writeExtension(t, root, "foo")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{name: "foo", want: filepath.Join(m.Dir, "huc-foo", "huc-foo"), wantOk: true},
+		{name: "onpath", want: "/usr/local/bin/huc-onpath", wantOk: true},
+		{name: "unknown", want: "", wantOk: false},
+		{name: "../foo", want: "", wantOk: false},
+	}
+	for _, tt := range tests {
+		got, ok := m.Find(tt.name)
+		if got != tt.want || ok != tt.wantOk {
+			t.Errorf("Find(%q) \nwant %q, %v \ngot  %q, %v", tt.name, tt.want, tt.wantOk, got, ok)
+		}
+	}
+}
+
+func TestManager_InvalidName(t *testing.T) {
+	m, root, teardown := setupTestManager(t)
+	defer teardown()
+	src := writeExtension(t, root, "foo")
+	if _, err := m.Install(src); err != nil {
+		t.Fatal(err)
+	}
+
+	// "x/../../huc-foo" is joined to the source directory out of Dir
+	for _, name := range []string{"", "..", "x/../../huc-foo", `x\..\..\huc-foo`, "huc-../foo"} {
+		if err := m.Remove(name); err == nil || err.Error() != "Invalid extension name, '"+name+"'" {
+			t.Errorf("Remove(%q) want invalid name error, got %v", name, err)
+		}
+		if err := m.Upgrade(name); err == nil || err.Error() != "Invalid extension name, '"+name+"'" {
+			t.Errorf("Upgrade(%q) want invalid name error, got %v", name, err)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(src, Prefix+"foo")); err != nil {
+		t.Errorf("Remove() removed the directory out of Dir, %v", err)
+	}
+}
+
+func TestNameOf(t *testing.T) {
+	tests := []struct {
+		path    string
+		want    string
+		wantErr bool
+	}{
+		{path: "/path/to/huc-foo", want: "foo"},
+		{path: "https://github.com/owner/huc-foo", want: "foo"},
+		{path: "git@github.com:huc-foo", want: "foo"},
+		{path: "/path/to/foo", wantErr: true},
+		{path: "/path/to/huc-", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := nameOf(tt.path)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("nameOf(%q) error = %v, wantErr %v", tt.path, err, tt.wantErr)
+		}
+		if got != tt.want {
+			t.Errorf("nameOf(%q) \nwant %q \ngot  %q", tt.path, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,246 @@
+// Package fakeserver is the local stand-in of the REST and GraphQL APIs of the
+// hosting services for the tests. It replays the responses of the fixture,
+// the requests are sent to it by the override of $HUB_TEST_HOST.
+//
+// The fixture is recorded from the real API when $HUC_RECORD is set, the
+// requests are forwarded to the original host and the interactions are
+// written to the fixture on Close. The credentials are not recorded.
+package fakeserver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// RecordEnv is the environment variable that enables the record mode.
+const RecordEnv = "HUC_RECORD"
+
+// recordedHeaders are the response headers written to the fixture.
+var recordedHeaders = []string{"Content-Type", "ETag", "Link"}
+
+// Interaction is the pair of request and response in the fixture.
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request matches the request. The body is compared as JSON, any body is
+// matched when it's empty.
+type Request struct {
+	Method string `json:"method"`
+	// Path is the path with the query, e.g. "/api/v4/projects?page=2"
+	Path string          `json:"path"`
+	Body json.RawMessage `json:"body,omitempty"`
+}
+
+// Response is the response of the matched request.
+type Response struct {
+	Status int               `json:"status"`
+	Header map[string]string `json:"header,omitempty"`
+	Body   json.RawMessage   `json:"body,omitempty"`
+}
+
+// Server replays the interactions of the fixture in order of the requests.
+type Server struct {
+	*httptest.Server
+
+	t            testing.TB
+	fixture      string
+	record       bool
+	upstream     http.RoundTripper
+	mu           sync.Mutex
+	interactions []Interaction
+	used         []bool
+}
+
+// Recording returns true when the fixtures are recorded from the real API.
+func Recording() bool {
+	return os.Getenv(RecordEnv) != ""
+}
+
+// New starts the server of the fixture file, e.g. "testdata/issue_show.json".
+func New(t testing.TB, fixture string) *Server {
+	s := &Server{
+		t:        t,
+		fixture:  fixture,
+		record:   Recording(),
+		upstream: http.DefaultTransport,
+	}
+	if !s.record {
+		b, err := ioutil.ReadFile(fixture)
+		if err != nil {
+			t.Fatalf("cannot read fixture, %s", err)
+		}
+		if err := json.Unmarshal(b, &s.interactions); err != nil {
+			t.Fatalf("cannot parse fixture %s, %s", fixture, err)
+		}
+		s.used = make([]bool, len(s.interactions))
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Close stops the server. It writes the fixture in the record mode, or
+// reports the interactions that are not requested.
+func (s *Server) Close() {
+	s.Server.Close()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.record {
+		if err := s.writeFixture(); err != nil {
+			s.t.Errorf("cannot write fixture, %s", err)
+		}
+		return
+	}
+	for i, used := range s.used {
+		if !used {
+			req := s.interactions[i].Request
+			s.t.Errorf("%s: not requested, %s %s", s.fixture, req.Method, req.Path)
+		}
+	}
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var res *Response
+	if s.record {
+		res, err = s.forward(r, body)
+	} else {
+		res, err = s.match(r, body)
+	}
+	if err != nil {
+		s.t.Errorf("%s: %s", s.fixture, err)
+		http.Error(w, err.Error(), http.StatusNotImplemented)
+		return
+	}
+
+	for k, v := range res.Header {
+		w.Header().Set(k, v)
+	}
+	w.WriteHeader(res.Status)
+	w.Write(fromJSON(res.Body))
+}
+
+// match returns the response of the first interaction that is not used yet.
+func (s *Server) match(r *http.Request, body []byte) (*Response, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for i, in := range s.interactions {
+		if s.used[i] || in.Request.Method != r.Method || in.Request.Path != r.URL.RequestURI() {
+			continue
+		}
+		if len(in.Request.Body) > 0 && !equalJSON(in.Request.Body, toJSON(body)) {
+			continue
+		}
+		s.used[i] = true
+		return &s.interactions[i].Response, nil
+	}
+	return nil, fmt.Errorf("Not found interaction, %s %s %s", r.Method, r.URL.RequestURI(), body)
+}
+
+// forward sends the request to the original host and records the interaction.
+func (s *Server) forward(r *http.Request, body []byte) (*Response, error) {
+	scheme := r.Header.Get("X-Original-Scheme")
+	if scheme == "" {
+		scheme = "https"
+	}
+	req, err := http.NewRequest(r.Method, scheme+"://"+r.Host+r.URL.RequestURI(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	for k, v := range r.Header {
+		req.Header[k] = v
+	}
+	req.Header.Del("X-Original-Scheme")
+	req.Header.Del("X-Original-Port")
+	// The recorded body is not compressed
+	req.Header.Del("Accept-Encoding")
+
+	res, err := s.upstream.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	resBody, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	in := Interaction{
+		Request: Request{
+			Method: r.Method,
+			Path:   r.URL.RequestURI(),
+			Body:   toJSON(body),
+		},
+		Response: Response{
+			Status: res.StatusCode,
+			Header: map[string]string{},
+			Body:   toJSON(resBody),
+		},
+	}
+	for _, k := range recordedHeaders {
+		if v := res.Header.Get(k); v != "" {
+			in.Response.Header[k] = v
+		}
+	}
+
+	s.mu.Lock()
+	s.interactions = append(s.interactions, in)
+	s.mu.Unlock()
+	return &in.Response, nil
+}
+
+func (s *Server) writeFixture() error {
+	b, err := json.MarshalIndent(s.interactions, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.fixture), 0755); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(s.fixture, append(b, '\n'), 0644)
+}
+
+// toJSON returns the body as it is when it's JSON, or the JSON string of body.
+func toJSON(body []byte) json.RawMessage {
+	if len(body) == 0 {
+		return nil
+	}
+	if json.Valid(body) {
+		return json.RawMessage(body)
+	}
+	b, _ := json.Marshal(string(body))
+	return json.RawMessage(b)
+}
+
+// fromJSON returns the body written by toJSON.
+func fromJSON(body json.RawMessage) []byte {
+	var s string
+	if err := json.Unmarshal(body, &s); err == nil {
+		return []byte(s)
+	}
+	return body
+}
+
+func equalJSON(a, b []byte) bool {
+	var va, vb interface{}
+	if json.Unmarshal(a, &va) != nil || json.Unmarshal(b, &vb) != nil {
+		return bytes.Equal(a, b)
+	}
+	return reflect.DeepEqual(va, vb)
+}
@@ -0,0 +1,124 @@
+package fakeserver
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestServer_Replay(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fakeserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fixture := filepath.Join(dir, "replay.json")
+	in := `[
+  {"request": {"method": "POST", "path": "/graphql", "body": {"query": "{viewer{login}}"}}, "response": {"status": 200, "body": {"data": "first"}}},
+  {"request": {"method": "POST", "path": "/graphql", "body": {"query": "{viewer{login}}"}}, "response": {"status": 200, "body": {"data": "second"}}},
+  {"request": {"method": "GET", "path": "/api/v4/projects?page=2"}, "response": {"status": 404, "body": "Not Found"}}
+]`
+	if err := ioutil.WriteFile(fixture, []byte(in), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		method     string
+		path       string
+		body       string
+		wantStatus int
+		wantBody   string
+	}{
+		{"POST", "/graphql", `{ "query": "{viewer{login}}" }`, 200, `{"data": "first"}`},
+		{"POST", "/graphql", `{"query":"{viewer{login}}"}`, 200, `{"data": "second"}`},
+		{"GET", "/api/v4/projects?page=2", "", 404, "Not Found"},
+	}
+
+	s := New(t, fixture)
+	defer s.Close()
+	for _, tt := range tests {
+		req, _ := http.NewRequest(tt.method, s.URL+tt.path, strings.NewReader(tt.body))
+		res, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if res.StatusCode != tt.wantStatus {
+			t.Errorf("%s %s: status \nwant %#v \ngot  %#v", tt.method, tt.path, tt.wantStatus, res.StatusCode)
+		}
+		if string(b) != tt.wantBody {
+			t.Errorf("%s %s: body \nwant %#v \ngot  %#v", tt.method, tt.path, tt.wantBody, string(b))
+		}
+	}
+}
+
+func TestServer_Record(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Original-Scheme") != "" {
+			t.Errorf("X-Original-Scheme is forwarded")
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("X-Request-Id", "1234")
+		w.Write([]byte(`{"login":"octocat"}`))
+	}))
+	defer upstream.Close()
+
+	dir, err := ioutil.TempDir("", "fakeserver")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	fixture := filepath.Join(dir, "record.json")
+
+	os.Setenv(RecordEnv, "1")
+	s := New(t, fixture)
+	os.Unsetenv(RecordEnv)
+
+	req, _ := http.NewRequest("GET", s.URL+"/user", nil)
+	req.Host = strings.TrimPrefix(upstream.URL, "http://")
+	req.Header.Set("X-Original-Scheme", "http")
+	req.Header.Set("Authorization", "token secret")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res.Body.Close()
+	s.Close()
+
+	b, err := ioutil.ReadFile(fixture)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []Interaction
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	want := []Interaction{
+		{
+			Request: Request{Method: "GET", Path: "/user"},
+			Response: Response{
+				Status: 200,
+				Header: map[string]string{"Content-Type": "application/json"},
+			},
+		},
+	}
+	if len(got) == 1 {
+		if !equalJSON([]byte(`{"login":"octocat"}`), got[0].Response.Body) {
+			t.Errorf("response body \nwant %s \ngot  %s", `{"login":"octocat"}`, got[0].Response.Body)
+		}
+		got[0].Response.Body = nil
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("fixture \nwant %#v \ngot  %#v", want, got)
+	}
+	if strings.Contains(string(b), "secret") {
+		t.Errorf("the token is recorded, %s", b)
+	}
+}
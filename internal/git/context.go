@@ -0,0 +1,296 @@
+package git
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// ErrNotGitRepository is returned when the current directory is not in a git repository.
+var ErrNotGitRepository = errors.New("Not a git repository (or any of the parent directories): .git")
+
+// RepositoryContext is the state of the local repository.
+// It's resolved by reading files in the git directory without running git command.
+type RepositoryContext struct {
+	// GitDir is the git directory of the current worktree
+	GitDir string
+	// CommonDir is the git directory shared with all worktrees
+	CommonDir string
+	// WorkTree is the top level directory of the working tree. It's empty in a bare repository
+	WorkTree string
+	// Branch is the current branch name. It's empty on detached HEAD
+	Branch string
+	// Head is the commit SHA of HEAD. It's empty on unborn branch
+	Head    string
+	Remotes []*Remote
+
+	config gitConfig
+}
+
+// Remote is a remote setting in the repository config.
+type Remote struct {
+	Name string
+	URL  string
+}
+
+// Detached returns true when HEAD is not pointing any branch.
+func (c *RepositoryContext) Detached() bool {
+	return c.Branch == ""
+}
+
+// Config returns the value of the key in repository, user and system config.
+func (c *RepositoryContext) Config(key string) string {
+	return c.config.Get(key)
+}
+
+var (
+	currentContextOnce sync.Once
+	currentContext     *RepositoryContext
+	currentContextErr  error
+)
+
+// CurrentContext returns the context of the repository in the current directory.
+// The result is cached for the life of the process.
+func CurrentContext() (*RepositoryContext, error) {
+	currentContextOnce.Do(func() {
+		dir, err := os.Getwd()
+		if err != nil {
+			currentContextErr = fmt.Errorf("cannot get current dir path, %s", err)
+			return
+		}
+		currentContext, currentContextErr = ResolveContext(dir, os.Getenv)
+	})
+	return currentContext, currentContextErr
+}
+
+// ResolveContext resolves the repository context of dir.
+// It supports worktrees, ".git" gitdir files, GIT_DIR and GIT_WORK_TREE.
+func ResolveContext(dir string, getenv func(string) string) (*RepositoryContext, error) {
+	ctx := &RepositoryContext{}
+
+	if gitDir := getenv("GIT_DIR"); gitDir != "" {
+		ctx.GitDir = absPath(dir, gitDir)
+		if !isGitDirectory(ctx.GitDir) {
+			return nil, ErrNotGitRepository
+		}
+		ctx.WorkTree = dir
+	} else {
+		gitDir, workTree, err := findGitDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		ctx.GitDir = gitDir
+		ctx.WorkTree = workTree
+	}
+
+	ctx.CommonDir = ctx.GitDir
+	if b, err := ioutil.ReadFile(filepath.Join(ctx.GitDir, "commondir")); err == nil {
+		ctx.CommonDir = absPath(ctx.GitDir, strings.TrimSpace(string(b)))
+	}
+
+	paths := append(globalGitConfigPaths(), filepath.Join(ctx.CommonDir, "config"))
+	entries := readGitConfigEntries(paths...)
+	ctx.config = newGitConfig(entries)
+
+	if workTree := getenv("GIT_WORK_TREE"); workTree != "" {
+		ctx.WorkTree = absPath(dir, workTree)
+	} else if workTree := ctx.config.Get("core.worktree"); workTree != "" {
+		ctx.WorkTree = absPath(ctx.GitDir, workTree)
+	} else if ctx.config.Get("core.bare") == "true" {
+		ctx.WorkTree = ""
+	}
+
+	if err := ctx.readHead(); err != nil {
+		return nil, err
+	}
+	ctx.Remotes = remotesFromConfig(entries)
+
+	return ctx, nil
+}
+
+// findGitDir walks up from dir and returns the git directory and the working tree.
+func findGitDir(dir string) (string, string, error) {
+	pos := dir
+	for {
+		dotGit := filepath.Join(pos, ".git")
+		if fi, err := os.Stat(dotGit); err == nil {
+			if fi.IsDir() && isGitDirectory(dotGit) {
+				return dotGit, pos, nil
+			}
+			if !fi.IsDir() {
+				gitDir, err := readGitDirFile(dotGit)
+				if err != nil {
+					return "", "", err
+				}
+				return gitDir, pos, nil
+			}
+		}
+
+		// Bare repository, its directory may not have ".git" suffix
+		if isBareRepository(pos) {
+			return pos, "", nil
+		}
+
+		parent := filepath.Dir(pos)
+		if parent == pos {
+			return "", "", ErrNotGitRepository
+		}
+		pos = parent
+	}
+}
+
+// readGitDirFile reads ".git" file of worktrees and submodules, e.g. "gitdir: ../.git/worktrees/foo".
+func readGitDirFile(path string) (string, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("cannot read gitdir file, %s", err)
+	}
+	content := strings.TrimSpace(string(b))
+	if !strings.HasPrefix(content, "gitdir:") {
+		return "", fmt.Errorf("invalid gitdir file, %s", path)
+	}
+	gitDir := absPath(filepath.Dir(path), strings.TrimSpace(strings.TrimPrefix(content, "gitdir:")))
+	if !isGitDirectory(gitDir) {
+		return "", fmt.Errorf("invalid gitdir, %s", gitDir)
+	}
+	return gitDir, nil
+}
+
+// isBareRepository returns true when dir has HEAD, objects and refs of git.
+func isBareRepository(dir string) bool {
+	for _, name := range []string{"HEAD", "objects", "refs"} {
+		if _, err := os.Stat(filepath.Join(dir, name)); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func isGitDirectory(dir string) bool {
+	if _, err := os.Stat(filepath.Join(dir, "HEAD")); err != nil {
+		return false
+	}
+	// A worktree has not "objects" and "refs" directory, these are in the common directory
+	if _, err := os.Stat(filepath.Join(dir, "commondir")); err == nil {
+		return true
+	}
+	_, err := os.Stat(filepath.Join(dir, "objects"))
+	return err == nil
+}
+
+func (c *RepositoryContext) readHead() error {
+	b, err := ioutil.ReadFile(filepath.Join(c.GitDir, "HEAD"))
+	if err != nil {
+		return fmt.Errorf("cannot read HEAD, %s", err)
+	}
+	head := strings.TrimSpace(string(b))
+
+	if !strings.HasPrefix(head, "ref:") {
+		c.Head = head
+		return nil
+	}
+
+	ref := strings.TrimSpace(strings.TrimPrefix(head, "ref:"))
+	c.Branch = strings.TrimPrefix(ref, "refs/heads/")
+	c.Head = c.resolveRef(ref)
+	return nil
+}
+
+// ResolveBranch returns the commit SHA of the local branch, or the remote tracking branch when the local one is not found.
+func (c *RepositoryContext) ResolveBranch(branch string) string {
+	if sha := c.resolveRef("refs/heads/" + branch); sha != "" {
+		return sha
+	}
+	for _, remote := range c.Remotes {
+		if sha := c.resolveRef("refs/remotes/" + remote.Name + "/" + branch); sha != "" {
+			return sha
+		}
+	}
+	return ""
+}
+
+// RelativePath returns the slash separated path of path from the top level of the working tree.
+func (c *RepositoryContext) RelativePath(path string) (string, error) {
+	if c.WorkTree == "" {
+		return "", errors.New("This operation must be run in a work tree")
+	}
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+	if evaluated, err := filepath.EvalSymlinks(abs); err == nil {
+		abs = evaluated
+	}
+	workTree := c.WorkTree
+	if evaluated, err := filepath.EvalSymlinks(workTree); err == nil {
+		workTree = evaluated
+	}
+	rel, err := filepath.Rel(workTree, abs)
+	if err != nil {
+		return "", err
+	}
+	if rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside repository", path)
+	}
+	if rel == "." {
+		return "", nil
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// resolveRef returns the commit SHA of ref from loose refs or packed-refs.
+func (c *RepositoryContext) resolveRef(ref string) string {
+	for _, dir := range []string{c.GitDir, c.CommonDir} {
+		if b, err := ioutil.ReadFile(filepath.Join(dir, filepath.FromSlash(ref))); err == nil {
+			return strings.TrimSpace(string(b))
+		}
+	}
+
+	file, err := os.Open(filepath.Join(c.CommonDir, "packed-refs"))
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 && fields[1] == ref {
+			return fields[0]
+		}
+	}
+	return ""
+}
+
+// remotesFromConfig returns the remotes in the order of config files, the
+// url of the remote defined twice is the last one.
+func remotesFromConfig(entries []gitConfigEntry) []*Remote {
+	remotes := []*Remote{}
+	byName := map[string]*Remote{}
+	for _, e := range entries {
+		if !strings.HasPrefix(e.key, "remote.") || !strings.HasSuffix(e.key, ".url") {
+			continue
+		}
+		name := strings.TrimSuffix(strings.TrimPrefix(e.key, "remote."), ".url")
+		if r, ok := byName[name]; ok {
+			r.URL = e.value
+			continue
+		}
+		byName[name] = &Remote{Name: name, URL: e.value}
+		remotes = append(remotes, byName[name])
+	}
+	return remotes
+}
+
+func absPath(base, path string) string {
+	path = expandHome(path)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(base, path)
+	}
+	return filepath.Clean(path)
+}
@@ -0,0 +1,210 @@
+package git
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testSHA = "0123456789abcdef0123456789abcdef01234567"
+
+func emptyEnv(string) string {
+	return ""
+}
+
+func setupTestRepository(t *testing.T, files map[string]string) string {
+	dir, err := ioutil.TempDir("", "huc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Resolve symlink of temporary directory, e.g. /var -> /private/var on macOS
+	dir, err = filepath.EvalSymlinks(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, gitDir := range []string{".git", "main.git"} {
+		if _, err := os.Stat(filepath.Join(dir, gitDir, "HEAD")); err == nil {
+			if err := os.MkdirAll(filepath.Join(dir, gitDir, "objects"), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return dir
+}
+
+func TestResolveContext(t *testing.T) {
+	dir := setupTestRepository(t, map[string]string{
+		// Repository
+		".git/HEAD":              "ref: refs/heads/feature/foo\n",
+		".git/config":            "[core]\n\tbare = false\n[remote \"origin\"]\n\turl = git@github.com:lighttiger2505/huc.git\n[remote \"upstream\"]\n\turl = https://github.com/upstream/huc\n",
+		".git/refs/heads/master": testSHA + "\n",
+		".git/packed-refs":       "# pack-refs with: peeled fully-peeled sorted\n" + testSHA + " refs/heads/feature/foo\n",
+		"sub/dir/file":           "",
+		// Worktree
+		".git/worktrees/wt/HEAD":      "ref: refs/heads/master\n",
+		".git/worktrees/wt/commondir": "../..\n",
+		"wt/.git":                     "gitdir: ../.git/worktrees/wt\n",
+		// Detached HEAD
+		"detached/.git":                     "gitdir: ../.git/worktrees/detached\n",
+		".git/worktrees/detached/HEAD":      testSHA + "\n",
+		".git/worktrees/detached/commondir": "../..\n",
+		// GIT_DIR
+		"main.git/HEAD":   "ref: refs/heads/develop\n",
+		"main.git/config": "[core]\n\tbare = true\n",
+		// Bare repository without ".git" suffix, the remotes are not sorted by name
+		"bare/HEAD":               "ref: refs/heads/master\n",
+		"bare/objects/info/packs": "",
+		"bare/refs/heads/master":  testSHA + "\n",
+		"bare/config":             "[core]\n\tbare = true\n[remote \"upstream\"]\n\turl = https://github.com/upstream/huc\n[remote \"origin\"]\n\turl = git@github.com:lighttiger2505/huc.git\n",
+	})
+	defer os.RemoveAll(dir)
+
+	remotes := []*Remote{
+		{Name: "origin", URL: "git@github.com:lighttiger2505/huc.git"},
+		{Name: "upstream", URL: "https://github.com/upstream/huc"},
+	}
+
+	tests := []struct {
+		name    string
+		dir     string
+		env     map[string]string
+		want    *RepositoryContext
+		wantErr error
+	}{
+		{
+			name: "sub directory",
+			dir:  filepath.Join(dir, "sub", "dir"),
+			want: &RepositoryContext{
+				GitDir:    filepath.Join(dir, ".git"),
+				CommonDir: filepath.Join(dir, ".git"),
+				WorkTree:  dir,
+				Branch:    "feature/foo",
+				Head:      testSHA,
+				Remotes:   remotes,
+			},
+		},
+		{
+			name: "worktree",
+			dir:  filepath.Join(dir, "wt"),
+			want: &RepositoryContext{
+				GitDir:    filepath.Join(dir, ".git", "worktrees", "wt"),
+				CommonDir: filepath.Join(dir, ".git"),
+				WorkTree:  filepath.Join(dir, "wt"),
+				Branch:    "master",
+				Head:      testSHA,
+				Remotes:   remotes,
+			},
+		},
+		{
+			name: "detached HEAD",
+			dir:  filepath.Join(dir, "detached"),
+			want: &RepositoryContext{
+				GitDir:    filepath.Join(dir, ".git", "worktrees", "detached"),
+				CommonDir: filepath.Join(dir, ".git"),
+				WorkTree:  filepath.Join(dir, "detached"),
+				Head:      testSHA,
+				Remotes:   remotes,
+			},
+		},
+		{
+			name: "GIT_DIR and GIT_WORK_TREE",
+			dir:  filepath.Join(dir, "sub"),
+			env:  map[string]string{"GIT_DIR": "../main.git", "GIT_WORK_TREE": "dir"},
+			want: &RepositoryContext{
+				GitDir:    filepath.Join(dir, "main.git"),
+				CommonDir: filepath.Join(dir, "main.git"),
+				WorkTree:  filepath.Join(dir, "sub", "dir"),
+				Branch:    "develop",
+				Remotes:   []*Remote{},
+			},
+		},
+		{
+			name: "bare repository",
+			dir:  filepath.Join(dir, "bare", "refs"),
+			want: &RepositoryContext{
+				GitDir:    filepath.Join(dir, "bare"),
+				CommonDir: filepath.Join(dir, "bare"),
+				Branch:    "master",
+				Head:      testSHA,
+				Remotes:   []*Remote{remotes[1], remotes[0]},
+			},
+		},
+		{
+			name:    "not git repository",
+			dir:     os.TempDir(),
+			env:     map[string]string{"GIT_DIR": filepath.Join(dir, "sub")},
+			wantErr: ErrNotGitRepository,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			getenv := func(key string) string {
+				return tt.env[key]
+			}
+			got, err := ResolveContext(tt.dir, getenv)
+			if err != tt.wantErr {
+				t.Fatalf("ResolveContext() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != nil {
+				got.config = nil
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ResolveContext() \nwant %#v \ngot  %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestParseGitConfig(t *testing.T) {
+	cfg := parseGitConfig(strings.NewReader(`
+# comment
+[core]
+	editor = vim ; comment
+[url "git@github.com:"]
+	insteadOf = gh:
+	insteadOf = "github:"
+[remote.origin]
+	url = "https://github.com/foo/bar#baz"
+[Branch "Feature/Foo"]
+	Remote = origin
+`))
+	want := gitConfig{
+		"core.editor":                   {"vim"},
+		"url.git@github.com:.insteadof": {"gh:", "github:"},
+		"remote.origin.url":             {"https://github.com/foo/bar#baz"},
+		"branch.Feature/Foo.remote":     {"origin"},
+	}
+	if !reflect.DeepEqual(cfg, want) {
+		t.Errorf("parseGitConfig() \nwant %#v \ngot  %#v", want, cfg)
+	}
+}
+
+func TestReadGitConfigFiles_IncludeOrder(t *testing.T) {
+	dir := setupTestRepository(t, map[string]string{
+		"first.inc":  "[core]\n\teditor = first\n",
+		"second.inc": "[core]\n\teditor = second\n",
+		"config":     "[core]\n\teditor = top\n[include]\n\tpath = first.inc\n[user]\n\tname = foo\n[include]\n\tpath = second.inc\n",
+	})
+	defer os.RemoveAll(dir)
+
+	// Repeat because the order of map iteration is random
+	for i := 0; i < 10; i++ {
+		cfg := readGitConfigFiles(filepath.Join(dir, "config"))
+		want := []string{"top", "first", "second"}
+		if got := cfg["core.editor"]; !reflect.DeepEqual(want, got) {
+			t.Fatalf("core.editor \nwant %#v \ngot  %#v", want, got)
+		}
+	}
+}
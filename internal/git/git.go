@@ -12,6 +12,10 @@ import (
 type Client interface {
 	RemoteInfos() ([]*RemoteInfo, error)
 	CurrentRemoteBranch() (string, error)
+	// RepositoryContext returns the state of the local repository
+	RepositoryContext() (*RepositoryContext, error)
+	// IsPushed returns true when the commit is contained in a remote tracking branch
+	IsPushed(sha string) (bool, error)
 }
 
 type GitClient struct {
@@ -22,98 +26,90 @@ func NewGitClient() Client {
 	return &GitClient{}
 }
 
+func (g *GitClient) RepositoryContext() (*RepositoryContext, error) {
+	return CurrentContext()
+}
+
 func (g *GitClient) RemoteInfos() ([]*RemoteInfo, error) {
-	// Get remote repositorys
-	remotes, err := gitOutput("remote")
+	ctx, err := g.RepositoryContext()
 	if err != nil {
-		return nil, fmt.Errorf("Failed collect git remote. %s", err)
+		return nil, err
 	}
-	if len(remotes) == 0 {
+	return remoteInfos(ctx)
+}
+
+// remoteInfos returns the remotes of hosting services in the order of config.
+func remoteInfos(ctx *RepositoryContext) ([]*RemoteInfo, error) {
+	if len(ctx.Remotes) == 0 {
 		return nil, errors.New("No remote setting in this repository")
 	}
 
 	// Extract domain, namespace, repository name from git remote url
+	parser := newURLParser(ctx.config)
 	var remoteInfos []*RemoteInfo
-	for _, remote := range remotes {
-		url, err := gitOutput("remote", "get-url", remote)
+	for _, remote := range ctx.Remotes {
+		remoteInfo, err := parser.RemoteInfo(remote.Name, remote.URL)
 		if err != nil {
-			return nil, fmt.Errorf("Failed get git remote url. %s", err)
+			// Skip the remote that is not a hosting service, e.g. local path
+			continue
 		}
-		remoteInfo := NewRemoteInfo(remote, url[0])
 		remoteInfos = append(remoteInfos, remoteInfo)
 	}
+	if len(remoteInfos) == 0 {
+		return nil, errors.New("No remote hosting service in this repository")
+	}
 	return remoteInfos, nil
 }
 
+// CurrentRemoteBranch returns the current branch, or the commit SHA on detached HEAD.
 func (g *GitClient) CurrentRemoteBranch() (string, error) {
-	// 	branches, err := gitOutput("branch", "-a")
-	// 	if err != nil {
-	// 		return "", fmt.Errorf("Failed get git branches. %s", err)
-	// 	}
-
-	currentBranch, err := CurrentBranch()
+	ctx, err := g.RepositoryContext()
 	if err != nil {
 		return "", err
 	}
-	return currentBranch, nil
-
-	// 	remoteBranch := fmt.Sprintf("%s/%s", remote.Remote, currentBranch)
-	// 	for _, branch := range branches {
-	// 		trimBranch := strings.TrimSpace(branch)
-	// 		if strings.HasSuffix(trimBranch, remoteBranch) {
-	// 			return currentBranch, nil
-	// 		}
-	// 	}
-	// 	return "master", nil
-
+	if ctx.Detached() {
+		return ctx.Head, nil
+	}
+	return ctx.Branch, nil
 }
 
-func IsGitDirReverseTop() (bool, error) {
-	pos, err := os.Getwd()
+func (g *GitClient) IsPushed(sha string) (bool, error) {
+	branches, err := gitOutput("branch", "--remotes", "--contains", sha)
 	if err != nil {
-		return false, fmt.Errorf("cannot get current dir path, %s", err)
-	}
-
-	for {
-		if IsGitDir(pos) {
-			return true, nil
-		}
-		if pos == "/" {
-			return false, nil
-		}
-		pos = filepath.Dir(pos)
+		return false, err
 	}
+	return len(branches) > 0, nil
 }
 
-func IsGitDir(dir string) bool {
-	gitdir := filepath.Join(dir, ".git")
-	_, err := execCommand("git", "--git-dir="+gitdir, "rev-parse", "--git-dir").CombinedOutput()
+func IsGitDirReverseTop() (bool, error) {
+	_, err := CurrentContext()
+	if err == ErrNotGitRepository {
+		return false, nil
+	}
 	if err != nil {
-		return false
+		return false, err
 	}
-	return true
+	return true, nil
 }
 
 func CurrentBranch() (string, error) {
-	// Get remote repositorys
-	branches, err := gitOutput("branch")
+	ctx, err := CurrentContext()
 	if err != nil {
-		return "", fmt.Errorf("Failed get git branches. %s", err)
+		return "", err
 	}
-
-	currentPrefix := "*"
-	currentBranch := ""
-	for _, branch := range branches {
-		if strings.HasPrefix(branch, currentPrefix) {
-			trimPrefix := strings.TrimPrefix(branch, currentPrefix)
-			currentBranch = strings.Trim(trimPrefix, " ")
-		}
+	if ctx.Detached() {
+		return "", errors.New("Not found current branch, HEAD is detached")
 	}
+	return ctx.Branch, nil
+}
 
-	if currentBranch == "" {
-		return "", errors.New("Not found current branch")
+// DefaultBranch returns the default branch of the remote, e.g. "main" of "origin/HEAD".
+func DefaultBranch(remote string) (string, error) {
+	outputs, err := gitOutput("symbolic-ref", "--short", "refs/remotes/"+remote+"/HEAD")
+	if err != nil || len(outputs) == 0 {
+		return "", fmt.Errorf("Not found default branch of %s", remote)
 	}
-	return currentBranch, nil
+	return strings.TrimPrefix(outputs[0], remote+"/"), nil
 }
 
 func GitEditor() (string, error) {
@@ -234,6 +230,8 @@ func gitConfigCommand(args []string) []string {
 type MockClient struct {
 	MockRemoteInfos         func() ([]*RemoteInfo, error)
 	MockCurrentRemoteBranch func() (string, error)
+	MockRepositoryContext   func() (*RepositoryContext, error)
+	MockIsPushed            func(sha string) (bool, error)
 }
 
 func (m *MockClient) RemoteInfos() ([]*RemoteInfo, error) {
@@ -243,3 +241,11 @@ func (m *MockClient) RemoteInfos() ([]*RemoteInfo, error) {
 func (m *MockClient) CurrentRemoteBranch() (string, error) {
 	return m.MockCurrentRemoteBranch()
 }
+
+func (m *MockClient) RepositoryContext() (*RepositoryContext, error) {
+	return m.MockRepositoryContext()
+}
+
+func (m *MockClient) IsPushed(sha string) (bool, error) {
+	return m.MockIsPushed(sha)
+}
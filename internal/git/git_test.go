@@ -66,22 +66,28 @@ func TestHelperProcess(*testing.T) {
 }
 
 func TestRemoteInfos(t *testing.T) {
-	execCommand = helperCommand
-	defer func() { execCommand = exec.Command }()
+	dir := setupTestRepository(t, map[string]string{
+		".git/HEAD":   "ref: refs/heads/master\n",
+		".git/config": "[remote \"origin\"]\n\turl = git@gitlab.com:lighttiger2505/lab.git\n[remote \"local\"]\n\turl = /path/to/lab.git\n",
+	})
+	defer os.RemoveAll(dir)
 
-	client := NewGitClient()
-	results, err := client.RemoteInfos()
+	ctx, err := ResolveContext(dir, emptyEnv)
+	if err != nil {
+		t.Fatalf("failed resolve context, %s", err)
+	}
+	results, err := remoteInfos(ctx)
 	if err != nil {
 		t.Errorf("echo: %v", err)
 	}
 
-	got := results[0]
-	want := &RemoteInfo{
+	got := results
+	want := []*RemoteInfo{{
 		Remote:     "origin",
 		Domain:     "gitlab.com",
 		Group:      "lighttiger2505",
 		Repository: "lab",
-	}
+	}}
 	if !reflect.DeepEqual(want, got) {
 		t.Errorf("Invalid return value. want %v, got %v", want, got)
 	}
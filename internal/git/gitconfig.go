@@ -0,0 +1,186 @@
+package git
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// gitConfig is values of git config files.
+// The key is "section.subsection.name", section and name are lower case the same as git.
+type gitConfig map[string][]string
+
+// Get returns the last value of key.
+func (c gitConfig) Get(key string) string {
+	values := c[key]
+	if len(values) == 0 {
+		return ""
+	}
+	return values[len(values)-1]
+}
+
+// readGitConfigFiles reads files in order, later file overrides earlier one.
+// Not exist files are ignored.
+func readGitConfigFiles(paths ...string) gitConfig {
+	return newGitConfig(readGitConfigEntries(paths...))
+}
+
+// readGitConfigEntries returns the entries of files in the order of reading.
+func readGitConfigEntries(paths ...string) []gitConfigEntry {
+	entries := []gitConfigEntry{}
+	for _, path := range paths {
+		entries = readGitConfigFile(entries, path, 0)
+	}
+	return entries
+}
+
+func readGitConfigFile(entries []gitConfigEntry, path string, depth int) []gitConfigEntry {
+	// Guard for circular include
+	if depth > 10 {
+		return entries
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return entries
+	}
+	defer file.Close()
+
+	// The included file is read at the position of include.path, the same as git.
+	// The values after it override the included values.
+	for _, e := range parseGitConfigEntries(file) {
+		if e.key != "include.path" {
+			entries = append(entries, e)
+			continue
+		}
+		include := expandHome(e.value)
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		entries = readGitConfigFile(entries, include, depth+1)
+	}
+	return entries
+}
+
+func parseGitConfig(r io.Reader) gitConfig {
+	return newGitConfig(parseGitConfigEntries(r))
+}
+
+func newGitConfig(entries []gitConfigEntry) gitConfig {
+	cfg := gitConfig{}
+	for _, e := range entries {
+		cfg[e.key] = append(cfg[e.key], e.value)
+	}
+	return cfg
+}
+
+// gitConfigEntry is the value of the key in a git config file.
+type gitConfigEntry struct {
+	key   string
+	value string
+}
+
+// parseGitConfigEntries returns the entries in the order of the file.
+func parseGitConfigEntries(r io.Reader) []gitConfigEntry {
+	entries := []gitConfigEntry{}
+	section := ""
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		for strings.HasSuffix(line, "\\") && scanner.Scan() {
+			line = strings.TrimSuffix(line, "\\") + strings.TrimSpace(scanner.Text())
+		}
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			end := strings.LastIndex(line, "]")
+			if end < 0 {
+				continue
+			}
+			section = parseGitConfigSection(line[1:end])
+			line = strings.TrimSpace(line[end+1:])
+			if line == "" {
+				continue
+			}
+		}
+
+		name, value := line, "true"
+		if i := strings.Index(line, "="); i >= 0 {
+			name = strings.TrimSpace(line[:i])
+			value = parseGitConfigValue(strings.TrimSpace(line[i+1:]))
+		}
+		entries = append(entries, gitConfigEntry{key: section + "." + strings.ToLower(name), value: value})
+	}
+	return entries
+}
+
+// parseGitConfigSection parses `remote "origin"` and legacy `remote.origin` style section header
+func parseGitConfigSection(header string) string {
+	header = strings.TrimSpace(header)
+	if i := strings.Index(header, "\""); i >= 0 {
+		name := strings.ToLower(strings.TrimSpace(header[:i]))
+		sub := strings.TrimSuffix(header[i+1:], "\"")
+		sub = strings.Replace(sub, "\\\"", "\"", -1)
+		sub = strings.Replace(sub, "\\\\", "\\", -1)
+		return name + "." + sub
+	}
+	if i := strings.Index(header, "."); i >= 0 {
+		return strings.ToLower(header[:i]) + "." + strings.ToLower(header[i+1:])
+	}
+	return strings.ToLower(header)
+}
+
+func parseGitConfigValue(raw string) string {
+	var b strings.Builder
+	inQuote := false
+	for i := 0; i < len(raw); i++ {
+		c := raw[i]
+		switch {
+		case c == '"':
+			inQuote = !inQuote
+		case c == '\\' && i+1 < len(raw):
+			i++
+			switch raw[i] {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default:
+				b.WriteByte(raw[i])
+			}
+		case (c == '#' || c == ';') && !inQuote:
+			return strings.TrimSpace(b.String())
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// globalGitConfigPaths returns paths of system and user config in the order of priority.
+func globalGitConfigPaths() []string {
+	home := os.Getenv("HOME")
+	xdgConfigHome := os.Getenv("XDG_CONFIG_HOME")
+	if xdgConfigHome == "" {
+		xdgConfigHome = filepath.Join(home, ".config")
+	}
+
+	paths := []string{"/etc/gitconfig", filepath.Join(xdgConfigHome, "git", "config")}
+	if global := os.Getenv("GIT_CONFIG_GLOBAL"); global != "" {
+		paths = append(paths, global)
+	} else {
+		paths = append(paths, filepath.Join(home, ".gitconfig"))
+	}
+	return paths
+}
+
+func expandHome(path string) string {
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		return filepath.Join(os.Getenv("HOME"), strings.TrimPrefix(path, "~"))
+	}
+	return path
+}
@@ -2,39 +2,27 @@ package git
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
 )
 
 type RemoteInfo struct {
-	Remote     string
-	Domain     string
+	Remote string
+	Domain string
+	// Alias is the host alias of ssh config, e.g. "github-work" of github.com, it's empty without alias
+	Alias      string
 	Group      string
 	SubGroup   string
 	Repository string
 }
 
+// NewRemoteInfo parses remote url without any rewrites.
 func NewRemoteInfo(remote, url string) *RemoteInfo {
-	url = strings.TrimPrefix(url, "ssh://")
-	url = strings.TrimPrefix(url, "https://")
-	url = strings.TrimPrefix(url, "git@")
-	splitUrl := regexp.MustCompile("/|:").Split(url, -1)
-	if len(splitUrl) > 3 {
-		// Apply subgroup
-		return &RemoteInfo{
-			Remote:     remote,
-			Repository: strings.TrimSuffix(splitUrl[len(splitUrl)-1], ".git"),
-			SubGroup:   strings.Join(splitUrl[2:len(splitUrl)-1], "/"),
-			Group:      splitUrl[1],
-			Domain:     splitUrl[0],
-		}
-	}
-	return &RemoteInfo{
-		Remote:     remote,
-		Repository: strings.TrimSuffix(splitUrl[len(splitUrl)-1], ".git"),
-		Group:      splitUrl[len(splitUrl)-2],
-		Domain:     splitUrl[len(splitUrl)-3],
+	parser := &URLParser{}
+	info, err := parser.RemoteInfo(remote, url)
+	if err != nil {
+		return &RemoteInfo{Remote: remote}
 	}
+	return info
 }
 
 func (r *RemoteInfo) RepositoryFullName() string {
@@ -60,8 +48,9 @@ func (r *RemoteInfo) BranchPath(branch string, path string) string {
 	return strings.Join([]string{r.BranchUrl(branch), path}, "/")
 }
 
+// BranchFileWithLine returns url of file with line anchor, line is the fragment such as "L10" or "L10-L20".
 func (r *RemoteInfo) BranchFileWithLine(branch string, path string, line string) string {
-	return strings.Join([]string{r.BranchPath(branch, path), line}, "/")
+	return strings.Join([]string{r.BranchPath(branch, path), line}, "#")
 }
 
 func (r *RemoteInfo) Subpage(subpage string) string {
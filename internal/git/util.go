@@ -1,6 +1,7 @@
 package git
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -16,6 +17,8 @@ type RemoteCollecter struct {
 	UI        ui.UI
 	GitClient Client
 	Cfg       *config.Config
+	// ReadOnly collects the target without asking the token and saving the config.
+	ReadOnly bool
 }
 
 type GitLabProjectInfo struct {
@@ -50,8 +53,9 @@ func (r *GitLabProjectInfo) BranchPath(branch string, path string) string {
 	return strings.Join([]string{r.BranchUrl(branch), path}, "/")
 }
 
+// BranchFileWithLine returns url of file with line anchor, line is the fragment such as "L10" or "L10-L20".
 func (r *GitLabProjectInfo) BranchFileWithLine(branch string, path string, line string) string {
-	return strings.Join([]string{r.BranchPath(branch, path), line}, "/")
+	return strings.Join([]string{r.BranchPath(branch, path), line}, "#")
 }
 
 func (r *GitLabProjectInfo) Subpage(subpage string) string {
@@ -70,11 +74,11 @@ func (c *RemoteCollecter) CollectTarget(project, profile string) (*GitLabProject
 	pInfo := &GitLabProjectInfo{}
 	var err error
 
-	isGitDir, err := IsGitDirReverseTop()
-	if err != nil {
+	_, err = c.GitClient.RepositoryContext()
+	if err != nil && !errors.Is(err, ErrNotGitRepository) {
 		return nil, err
 	}
-	if isGitDir {
+	if err == nil {
 		pInfo = c.collectTargetByDefaultConfig(pInfo)
 		pInfo, err = c.collectTargetByLocalRepository(pInfo)
 		if err != nil {
@@ -128,31 +132,39 @@ func (c *RemoteCollecter) collectTargetByLocalRepository(pInfo *GitLabProjectInf
 	var domain, token string
 
 	domain = targetRepo.Domain
-	if !c.Cfg.HasDomain(domain) {
-		c.UI.Message(fmt.Sprintf("Not found this domain [%s].", domain))
-		c.Cfg.SetProfile(domain, config.Profile{})
-		if err := c.Cfg.Save(); err != nil {
-			return nil, err
+	// The profile named by ssh alias is preferred, the accounts of the same
+	// host are separated by the aliases, e.g. "github-work" and "github-personal"
+	name := domain
+	if targetRepo.Alias != "" && c.Cfg.HasDomain(targetRepo.Alias) {
+		name = targetRepo.Alias
+	}
+	if !c.Cfg.HasDomain(name) {
+		c.Cfg.SetProfile(name, config.Profile{})
+		if !c.ReadOnly {
+			c.UI.Message(fmt.Sprintf("Not found this domain [%s].", domain))
+			if err := c.Cfg.Save(); err != nil {
+				return nil, err
+			}
+			c.UI.Message("Saved profile.")
 		}
-		c.UI.Message("Saved profile.")
 	}
 
-	token = c.Cfg.GetToken(domain)
-	if token == "" {
-		c.UI.Message(fmt.Sprintf("Not found private token in the domain [%s].", domain))
-		token, err = c.UI.Ask("Please enter GitLab private token:")
+	token = c.Cfg.GetToken(name)
+	if token == "" && !c.ReadOnly {
+		c.UI.Message(fmt.Sprintf("Not found private token in the domain [%s].", name))
+		token, err = c.UI.AskSecret("Please enter GitLab private token:")
 		if err != nil {
-			return nil, fmt.Errorf("cannot read private token, %s", err)
+			return nil, fmt.Errorf("cannot read private token, %w", err)
 		}
 
-		c.Cfg.SetToken(domain, token)
+		c.Cfg.SetToken(name, token)
 		if err := c.Cfg.Save(); err != nil {
 			return nil, err
 		}
 		c.UI.Message("Saved private Token.")
 	}
 
-	profile, err := c.Cfg.GetProfile(domain)
+	profile, err := c.Cfg.GetProfile(name)
 	if err != nil {
 		return nil, err
 	}
@@ -192,9 +204,9 @@ func (c *RemoteCollecter) collectTargetByArgs(pInfo *GitLabProjectInfo, project,
 func filterHasGitlabDomain(remoteInfos []*RemoteInfo, cfg *config.Config) []*RemoteInfo {
 	gitlabRemotes := []*RemoteInfo{}
 	for _, remoteInfo := range remoteInfos {
-		if strings.HasPrefix(remoteInfo.Domain, "github") {
+		if strings.HasPrefix(remoteInfo.Domain, "github") || strings.HasPrefix(remoteInfo.Domain, "gitlab") {
 			gitlabRemotes = append(gitlabRemotes, remoteInfo)
-		} else if cfg.HasDomain(remoteInfo.Domain) {
+		} else if cfg.HasDomain(remoteInfo.Domain) || (remoteInfo.Alias != "" && cfg.HasDomain(remoteInfo.Alias)) {
 			gitlabRemotes = append(gitlabRemotes, remoteInfo)
 		}
 	}
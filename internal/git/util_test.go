@@ -0,0 +1,132 @@
+package git
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/config"
+	"github.com/lighttiger2505/huc/internal/ui"
+)
+
+func TestRemoteCollecter_ReadOnly(t *testing.T) {
+	var out, errOut bytes.Buffer
+	c := &RemoteCollecter{
+		UI: &ui.BasicUi{
+			Reader:         strings.NewReader(""),
+			Writer:         &out,
+			ErrorWriter:    &errOut,
+			NonInteractive: true,
+		},
+		Cfg: config.NewConfig(),
+		GitClient: &MockClient{
+			MockRemoteInfos: func() ([]*RemoteInfo, error) {
+				return []*RemoteInfo{{Domain: "github.com", Group: "octocat", Repository: "hello-world"}}, nil
+			},
+			MockCurrentRemoteBranch: func() (string, error) {
+				return "main", nil
+			},
+		},
+		ReadOnly: true,
+	}
+
+	// The unknown domain is not saved and the token is not asked
+	got, err := c.collectTargetByLocalRepository(&GitLabProjectInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &GitLabProjectInfo{
+		Domain:        "github.com",
+		Project:       "octocat/hello-world",
+		CurrentBranch: "main",
+		Profile:       &config.Profile{},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("\nwant %#v \ngot  %#v", want, got)
+	}
+	if out.String() != "" || errOut.String() != "" {
+		t.Errorf("want no messages, got %q, %q", out.String(), errOut.String())
+	}
+}
+
+func TestRemoteCollecter_OutsideRepository(t *testing.T) {
+	cfg := config.NewConfig()
+	cfg.SetProfile("github.com", config.Profile{Token: "token", DefaultProject: "octocat/hello-world"})
+	cfg.DefalutProfile = "github.com"
+	c := &RemoteCollecter{
+		UI:  &ui.ScriptedUi{},
+		Cfg: cfg,
+		// The remotes are not read outside of repository
+		GitClient: &MockClient{
+			MockRepositoryContext: func() (*RepositoryContext, error) {
+				return nil, ErrNotGitRepository
+			},
+		},
+	}
+
+	got, err := c.CollectTarget("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := &GitLabProjectInfo{
+		Domain:  "github.com",
+		Project: "octocat/hello-world",
+		Token:   "token",
+		Profile: cfg.GetDefaultProfile(),
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("\nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestRemoteCollecter_SSHAlias(t *testing.T) {
+	sshConfig, err := ParseSSHConfig(strings.NewReader(`
+Host github-work github-personal
+    HostName github.com
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parser := &URLParser{SSHConfig: sshConfig}
+
+	cfg := config.NewConfig()
+	cfg.SetProfile("github.com", config.Profile{Token: "default-token"})
+	cfg.SetProfile("github-work", config.Profile{Token: "work-token"})
+	cfg.SetProfile("github-personal", config.Profile{Token: "personal-token"})
+
+	tests := []struct {
+		url       string
+		wantToken string
+	}{
+		{url: "github-work:company/app.git", wantToken: "work-token"},
+		{url: "github-personal:octocat/dotfiles.git", wantToken: "personal-token"},
+		// The profile of host is used without alias
+		{url: "git@github.com:octocat/hello-world.git", wantToken: "default-token"},
+	}
+	for _, tt := range tests {
+		remote, err := parser.RemoteInfo("origin", tt.url)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := &RemoteCollecter{
+			UI:  &ui.ScriptedUi{},
+			Cfg: cfg,
+			GitClient: &MockClient{
+				MockRemoteInfos: func() ([]*RemoteInfo, error) {
+					return []*RemoteInfo{remote}, nil
+				},
+				MockCurrentRemoteBranch: func() (string, error) {
+					return "main", nil
+				},
+			},
+		}
+		got, err := c.collectTargetByLocalRepository(&GitLabProjectInfo{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got.Domain != "github.com" || got.Token != tt.wantToken || got.Profile.Token != tt.wantToken {
+			t.Errorf("%s: \nwant %#v, %#v \ngot  %#v, %#v", tt.url, "github.com", tt.wantToken, got.Domain, got.Token)
+		}
+	}
+}
@@ -0,0 +1,208 @@
+package gitea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+var UserAgent = "huc"
+
+// tokenScopes are the scopes of access token that huc requires.
+var tokenScopes = []string{"write:issue", "read:repository"}
+
+type client struct {
+	ctx        context.Context
+	httpClient *http.Client
+	apiURL     string
+	token      string
+}
+
+func newClient(ctx context.Context, httpClient *http.Client, apiURL, token string) *client {
+	return &client{
+		ctx:        ctx,
+		httpClient: provider.HTTPClient(httpClient),
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		token:      token,
+	}
+}
+
+// errorResponse is the error of Gitea API.
+type errorResponse struct {
+	StatusCode int
+	Message    string `json:"message"`
+}
+
+func (e *errorResponse) Error() string {
+	if e.Message != "" {
+		return fmt.Sprintf("Gitea API error, %d %s", e.StatusCode, e.Message)
+	}
+	return fmt.Sprintf("Gitea API error, %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// get requests to path relative from API root and unmarshal response to dest.
+func (c *client) get(path string, params url.Values, dest interface{}) error {
+	return c.do("GET", path, params, nil, dest)
+}
+
+// do requests with the json body, response is ignored when dest is nil.
+func (c *client) do(method, path string, params url.Values, payload interface{}, dest interface{}) error {
+	u := c.apiURL + "/" + strings.TrimPrefix(path, "/")
+	if len(params) > 0 {
+		u = u + "?" + params.Encode()
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "token "+c.token)
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		errRes := &errorResponse{StatusCode: res.StatusCode}
+		json.Unmarshal(body, errRes)
+		return provider.StatusError(res.StatusCode, tokenScopes, errRes)
+	}
+
+	if dest == nil {
+		return nil
+	}
+	return json.Unmarshal(body, dest)
+}
+
+// repoPath returns the path of repository API, e.g. "repos/owner/repo/issues"
+func repoPath(owner, repo string, subpaths ...string) string {
+	paths := append([]string{"repos", url.PathEscape(owner), url.PathEscape(repo)}, subpaths...)
+	return strings.Join(paths, "/")
+}
+
+type user struct {
+	ID    int    `json:"id"`
+	Login string `json:"login"`
+}
+
+type label struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Color is the hex code with or without "#" by the version of Gitea
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// https://try.gitea.io/api/swagger#/issue
+type issue struct {
+	ID          int               `json:"id"`
+	Number      int               `json:"number"`
+	Title       string            `json:"title"`
+	Body        string            `json:"body"`
+	State       string            `json:"state"`
+	User        user              `json:"user"`
+	Labels      []label           `json:"labels"`
+	Assignees   []user            `json:"assignees"`
+	CreatedAt   time.Time         `json:"created_at"`
+	PullRequest *issuePullRequest `json:"pull_request"`
+}
+
+// issuePullRequest is not nil when the issue is a pull request
+type issuePullRequest struct {
+	Merged bool `json:"merged"`
+}
+
+// https://try.gitea.io/api/swagger#/repository/repoListPullRequests
+type pullRequest struct {
+	ID        int       `json:"id"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	State     string    `json:"state"`
+	Merged    bool      `json:"merged"`
+	User      user      `json:"user"`
+	Labels    []label   `json:"labels"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// https://try.gitea.io/api/swagger#/issue/issueGetComments
+type comment struct {
+	ID        int       `json:"id"`
+	Body      string    `json:"body"`
+	User      user      `json:"user"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// https://try.gitea.io/api/swagger#/repository/repoListReleases
+type release struct {
+	ID        int       `json:"id"`
+	TagName   string    `json:"tag_name"`
+	Name      string    `json:"name"`
+	Body      string    `json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// https://try.gitea.io/api/swagger#/repository/repoGetContents
+type content struct {
+	Name string `json:"name"`
+	Path string `json:"path"`
+	// Type is "file", "dir", "symlink" or "submodule"
+	Type string `json:"type"`
+	// Content is encoded by base64, it's empty for directory
+	Content string `json:"content"`
+}
+
+// pageLimit is the max page size of Gitea API in the default settings.
+const pageLimit = 50
+
+// pageSize returns the page size to list num items, it's up to pageLimit.
+func pageSize(num int) int {
+	if num > pageLimit {
+		return pageLimit
+	}
+	return num
+}
+
+// pageParams returns the copy of params with the page and the limit.
+func pageParams(params url.Values, page, limit int) url.Values {
+	values := url.Values{}
+	for k, v := range params {
+		values[k] = v
+	}
+	values.Set("limit", strconv.Itoa(limit))
+	values.Set("page", strconv.Itoa(page))
+	return values
+}
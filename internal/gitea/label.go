@@ -0,0 +1,111 @@
+package gitea
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+func (p *Provider) listLabels() ([]label, error) {
+	labels := []label{}
+	for page := 1; ; page++ {
+		var results []label
+		if err := p.client.get(repoPath(p.repositoryOwner, p.repositoryName, "labels"), pageParams(nil, page, pageLimit), &results); err != nil {
+			return nil, err
+		}
+		labels = append(labels, results...)
+		if len(results) < pageLimit {
+			return labels, nil
+		}
+	}
+}
+
+func (p *Provider) ListLabels() ([]provider.Label, error) {
+	labels, err := p.listLabels()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]provider.Label, len(labels))
+	for i, l := range labels {
+		results[i] = provider.Label{
+			Name:        l.Name,
+			Color:       strings.ToLower(strings.TrimPrefix(l.Color, "#")),
+			Description: l.Description,
+		}
+	}
+	return results, nil
+}
+
+func (p *Provider) CreateLabel(l *provider.Label) error {
+	payload := map[string]interface{}{
+		"name":        l.Name,
+		"color":       "#" + l.Color,
+		"description": l.Description,
+	}
+	return p.client.do("POST", repoPath(p.repositoryOwner, p.repositoryName, "labels"), nil, payload, nil)
+}
+
+func (p *Provider) UpdateLabel(name string, l *provider.Label) error {
+	id, err := p.labelID(name)
+	if err != nil {
+		return err
+	}
+	payload := map[string]interface{}{
+		"name":        l.Name,
+		"color":       "#" + l.Color,
+		"description": l.Description,
+	}
+	return p.client.do("PATCH", repoPath(p.repositoryOwner, p.repositoryName, "labels", strconv.Itoa(id)), nil, payload, nil)
+}
+
+func (p *Provider) DeleteLabel(name string) error {
+	id, err := p.labelID(name)
+	if err != nil {
+		return err
+	}
+	return p.client.do("DELETE", repoPath(p.repositoryOwner, p.repositoryName, "labels", strconv.Itoa(id)), nil, nil, nil)
+}
+
+// labelID returns the id of the label, Gitea API changes the label by the id.
+func (p *Provider) labelID(name string) (int, error) {
+	labels, err := p.listLabels()
+	if err != nil {
+		return 0, err
+	}
+	for _, l := range labels {
+		if l.Name == name {
+			return l.ID, nil
+		}
+	}
+	return 0, &provider.NotFoundError{Err: fmt.Errorf("Not found label, %s", name)}
+}
+
+// ListRepositories returns the full names of the repositories of the
+// organization, the archived repositories are excluded because they are read-only.
+func ListRepositories(ctx context.Context, pInfo *git.GitLabProjectInfo, httpClient *http.Client, org string) ([]string, error) {
+	c := newClient(ctx, httpClient, pInfo.BaseUrl()+"/api/v1", pInfo.Token)
+	names := []string{}
+	for page := 1; ; page++ {
+		var repos []struct {
+			FullName string `json:"full_name"`
+			Archived bool   `json:"archived"`
+		}
+		if err := c.get("orgs/"+url.PathEscape(org)+"/repos", pageParams(nil, page, pageLimit), &repos); err != nil {
+			return nil, err
+		}
+		for _, r := range repos {
+			if !r.Archived {
+				names = append(names, r.FullName)
+			}
+		}
+		if len(repos) < pageLimit {
+			return names, nil
+		}
+	}
+}
@@ -0,0 +1,481 @@
+package gitea
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+// Provider is the Gitea and Forgejo implementation of provider.Provider.
+type Provider struct {
+	pInfo           *git.GitLabProjectInfo
+	client          *client
+	repositoryOwner string
+	repositoryName  string
+}
+
+// NewProvider returns the provider of the repository, the API calls are sent
+// by httpClient and canceled by ctx.
+func NewProvider(ctx context.Context, pInfo *git.GitLabProjectInfo, httpClient *http.Client) (*Provider, error) {
+	return newProvider(ctx, pInfo, httpClient, pInfo.BaseUrl()+"/api/v1")
+}
+
+func newProvider(ctx context.Context, pInfo *git.GitLabProjectInfo, httpClient *http.Client, apiURL string) (*Provider, error) {
+	spProject := strings.Split(pInfo.Project, "/")
+	if len(spProject) != 2 {
+		return nil, fmt.Errorf("Invalid Gitea repository, %s", pInfo.Project)
+	}
+	return &Provider{
+		pInfo:           pInfo,
+		client:          newClient(ctx, httpClient, apiURL, pInfo.Token),
+		repositoryOwner: spProject[0],
+		repositoryName:  spProject[1],
+	}, nil
+}
+
+func (p *Provider) ShowIssue(number int) (*provider.Issue, error) {
+	var i issue
+	if err := p.client.get(repoPath(p.repositoryOwner, p.repositoryName, "issues", strconv.Itoa(number)), nil, &i); err != nil {
+		return nil, err
+	}
+	return i.toProvider(), nil
+}
+
+func (p *Provider) ListIssue(opt *provider.ListIssueOption) ([]provider.Issue, error) {
+	// Gitea issue API is always sorted by newest
+	if opt.Sort != provider.SortCreatedAt {
+		return nil, &provider.NotSupportedOptionError{Provider: "Gitea", Option: "issue sort", Value: opt.Sort}
+	}
+	if opt.Direction != provider.DirectionDesc {
+		return nil, &provider.NotSupportedOptionError{Provider: "Gitea", Option: "issue order", Value: opt.Direction}
+	}
+
+	params := url.Values{}
+	params.Set("type", "issues")
+	switch opt.States {
+	case provider.StateOpen:
+		params.Set("state", "open")
+	case provider.StateClosed:
+		params.Set("state", "closed")
+	default:
+		return nil, &provider.NotSupportedOptionError{Provider: "Gitea", Option: "issue state", Value: opt.States}
+	}
+
+	// The pull requests are skipped, the pages are read until num issues are found
+	path := repoPath(p.repositoryOwner, p.repositoryName, "issues")
+	size := pageSize(opt.Num)
+	results := []provider.Issue{}
+	for page := 1; len(results) < opt.Num; page++ {
+		var issues []issue
+		if err := p.client.get(path, pageParams(params, page, size), &issues); err != nil {
+			return nil, err
+		}
+		for i := range issues {
+			if len(results) == opt.Num {
+				break
+			}
+			// Old Gitea ignores "type" parameter
+			if issues[i].PullRequest != nil {
+				continue
+			}
+			results = append(results, *issues[i].toProvider())
+		}
+		if len(issues) < size {
+			break
+		}
+	}
+	return results, nil
+}
+
+func (p *Provider) ShowPullRequest(number int) (*provider.PullRequest, error) {
+	var pr pullRequest
+	if err := p.client.get(repoPath(p.repositoryOwner, p.repositoryName, "pulls", strconv.Itoa(number)), nil, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toProvider(), nil
+}
+
+func (p *Provider) ListPullRequest(opt *provider.ListPullRequestOption) ([]provider.PullRequest, error) {
+	params := url.Values{}
+	sort, err := toPullRequestSort(opt.Sort, opt.Direction)
+	if err != nil {
+		return nil, err
+	}
+	if sort != "" {
+		params.Set("sort", sort)
+	}
+
+	// Gitea has not "merged" state, it's a closed pull request that is merged
+	switch opt.States {
+	case provider.StateOpen:
+		params.Set("state", "open")
+	case provider.StateClosed, provider.StateMerged:
+		params.Set("state", "closed")
+	default:
+		return nil, &provider.NotSupportedOptionError{Provider: "Gitea", Option: "pull request state", Value: opt.States}
+	}
+
+	// The merged pull requests are filtered from the closed ones, the pages
+	// are read until num pull requests are found
+	path := repoPath(p.repositoryOwner, p.repositoryName, "pulls")
+	size := pageSize(opt.Num)
+	results := []provider.PullRequest{}
+	for page := 1; len(results) < opt.Num; page++ {
+		var prs []pullRequest
+		if err := p.client.get(path, pageParams(params, page, size), &prs); err != nil {
+			return nil, err
+		}
+		for i := range prs {
+			if len(results) == opt.Num {
+				break
+			}
+			if opt.States == provider.StateMerged && !prs[i].Merged {
+				continue
+			}
+			results = append(results, *prs[i].toProvider())
+		}
+		if len(prs) < size {
+			break
+		}
+	}
+	return results, nil
+}
+
+func (p *Provider) ListRelease(opt *provider.ListReleaseOption) ([]provider.Release, error) {
+	// Gitea release API is always sorted by newest
+	if opt.Sort != provider.SortCreatedAt {
+		return nil, &provider.NotSupportedOptionError{Provider: "Gitea", Option: "release sort", Value: opt.Sort}
+	}
+	if opt.Direction != provider.DirectionDesc {
+		return nil, &provider.NotSupportedOptionError{Provider: "Gitea", Option: "release order", Value: opt.Direction}
+	}
+
+	path := repoPath(p.repositoryOwner, p.repositoryName, "releases")
+	size := pageSize(opt.Num)
+	results := []provider.Release{}
+	for page := 1; len(results) < opt.Num; page++ {
+		var releases []release
+		if err := p.client.get(path, pageParams(nil, page, size), &releases); err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(releases) && len(results) < opt.Num; i++ {
+			results = append(results, *releases[i].toProvider())
+		}
+		if len(releases) < size {
+			break
+		}
+	}
+	return results, nil
+}
+
+func (p *Provider) ShowRelease(tagName string) (*provider.Release, error) {
+	var r release
+	if err := p.client.get(repoPath(p.repositoryOwner, p.repositoryName, "releases", "tags", url.PathEscape(tagName)), nil, &r); err != nil {
+		return nil, err
+	}
+	return r.toProvider(), nil
+}
+
+// Pull requests are also issues in Gitea, the issue API is used for comments, labels and assignees of them.
+
+func (p *Provider) ListIssueComments(number int) ([]provider.Comment, error) {
+	return p.listComments(number)
+}
+
+func (p *Provider) ListPullRequestComments(number int) ([]provider.Comment, error) {
+	return p.listComments(number)
+}
+
+func (p *Provider) CommentIssue(number int, body string) error {
+	return p.addComment(number, body)
+}
+
+func (p *Provider) CommentPullRequest(number int, body string) error {
+	return p.addComment(number, body)
+}
+
+func (p *Provider) EditIssueComment(number int, commentID string, body string) error {
+	return p.editComment(commentID, body)
+}
+
+func (p *Provider) EditPullRequestComment(number int, commentID string, body string) error {
+	return p.editComment(commentID, body)
+}
+
+func (p *Provider) CurrentUser() (string, error) {
+	var u user
+	if err := p.client.get("user", nil, &u); err != nil {
+		return "", err
+	}
+	return u.Login, nil
+}
+
+func (p *Provider) CloseIssue(number int) error {
+	path := repoPath(p.repositoryOwner, p.repositoryName, "issues", strconv.Itoa(number))
+	return p.client.do("PATCH", path, nil, map[string]interface{}{"state": "closed"}, nil)
+}
+
+func (p *Provider) ClosePullRequest(number int) error {
+	path := repoPath(p.repositoryOwner, p.repositoryName, "pulls", strconv.Itoa(number))
+	return p.client.do("PATCH", path, nil, map[string]interface{}{"state": "closed"}, nil)
+}
+
+func (p *Provider) CreateIssue(opt *provider.CreateIssueOption) (*provider.Issue, error) {
+	payload := map[string]interface{}{
+		"title": opt.Title,
+		"body":  opt.Body,
+	}
+	var i issue
+	if err := p.client.do("POST", repoPath(p.repositoryOwner, p.repositoryName, "issues"), nil, payload, &i); err != nil {
+		return nil, err
+	}
+	return i.toProvider(), nil
+}
+
+func (p *Provider) CreatePullRequest(opt *provider.CreatePullRequestOption) (*provider.PullRequest, error) {
+	payload := map[string]interface{}{
+		"head":  opt.Head,
+		"base":  opt.Base,
+		"title": opt.Title,
+		"body":  opt.Body,
+	}
+	var pr pullRequest
+	if err := p.client.do("POST", repoPath(p.repositoryOwner, p.repositoryName, "pulls"), nil, payload, &pr); err != nil {
+		return nil, err
+	}
+	return pr.toProvider(), nil
+}
+
+func (p *Provider) LabelIssue(number int, labels []string) error {
+	return p.addLabels(number, labels)
+}
+
+func (p *Provider) LabelPullRequest(number int, labels []string) error {
+	return p.addLabels(number, labels)
+}
+
+func (p *Provider) AssignIssue(number int, users []string) error {
+	return p.assign(number, users)
+}
+
+func (p *Provider) AssignPullRequest(number int, users []string) error {
+	return p.assign(number, users)
+}
+
+func (p *Provider) PullRequestRef(number int) string {
+	return fmt.Sprintf("pull/%d/head", number)
+}
+
+func (p *Provider) listComments(number int) ([]provider.Comment, error) {
+	var comments []comment
+	if err := p.client.get(repoPath(p.repositoryOwner, p.repositoryName, "issues", strconv.Itoa(number), "comments"), nil, &comments); err != nil {
+		return nil, err
+	}
+
+	results := make([]provider.Comment, len(comments))
+	for i, c := range comments {
+		results[i] = provider.Comment{
+			ID:          strconv.Itoa(c.ID),
+			Author:      c.User.Login,
+			PublishedAt: c.CreatedAt,
+			Body:        c.Body,
+		}
+	}
+	return results, nil
+}
+
+func (p *Provider) addComment(number int, body string) error {
+	path := repoPath(p.repositoryOwner, p.repositoryName, "issues", strconv.Itoa(number), "comments")
+	return p.client.do("POST", path, nil, map[string]interface{}{"body": body}, nil)
+}
+
+// editComment replaces the body, the comment is identified without the number of issue.
+func (p *Provider) editComment(commentID string, body string) error {
+	path := repoPath(p.repositoryOwner, p.repositoryName, "issues", "comments", commentID)
+	return p.client.do("PATCH", path, nil, map[string]interface{}{"body": body}, nil)
+}
+
+// addLabels adds the labels by ids, the ids are looked up from the labels of repository.
+func (p *Provider) addLabels(number int, names []string) error {
+	labels, err := p.listLabels()
+	if err != nil {
+		return err
+	}
+
+	ids := []int{}
+	for _, name := range names {
+		found := false
+		for _, l := range labels {
+			if l.Name == name {
+				ids = append(ids, l.ID)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return fmt.Errorf("Not found label, %s", name)
+		}
+	}
+
+	path := repoPath(p.repositoryOwner, p.repositoryName, "issues", strconv.Itoa(number), "labels")
+	return p.client.do("POST", path, nil, map[string]interface{}{"labels": ids}, nil)
+}
+
+// assign adds users to the current assignees, Gitea API replaces all assignees.
+func (p *Provider) assign(number int, users []string) error {
+	path := repoPath(p.repositoryOwner, p.repositoryName, "issues", strconv.Itoa(number))
+	var i issue
+	if err := p.client.get(path, nil, &i); err != nil {
+		return err
+	}
+
+	assignees := []string{}
+	for _, u := range i.Assignees {
+		assignees = append(assignees, u.Login)
+	}
+	assignees = append(assignees, users...)
+	return p.client.do("PATCH", path, nil, map[string]interface{}{"assignees": assignees}, nil)
+}
+
+// ReadDir returns the files of the directory, the contents API returns the list for directory.
+func (p *Provider) ReadDir(dir string) ([]string, error) {
+	var entries []content
+	if err := p.client.get(repoPath(p.repositoryOwner, p.repositoryName, "contents", dir), nil, &entries); err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, e := range entries {
+		if e.Type == "file" {
+			names = append(names, e.Name)
+		}
+	}
+	return names, nil
+}
+
+func (p *Provider) ReadFile(path string) ([]byte, error) {
+	var c content
+	if err := p.client.get(repoPath(p.repositoryOwner, p.repositoryName, "contents", path), nil, &c); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(c.Content)
+}
+
+func (p *Provider) IssueURL(number int) string {
+	return strings.Join([]string{p.pInfo.SubpageUrl("issues"), strconv.Itoa(number)}, "/")
+}
+
+func (p *Provider) PullRequestURL(number int) string {
+	return strings.Join([]string{p.pInfo.SubpageUrl("pulls"), strconv.Itoa(number)}, "/")
+}
+
+func (p *Provider) ReleaseURL(tagName string) string {
+	return strings.Join([]string{p.pInfo.SubpageUrl("releases/tag"), url.PathEscape(tagName)}, "/")
+}
+
+func (p *Provider) RepositoryURL() string {
+	return p.pInfo.RepositoryUrl()
+}
+
+func (p *Provider) TreeURL(ref provider.Ref, path string, startLine, endLine int) string {
+	url := p.pInfo.SubpageUrl("src/" + refPath(ref))
+	if path != "" {
+		url = url + "/" + path
+	}
+	if startLine == 0 {
+		return url
+	}
+	if endLine != 0 {
+		return fmt.Sprintf("%s#L%d-L%d", url, startLine, endLine)
+	}
+	return fmt.Sprintf("%s#L%d", url, startLine)
+}
+
+func (p *Provider) CommitsURL(ref provider.Ref) string {
+	return p.pInfo.SubpageUrl("commits/" + refPath(ref))
+}
+
+func (p *Provider) SettingsURL() string {
+	return p.pInfo.SubpageUrl("settings")
+}
+
+func (p *Provider) WikiURL() string {
+	return p.pInfo.SubpageUrl("wiki")
+}
+
+func (p *Provider) ActionsURL() string {
+	return p.pInfo.SubpageUrl("actions")
+}
+
+// refPath returns the path of ref in web url, e.g. "branch/master" and "commit/<sha>"
+func refPath(ref provider.Ref) string {
+	if ref.Commit {
+		return "commit/" + ref.Name
+	}
+	return "branch/" + ref.Name
+}
+
+func toPullRequestSort(sort, direction string) (string, error) {
+	if direction != provider.DirectionAsc && direction != provider.DirectionDesc {
+		return "", &provider.NotSupportedOptionError{Provider: "Gitea", Option: "order", Value: direction}
+	}
+	asc := direction == provider.DirectionAsc
+
+	switch sort {
+	case provider.SortCreatedAt:
+		if asc {
+			return "oldest", nil
+		}
+		// Default order is newest
+		return "", nil
+	case provider.SortUpdatedAt:
+		if asc {
+			return "leastupdate", nil
+		}
+		return "recentupdate", nil
+	case provider.SortComments:
+		if asc {
+			return "leastcomment", nil
+		}
+		return "mostcomment", nil
+	}
+	return "", &provider.NotSupportedOptionError{Provider: "Gitea", Option: "sort", Value: sort}
+}
+
+func (i *issue) toProvider() *provider.Issue {
+	return &provider.Issue{
+		ID:          strconv.Itoa(i.ID),
+		Number:      i.Number,
+		Author:      i.User.Login,
+		PublishedAt: i.CreatedAt,
+		Title:       i.Title,
+		Body:        i.Body,
+	}
+}
+
+func (pr *pullRequest) toProvider() *provider.PullRequest {
+	return &provider.PullRequest{
+		ID:          strconv.Itoa(pr.ID),
+		Number:      pr.Number,
+		Author:      pr.User.Login,
+		PublishedAt: pr.CreatedAt,
+		Title:       pr.Title,
+		Body:        pr.Body,
+	}
+}
+
+func (r *release) toProvider() *provider.Release {
+	return &provider.Release{
+		ID:          strconv.Itoa(r.ID),
+		Name:        r.Name,
+		TagName:     r.TagName,
+		Description: r.Body,
+	}
+}
@@ -0,0 +1,385 @@
+package gitea
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+// newTestServer returns the stand-in of Gitea API that responds the fixed json of each path.
+func newTestServer(t *testing.T, responses map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "token token" {
+			t.Errorf("bad authorization header, %q", got)
+		}
+		body, ok := responses[r.URL.RequestURI()]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"Not Found"}`)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+}
+
+func setupTestProvider(t *testing.T, server *httptest.Server) *Provider {
+	p, err := newProvider(context.Background(), &git.GitLabProjectInfo{
+		Domain:  "gitea.example.com",
+		Project: "owner/repo",
+		Token:   "token",
+	}, nil, server.URL+"/api/v1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return p
+}
+
+func TestProvider_ListIssue(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/api/v1/repos/owner/repo/issues?limit=2&page=1&state=open&type=issues": `[
+			{"id":10,"number":1,"title":"issue","body":"body","user":{"login":"user"},"created_at":"2019-07-01T00:00:00Z"},
+			{"id":11,"number":2,"title":"pull","body":"body","user":{"login":"user"},"created_at":"2019-07-01T00:00:00Z","pull_request":{"merged":false}}
+		]`,
+		"/api/v1/repos/owner/repo/issues?limit=2&page=2&state=open&type=issues": `[
+			{"id":12,"number":3,"title":"next","body":"body","user":{"login":"user"},"created_at":"2019-07-01T00:00:00Z"}
+		]`,
+	})
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	// The pull request of the old Gitea is skipped, and the issue of the next page is read
+	got, err := p.ListIssue(&provider.ListIssueOption{
+		Num:       2,
+		Sort:      provider.SortCreatedAt,
+		Direction: provider.DirectionDesc,
+		States:    provider.StateOpen,
+	})
+	if err != nil {
+		t.Fatalf("ListIssue() error = %v", err)
+	}
+	want := []provider.Issue{{
+		ID:          "10",
+		Number:      1,
+		Author:      "user",
+		PublishedAt: time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+		Title:       "issue",
+		Body:        "body",
+	}, {
+		ID:          "12",
+		Number:      3,
+		Author:      "user",
+		PublishedAt: time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+		Title:       "next",
+		Body:        "body",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListIssue() \nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestProvider_ListPullRequest(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/api/v1/repos/owner/repo/pulls?limit=2&page=1&sort=recentupdate&state=closed": `[
+			{"id":20,"number":3,"title":"merged","merged":true,"user":{"login":"user"},"created_at":"2019-07-01T00:00:00Z"},
+			{"id":21,"number":4,"title":"closed","merged":false,"user":{"login":"user"},"created_at":"2019-07-01T00:00:00Z"}
+		]`,
+		"/api/v1/repos/owner/repo/pulls?limit=2&page=2&sort=recentupdate&state=closed": `[
+			{"id":22,"number":5,"title":"closed","merged":false,"user":{"login":"user"},"created_at":"2019-07-01T00:00:00Z"},
+			{"id":23,"number":6,"title":"merged","merged":true,"user":{"login":"user"},"created_at":"2019-07-01T00:00:00Z"}
+		]`,
+	})
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	// The page 3 isn't requested because 2 merged pull requests are found
+	got, err := p.ListPullRequest(&provider.ListPullRequestOption{
+		Num:       2,
+		Sort:      provider.SortUpdatedAt,
+		Direction: provider.DirectionDesc,
+		States:    provider.StateMerged,
+	})
+	if err != nil {
+		t.Fatalf("ListPullRequest() error = %v", err)
+	}
+	if len(got) != 2 || got[0].Number != 3 || got[1].Number != 6 {
+		t.Errorf("ListPullRequest() want merged pull requests #3 and #6, got %#v", got)
+	}
+}
+
+func TestProvider_ListRelease(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/api/v1/repos/owner/repo/releases?limit=5&page=1": `[{"id":30,"tag_name":"v1.0.0","name":"First release","body":"notes"}]`,
+	})
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	got, err := p.ListRelease(&provider.ListReleaseOption{
+		Num:       5,
+		Sort:      provider.SortCreatedAt,
+		Direction: provider.DirectionDesc,
+	})
+	if err != nil {
+		t.Fatalf("ListRelease() error = %v", err)
+	}
+	want := []provider.Release{{ID: "30", Name: "First release", TagName: "v1.0.0", Description: "notes"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListRelease() \nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestProvider_ListRelease_Pages(t *testing.T) {
+	releases := make([]string, pageLimit)
+	for i := range releases {
+		releases[i] = fmt.Sprintf(`{"id":%d,"tag_name":"v0.%d.0"}`, i, i)
+	}
+	server := newTestServer(t, map[string]string{
+		"/api/v1/repos/owner/repo/releases?limit=50&page=1": "[" + strings.Join(releases, ",") + "]",
+		"/api/v1/repos/owner/repo/releases?limit=50&page=2": `[{"id":50,"tag_name":"v0.50.0"},{"id":51,"tag_name":"v0.51.0"}]`,
+	})
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	got, err := p.ListRelease(&provider.ListReleaseOption{
+		Num:       60,
+		Sort:      provider.SortCreatedAt,
+		Direction: provider.DirectionDesc,
+	})
+	if err != nil {
+		t.Fatalf("ListRelease() error = %v", err)
+	}
+	if len(got) != 52 || got[51].TagName != "v0.51.0" {
+		t.Errorf("ListRelease() want 52 releases to v0.51.0, got %d", len(got))
+	}
+}
+
+func TestProvider_ShowRelease(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/api/v1/repos/owner/repo/releases/tags/release%2Fv1.0.0": `{"id":1,"tag_name":"release/v1.0.0","name":"v1.0.0"}`,
+	})
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	got, err := p.ShowRelease("release/v1.0.0")
+	if err != nil {
+		t.Fatalf("ShowRelease() error = %v", err)
+	}
+	if got.TagName != "release/v1.0.0" || got.Name != "v1.0.0" {
+		t.Errorf("ShowRelease() unexpected release, %#v", got)
+	}
+
+	if _, err := p.ShowRelease("unknown"); !errors.As(err, new(*provider.NotFoundError)) {
+		t.Errorf("ShowRelease() want NotFoundError, got %T", err)
+	}
+}
+
+func TestProvider_ShowIssue_NotFound(t *testing.T) {
+	server := newTestServer(t, map[string]string{})
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	_, err := p.ShowIssue(100)
+	var errRes *errorResponse
+	if !errors.As(err, &errRes) || errRes.StatusCode != http.StatusNotFound {
+		t.Errorf("ShowIssue() want 404 error, got %v", err)
+	}
+	if _, ok := err.(*provider.NotFoundError); !ok {
+		t.Errorf("ShowIssue() want NotFoundError, got %T", err)
+	}
+}
+
+func TestProvider_URL(t *testing.T) {
+	p, err := NewProvider(context.Background(), &git.GitLabProjectInfo{Domain: "gitea.example.com", Project: "owner/repo"}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{got: p.IssueURL(1), want: "https://gitea.example.com/owner/repo/issues/1"},
+		{got: p.PullRequestURL(2), want: "https://gitea.example.com/owner/repo/pulls/2"},
+		{got: p.ReleaseURL("v1.0.0"), want: "https://gitea.example.com/owner/repo/releases/tag/v1.0.0"},
+		{got: p.TreeURL(provider.Ref{Name: "master"}, "", 0, 0), want: "https://gitea.example.com/owner/repo/src/branch/master"},
+		{got: p.TreeURL(provider.Ref{Name: "abc123", Commit: true}, "cmd/root.go", 10, 20), want: "https://gitea.example.com/owner/repo/src/commit/abc123/cmd/root.go#L10-L20"},
+		{got: p.CommitsURL(provider.Ref{Name: "master"}), want: "https://gitea.example.com/owner/repo/commits/branch/master"},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("#%d: bad url \nwant %q \ngot  %q", i, tt.want, tt.got)
+		}
+	}
+}
+
+func TestProvider_LabelIssue(t *testing.T) {
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.RequestURI() {
+		case "GET /api/v1/repos/owner/repo/labels?limit=50&page=1":
+			// The label of the second page is found
+			labels := make([]string, pageLimit)
+			for i := range labels {
+				labels[i] = fmt.Sprintf(`{"id":%d,"name":"label%d"}`, i+10, i)
+			}
+			fmt.Fprint(w, "["+strings.Join(labels, ",")+"]")
+		case "GET /api/v1/repos/owner/repo/labels?limit=50&page=2":
+			fmt.Fprint(w, `[{"id":1,"name":"bug"},{"id":2,"name":"ui"}]`)
+		case "POST /api/v1/repos/owner/repo/issues/3/labels":
+			body, _ := ioutil.ReadAll(r.Body)
+			gotBody = string(body)
+			fmt.Fprint(w, `[]`)
+		default:
+			t.Errorf("unexpected request, %s %s", r.Method, r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	if err := p.LabelIssue(3, []string{"ui"}); err != nil {
+		t.Fatalf("LabelIssue() error = %v", err)
+	}
+	if want := `{"labels":[2]}`; gotBody != want {
+		t.Errorf("bad request body \nwant %q \ngot  %q", want, gotBody)
+	}
+
+	if err := p.LabelIssue(3, []string{"unknown"}); err == nil {
+		t.Errorf("LabelIssue() want error for unknown label")
+	}
+}
+
+func TestProvider_CreateIssue(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Method+" "+r.URL.RequestURI(), "POST /api/v1/repos/owner/repo/issues"; got != want {
+			t.Errorf("bad request \nwant %q \ngot  %q", want, got)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"body":"body","title":"title"}`; got != want {
+			t.Errorf("bad request body \nwant %q \ngot  %q", want, got)
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":100,"number":3,"title":"title","body":"body","user":{"login":"user"},"created_at":"2019-07-01T00:00:00Z"}`)
+	}))
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	got, err := p.CreateIssue(&provider.CreateIssueOption{Title: "title", Body: "body"})
+	if err != nil {
+		t.Fatalf("CreateIssue() error = %v", err)
+	}
+	want := &provider.Issue{
+		ID:          "100",
+		Number:      3,
+		Author:      "user",
+		PublishedAt: time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+		Title:       "title",
+		Body:        "body",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CreateIssue() \nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestProvider_ReadFile(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/api/v1/repos/owner/repo/contents/.github/ISSUE_TEMPLATE":         `[{"name":"bug.yml","type":"file"},{"name":"old","type":"dir"}]`,
+		"/api/v1/repos/owner/repo/contents/.github/ISSUE_TEMPLATE/bug.yml": `{"name":"bug.yml","type":"file","content":"bmFtZTogQnVnCg=="}`,
+	})
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	names, err := p.ReadDir(".github/ISSUE_TEMPLATE")
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if want := []string{"bug.yml"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ReadDir() \nwant %#v \ngot  %#v", want, names)
+	}
+
+	b, err := p.ReadFile(".github/ISSUE_TEMPLATE/bug.yml")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if got, want := string(b), "name: Bug\n"; got != want {
+		t.Errorf("ReadFile() \nwant %q \ngot  %q", want, got)
+	}
+
+	_, err = p.ReadDir(".github/PULL_REQUEST_TEMPLATE")
+	var notFoundErr *provider.NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("ReadDir() want NotFoundError, got %v", err)
+	}
+}
+
+func TestProvider_EditIssueComment(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Method+" "+r.URL.RequestURI(), "PATCH /api/v1/repos/owner/repo/issues/comments/2001"; got != want {
+			t.Errorf("bad request \nwant %q \ngot  %q", want, got)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"body":"edited"}`; got != want {
+			t.Errorf("bad request body \nwant %q \ngot  %q", want, got)
+		}
+		fmt.Fprint(w, `{"id":2001,"body":"edited"}`)
+	}))
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	if err := p.EditIssueComment(5, "2001", "edited"); err != nil {
+		t.Errorf("EditIssueComment() error = %v", err)
+	}
+}
+
+func TestProvider_CurrentUser(t *testing.T) {
+	server := newTestServer(t, map[string]string{
+		"/api/v1/user": `{"id":1,"login":"owner"}`,
+	})
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	got, err := p.CurrentUser()
+	if err != nil {
+		t.Fatalf("CurrentUser() error = %v", err)
+	}
+	if want := "owner"; got != want {
+		t.Errorf("CurrentUser() \nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestProvider_UpdateLabel(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method + " " + r.URL.RequestURI() {
+		case "GET /api/v1/repos/owner/repo/labels?limit=50&page=1":
+			fmt.Fprint(w, `[{"id":1,"name":"bug","color":"ee0701"},{"id":2,"name":"defect","color":"e11d21"}]`)
+		case "PATCH /api/v1/repos/owner/repo/labels/2":
+			body, _ := ioutil.ReadAll(r.Body)
+			if got, want := string(body), `{"color":"#d73a4a","description":"","name":"bug-report"}`; got != want {
+				t.Errorf("bad request body \nwant %q \ngot  %q", want, got)
+			}
+			fmt.Fprint(w, `{"id":2,"name":"bug-report","color":"d73a4a"}`)
+		default:
+			t.Errorf("bad request, %s %s", r.Method, r.URL.RequestURI())
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	p := setupTestProvider(t, server)
+
+	if err := p.UpdateLabel("defect", &provider.Label{Name: "bug-report", Color: "d73a4a"}); err != nil {
+		t.Errorf("UpdateLabel() error = %v", err)
+	}
+	err := p.DeleteLabel("wontfix")
+	var notFoundErr *provider.NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("DeleteLabel() of unknown label \nwant %T \ngot  %#v", notFoundErr, err)
+	}
+}
@@ -0,0 +1,56 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/shurcooL/githubv4"
+	"golang.org/x/oauth2"
+)
+
+// GitHubHost is the host of github.com, the others are GitHub Enterprise.
+const GitHubHost string = "github.com"
+
+const apiPayloadVersion = "application/vnd.github.v3+json;charset=utf-8"
+
+var UserAgent = "huc"
+
+// Client is the client of GitHub API. It's created once from the profile and
+// shared by the API calls, the calls are canceled by the context.
+type Client struct {
+	v4 *githubv4.Client
+	// httpClient and restURL are used for REST API v3, e.g. the labels that GraphQL API can't change
+	httpClient *http.Client
+	restURL    string
+}
+
+// NewClient returns the client of host authorized by token, host is
+// "github.com" or the domain of GitHub Enterprise. The requests are sent by
+// httpClient, http.DefaultClient is used when it's nil.
+func NewClient(host, token string, httpClient *http.Client) *Client {
+	src := oauth2.StaticTokenSource(
+		&oauth2.Token{AccessToken: token},
+	)
+	base := provider.HTTPClient(httpClient)
+	authClient := oauth2.NewClient(context.WithValue(context.Background(), oauth2.HTTPClient, base), src)
+	// oauth2 uses only the transport of base
+	authClient.Timeout = base.Timeout
+	authClient.Transport = &errorTransport{base: authClient.Transport}
+
+	var v4 *githubv4.Client
+	var restURL string
+	if host == "" || strings.EqualFold(host, GitHubHost) {
+		v4 = githubv4.NewClient(authClient)
+		restURL = "https://api.github.com"
+	} else {
+		v4 = githubv4.NewEnterpriseClient("https://"+host+"/api/graphql", authClient)
+		restURL = "https://" + host + "/api/v3"
+	}
+	return &Client{
+		v4:         v4,
+		httpClient: authClient,
+		restURL:    restURL,
+	}
+}
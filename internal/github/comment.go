@@ -0,0 +1,225 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// Comment model struct
+// https://developer.github.com/v4/object/issuecomment/
+type Comment struct {
+	ID          githubv4.ID
+	Author      GithubV4Actor
+	PublishedAt githubv4.DateTime
+	Body        githubv4.String
+}
+
+// commentConnection is the page of comments, the pages are read backwards
+// from the latest by "before" cursor.
+type commentConnection struct {
+	Nodes    []Comment
+	PageInfo struct {
+		HasPreviousPage githubv4.Boolean
+		StartCursor     githubv4.String
+	}
+}
+
+// listComments reads all pages of comments in the order of creation, query
+// reads the page before the "commentsCursor" of variables.
+func (c *Client) listComments(variables map[string]interface{}, query func(variables map[string]interface{}) (*commentConnection, error)) ([]Comment, error) {
+	variables["commentsCursor"] = (*githubv4.String)(nil)
+	comments := []Comment{}
+	for {
+		page, err := query(variables)
+		if err != nil {
+			return nil, err
+		}
+		comments = append(page.Nodes, comments...)
+		if !page.PageInfo.HasPreviousPage {
+			return comments, nil
+		}
+		variables["commentsCursor"] = githubv4.NewString(page.PageInfo.StartCursor)
+	}
+}
+
+// ListIssueComments returns all comments of the issue in the order of creation.
+func (c *Client) ListIssueComments(ctx context.Context, repositoryOwner, repositoryName string, number int) ([]Comment, error) {
+	variables := map[string]interface{}{
+		"repositoryOwner": githubv4.String(repositoryOwner),
+		"repositoryName":  githubv4.String(repositoryName),
+		"number":          githubv4.Int(number),
+	}
+	return c.listComments(variables, func(variables map[string]interface{}) (*commentConnection, error) {
+		var q struct {
+			Repository struct {
+				Issue struct {
+					Comments commentConnection `graphql:"comments(last:100, before:$commentsCursor)"`
+				} `graphql:"issue(number:$number)"`
+			} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+		}
+		if err := c.v4.Query(ctx, &q, variables); err != nil {
+			return nil, err
+		}
+		return &q.Repository.Issue.Comments, nil
+	})
+}
+
+// ListPullRequestComments returns all comments of the pull request in the order of creation.
+func (c *Client) ListPullRequestComments(ctx context.Context, repositoryOwner, repositoryName string, number int) ([]Comment, error) {
+	variables := map[string]interface{}{
+		"repositoryOwner": githubv4.String(repositoryOwner),
+		"repositoryName":  githubv4.String(repositoryName),
+		"number":          githubv4.Int(number),
+	}
+	return c.listComments(variables, func(variables map[string]interface{}) (*commentConnection, error) {
+		var q struct {
+			Repository struct {
+				PullRequest struct {
+					Comments commentConnection `graphql:"comments(last:100, before:$commentsCursor)"`
+				} `graphql:"pullRequest(number:$number)"`
+			} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+		}
+		if err := c.v4.Query(ctx, &q, variables); err != nil {
+			return nil, err
+		}
+		return &q.Repository.PullRequest.Comments, nil
+	})
+}
+
+// AddComment adds the comment to the issue or the pull request of the node ID.
+func (c *Client) AddComment(ctx context.Context, subjectID githubv4.ID, body string) error {
+	var m struct {
+		AddComment struct {
+			ClientMutationID githubv4.String
+		} `graphql:"addComment(input:$input)"`
+	}
+	input := githubv4.AddCommentInput{
+		SubjectID: subjectID,
+		Body:      githubv4.String(body),
+	}
+	return c.v4.Mutate(ctx, &m, input, nil)
+}
+
+// UpdateIssueComment replaces the body of the comment of the node ID, the
+// comments of pull request are also IssueComment.
+func (c *Client) UpdateIssueComment(ctx context.Context, commentID githubv4.ID, body string) error {
+	var m struct {
+		UpdateIssueComment struct {
+			ClientMutationID githubv4.String
+		} `graphql:"updateIssueComment(input:$input)"`
+	}
+	input := githubv4.UpdateIssueCommentInput{
+		ID:   commentID,
+		Body: githubv4.String(body),
+	}
+	return c.v4.Mutate(ctx, &m, input, nil)
+}
+
+// Viewer returns the login of the user of the token.
+func (c *Client) Viewer(ctx context.Context) (string, error) {
+	var q struct {
+		Viewer struct {
+			Login githubv4.String
+		}
+	}
+	if err := c.v4.Query(ctx, &q, nil); err != nil {
+		return "", err
+	}
+	return string(q.Viewer.Login), nil
+}
+
+func (c *Client) CloseIssue(ctx context.Context, issueID githubv4.ID) error {
+	var m struct {
+		CloseIssue struct {
+			ClientMutationID githubv4.String
+		} `graphql:"closeIssue(input:$input)"`
+	}
+	input := githubv4.CloseIssueInput{
+		IssueID: issueID,
+	}
+	return c.v4.Mutate(ctx, &m, input, nil)
+}
+
+func (c *Client) ClosePullRequest(ctx context.Context, pullRequestID githubv4.ID) error {
+	var m struct {
+		ClosePullRequest struct {
+			ClientMutationID githubv4.String
+		} `graphql:"closePullRequest(input:$input)"`
+	}
+	input := githubv4.ClosePullRequestInput{
+		PullRequestID: pullRequestID,
+	}
+	return c.v4.Mutate(ctx, &m, input, nil)
+}
+
+// AddLabels adds the labels of the repository to the issue or the pull request of the node ID.
+func (c *Client) AddLabels(ctx context.Context, repositoryOwner, repositoryName string, labelableID githubv4.ID, labels []string) error {
+	labelIDs := make([]githubv4.ID, len(labels))
+	for i, name := range labels {
+		var q struct {
+			Repository struct {
+				Label *struct {
+					ID githubv4.ID
+				} `graphql:"label(name:$name)"`
+			} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+		}
+		variables := map[string]interface{}{
+			"repositoryOwner": githubv4.String(repositoryOwner),
+			"repositoryName":  githubv4.String(repositoryName),
+			"name":            githubv4.String(name),
+		}
+		if err := c.v4.Query(ctx, &q, variables); err != nil {
+			return err
+		}
+		if q.Repository.Label == nil {
+			return fmt.Errorf("Not found label, %s", name)
+		}
+		labelIDs[i] = q.Repository.Label.ID
+	}
+
+	var m struct {
+		AddLabelsToLabelable struct {
+			ClientMutationID githubv4.String
+		} `graphql:"addLabelsToLabelable(input:$input)"`
+	}
+	input := githubv4.AddLabelsToLabelableInput{
+		LabelableID: labelableID,
+		LabelIDs:    labelIDs,
+	}
+	return c.v4.Mutate(ctx, &m, input, nil)
+}
+
+// AddAssignees adds the users to the assignees of the issue or the pull request of the node ID.
+func (c *Client) AddAssignees(ctx context.Context, assignableID githubv4.ID, logins []string) error {
+	userIDs := make([]githubv4.ID, len(logins))
+	for i, login := range logins {
+		var q struct {
+			User *struct {
+				ID githubv4.ID
+			} `graphql:"user(login:$login)"`
+		}
+		variables := map[string]interface{}{
+			"login": githubv4.String(login),
+		}
+		if err := c.v4.Query(ctx, &q, variables); err != nil {
+			return err
+		}
+		if q.User == nil {
+			return fmt.Errorf("Not found user, %s", login)
+		}
+		userIDs[i] = q.User.ID
+	}
+
+	var m struct {
+		AddAssigneesToAssignable struct {
+			ClientMutationID githubv4.String
+		} `graphql:"addAssigneesToAssignable(input:$input)"`
+	}
+	input := githubv4.AddAssigneesToAssignableInput{
+		AssignableID: assignableID,
+		AssigneeIDs:  userIDs,
+	}
+	return c.v4.Mutate(ctx, &m, input, nil)
+}
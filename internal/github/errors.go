@@ -0,0 +1,73 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+// tokenScopes are the scopes of personal access token that huc requires.
+var tokenScopes = []string{"repo"}
+
+// graphQLError is the error in the response of GraphQL API.
+type graphQLError struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+}
+
+// errorTransport returns the typed errors of the failed requests, githubv4
+// reports them only by the message.
+type errorTransport struct {
+	base http.RoundTripper
+}
+
+func (t *errorTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	res, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK && res.StatusCode != http.StatusUnauthorized {
+		return res, nil
+	}
+
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	if res.StatusCode == http.StatusUnauthorized {
+		var errRes struct {
+			Message string `json:"message"`
+		}
+		json.Unmarshal(body, &errRes)
+		if errRes.Message == "" {
+			errRes.Message = http.StatusText(res.StatusCode)
+		}
+		return nil, &provider.AuthError{
+			Scopes: tokenScopes,
+			Err:    fmt.Errorf("GitHub API error, %d %s", res.StatusCode, errRes.Message),
+		}
+	}
+
+	var payload struct {
+		Errors []graphQLError `json:"errors"`
+	}
+	if !bytes.Contains(body, []byte(`"errors"`)) || json.Unmarshal(body, &payload) != nil {
+		return res, nil
+	}
+	for _, e := range payload.Errors {
+		switch e.Type {
+		case "NOT_FOUND":
+			return nil, &provider.NotFoundError{Err: fmt.Errorf("GitHub API error, %s", e.Message)}
+		case "INSUFFICIENT_SCOPES", "FORBIDDEN":
+			return nil, &provider.AuthError{Scopes: tokenScopes, Err: fmt.Errorf("GitHub API error, %s", e.Message)}
+		}
+	}
+	return res, nil
+}
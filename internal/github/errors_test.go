@@ -0,0 +1,85 @@
+package github
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+func TestErrorTransport_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    error
+		wantRes string
+	}{
+		{
+			name:    "data",
+			status:  http.StatusOK,
+			body:    `{"data":{"viewer":{"login":"octocat"}}}`,
+			wantRes: `{"data":{"viewer":{"login":"octocat"}}}`,
+		},
+		{
+			name:   "bad credentials",
+			status: http.StatusUnauthorized,
+			body:   `{"message":"Bad credentials"}`,
+			want:   &provider.AuthError{Scopes: []string{"repo"}, Err: errors.New("GitHub API error, 401 Bad credentials")},
+		},
+		{
+			name:   "not found",
+			status: http.StatusOK,
+			body:   `{"data":{"repository":{"issue":null}},"errors":[{"type":"NOT_FOUND","message":"Could not resolve to an Issue with the number of 9."}]}`,
+			want:   &provider.NotFoundError{Err: errors.New("GitHub API error, Could not resolve to an Issue with the number of 9.")},
+		},
+		{
+			name:   "insufficient scopes",
+			status: http.StatusOK,
+			body:   `{"errors":[{"type":"INSUFFICIENT_SCOPES","message":"Your token has not been granted the required scopes."}]}`,
+			want:   &provider.AuthError{Scopes: []string{"repo"}, Err: errors.New("GitHub API error, Your token has not been granted the required scopes.")},
+		},
+		{
+			name:    "other error",
+			status:  http.StatusOK,
+			body:    `{"errors":[{"message":"Field 'foo' doesn't exist"}]}`,
+			wantRes: `{"errors":[{"message":"Field 'foo' doesn't exist"}]}`,
+		},
+		{
+			name:    "server error",
+			status:  http.StatusBadGateway,
+			body:    `bad gateway`,
+			wantRes: `bad gateway`,
+		},
+	}
+	for _, tt := range tests {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tt.status)
+			w.Write([]byte(tt.body))
+		}))
+		tr := &errorTransport{base: http.DefaultTransport}
+		req, err := http.NewRequest("POST", ts.URL+"/graphql", strings.NewReader(`{"query":"query{viewer{login}}"}`))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := tr.RoundTrip(req)
+		ts.Close()
+
+		if err != nil || tt.want != nil {
+			if !reflect.DeepEqual(err, tt.want) {
+				t.Errorf("%s: error \nwant %#v \ngot  %#v", tt.name, tt.want, err)
+			}
+			continue
+		}
+		b, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if string(b) != tt.wantRes {
+			t.Errorf("%s: body \nwant %q \ngot  %q", tt.name, tt.wantRes, b)
+		}
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/shurcooL/githubv4"
-	"golang.org/x/oauth2"
 )
 
 // Query some details about a repository, an issue in it, and its comments.
@@ -43,16 +42,9 @@ type ListProjectIssueOption struct {
 	Sort      githubv4.IssueOrderField
 	Direction githubv4.OrderDirection
 	States    githubv4.IssueState
-	Labels    []string
 }
 
-func ShowIssue(token, repositoryOwner, repositoryName string, number int) (*Issue, error) {
-	src := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	httpClient := oauth2.NewClient(context.Background(), src)
-	client := githubv4.NewClient(httpClient)
-
+func (c *Client) ShowIssue(ctx context.Context, repositoryOwner, repositoryName string, number int) (*Issue, error) {
 	var q struct {
 		Repository struct {
 			DatabaseID githubv4.Int
@@ -67,7 +59,7 @@ func ShowIssue(token, repositoryOwner, repositoryName string, number int) (*Issu
 		"issueNumber":     githubv4.Int(number),
 	}
 
-	if err := client.Query(context.Background(), &q, variables); err != nil {
+	if err := c.v4.Query(ctx, &q, variables); err != nil {
 		return nil, err
 	}
 
@@ -75,13 +67,7 @@ func ShowIssue(token, repositoryOwner, repositoryName string, number int) (*Issu
 
 }
 
-func ListIssue(token, repositoryOwner, repositoryName string, opt *ListProjectIssueOption) ([]Issue, error) {
-	src := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	httpClient := oauth2.NewClient(context.Background(), src)
-	client := githubv4.NewClient(httpClient)
-
+func (c *Client) ListIssue(ctx context.Context, repositoryOwner, repositoryName string, opt *ListProjectIssueOption) ([]Issue, error) {
 	var q struct {
 		Repository struct {
 			DatabaseID githubv4.Int
@@ -104,9 +90,28 @@ func ListIssue(token, repositoryOwner, repositoryName string, opt *ListProjectIs
 		"issueFirst":  githubv4.Int(opt.Num),
 	}
 
-	if err := client.Query(context.Background(), &q, variables); err != nil {
+	if err := c.v4.Query(ctx, &q, variables); err != nil {
 		return nil, err
 	}
 
 	return q.Repository.Issues.Nodes, nil
 }
+
+// CreateIssue creates the issue in the repository of the node ID.
+func (c *Client) CreateIssue(ctx context.Context, repositoryID githubv4.ID, title, body string) (*Issue, error) {
+	var m struct {
+		CreateIssue struct {
+			Issue Issue
+		} `graphql:"createIssue(input:$input)"`
+	}
+	b := githubv4.String(body)
+	input := githubv4.CreateIssueInput{
+		RepositoryID: repositoryID,
+		Title:        githubv4.String(title),
+		Body:         &b,
+	}
+	if err := c.v4.Mutate(ctx, &m, input, nil); err != nil {
+		return nil, err
+	}
+	return &m.CreateIssue.Issue, nil
+}
@@ -0,0 +1,124 @@
+package github
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+// perPage is the max page size of REST API v3.
+const perPage = 100
+
+// Label model struct
+// https://docs.github.com/en/rest/issues/labels
+type Label struct {
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+func (c *Client) ListLabels(ctx context.Context, repositoryOwner, repositoryName string) ([]Label, error) {
+	labels := []Label{}
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("per_page", strconv.Itoa(perPage))
+		params.Set("page", strconv.Itoa(page))
+		var results []Label
+		if err := c.rest(ctx, "GET", repoPath(repositoryOwner, repositoryName, "labels"), params, nil, &results); err != nil {
+			return nil, err
+		}
+		labels = append(labels, results...)
+		if len(results) < perPage {
+			return labels, nil
+		}
+	}
+}
+
+func (c *Client) CreateLabel(ctx context.Context, repositoryOwner, repositoryName string, label *Label) error {
+	return c.rest(ctx, "POST", repoPath(repositoryOwner, repositoryName, "labels"), nil, label, nil)
+}
+
+// UpdateLabel updates the label of the name, "new_name" renames the label.
+func (c *Client) UpdateLabel(ctx context.Context, repositoryOwner, repositoryName, name string, label *Label) error {
+	payload := map[string]interface{}{
+		"new_name":    label.Name,
+		"color":       label.Color,
+		"description": label.Description,
+	}
+	return c.rest(ctx, "PATCH", repoPath(repositoryOwner, repositoryName, "labels", url.PathEscape(name)), nil, payload, nil)
+}
+
+func (c *Client) DeleteLabel(ctx context.Context, repositoryOwner, repositoryName, name string) error {
+	return c.rest(ctx, "DELETE", repoPath(repositoryOwner, repositoryName, "labels", url.PathEscape(name)), nil, nil, nil)
+}
+
+// ListOrganizationRepositories returns the full names of the repositories of
+// the organization, the archived repositories are excluded because they are read-only.
+func (c *Client) ListOrganizationRepositories(ctx context.Context, org string) ([]string, error) {
+	names := []string{}
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("per_page", strconv.Itoa(perPage))
+		params.Set("page", strconv.Itoa(page))
+		var results []struct {
+			FullName string `json:"full_name"`
+			Archived bool   `json:"archived"`
+		}
+		if err := c.rest(ctx, "GET", "orgs/"+url.PathEscape(org)+"/repos", params, nil, &results); err != nil {
+			return nil, err
+		}
+		for _, r := range results {
+			if !r.Archived {
+				names = append(names, r.FullName)
+			}
+		}
+		if len(results) < perPage {
+			return names, nil
+		}
+	}
+}
+
+// ListRepositories returns the full names of the repositories of the organization on the host of pInfo.
+func ListRepositories(ctx context.Context, pInfo *git.GitLabProjectInfo, httpClient *http.Client, org string) ([]string, error) {
+	return NewClient(pInfo.Domain, pInfo.Token, httpClient).ListOrganizationRepositories(ctx, org)
+}
+
+func (p *Provider) ListLabels() ([]provider.Label, error) {
+	labels, err := p.client.ListLabels(p.ctx, p.repositoryOwner, p.repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]provider.Label, len(labels))
+	for i, l := range labels {
+		results[i] = provider.Label{
+			Name:        l.Name,
+			Color:       l.Color,
+			Description: l.Description,
+		}
+	}
+	return results, nil
+}
+
+func (p *Provider) CreateLabel(label *provider.Label) error {
+	return p.client.CreateLabel(p.ctx, p.repositoryOwner, p.repositoryName, toLabel(label))
+}
+
+func (p *Provider) UpdateLabel(name string, label *provider.Label) error {
+	return p.client.UpdateLabel(p.ctx, p.repositoryOwner, p.repositoryName, name, toLabel(label))
+}
+
+func (p *Provider) DeleteLabel(name string) error {
+	return p.client.DeleteLabel(p.ctx, p.repositoryOwner, p.repositoryName, name)
+}
+
+func toLabel(label *provider.Label) *Label {
+	return &Label{
+		Name:        label.Name,
+		Color:       label.Color,
+		Description: label.Description,
+	}
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/shurcooL/githubv4"
-	"golang.org/x/oauth2"
 )
 
 type PullRequest struct {
@@ -34,16 +33,9 @@ type ListProjectPullRequestOption struct {
 	Sort      githubv4.IssueOrderField
 	Direction githubv4.OrderDirection
 	States    githubv4.PullRequestState
-	Labels    []string
 }
 
-func ShowPullRequest(token, repositoryOwner, repositoryName string, number int) (*PullRequest, error) {
-	src := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	httpClient := oauth2.NewClient(context.Background(), src)
-	client := githubv4.NewClient(httpClient)
-
+func (c *Client) ShowPullRequest(ctx context.Context, repositoryOwner, repositoryName string, number int) (*PullRequest, error) {
 	// Target object pullRequests https://developer.github.com/v4/object/repository/
 	var q struct {
 		Repository struct {
@@ -59,20 +51,14 @@ func ShowPullRequest(token, repositoryOwner, repositoryName string, number int)
 		"pullRequestNumber": githubv4.Int(number),
 	}
 
-	if err := client.Query(context.Background(), &q, variables); err != nil {
+	if err := c.v4.Query(ctx, &q, variables); err != nil {
 		return nil, err
 	}
 
 	return &q.Repository.PullRequest, nil
 }
 
-func ListPullRequest(token, repositoryOwner, repositoryName string, opt *ListProjectPullRequestOption) ([]PullRequest, error) {
-	src := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	httpClient := oauth2.NewClient(context.Background(), src)
-	client := githubv4.NewClient(httpClient)
-
+func (c *Client) ListPullRequest(ctx context.Context, repositoryOwner, repositoryName string, opt *ListProjectPullRequestOption) ([]PullRequest, error) {
 	// Target object pullRequests https://developer.github.com/v4/object/repository/
 	var q struct {
 		Repository struct {
@@ -96,9 +82,30 @@ func ListPullRequest(token, repositoryOwner, repositoryName string, opt *ListPro
 		"pullRequestFirst": githubv4.Int(50),
 	}
 
-	if err := client.Query(context.Background(), &q, variables); err != nil {
+	if err := c.v4.Query(ctx, &q, variables); err != nil {
 		return nil, err
 	}
 
 	return q.Repository.PullRequests.Nodes, nil
 }
+
+// CreatePullRequest creates the pull request from head into base in the repository of the node ID.
+func (c *Client) CreatePullRequest(ctx context.Context, repositoryID githubv4.ID, base, head, title, body string) (*PullRequest, error) {
+	var m struct {
+		CreatePullRequest struct {
+			PullRequest PullRequest
+		} `graphql:"createPullRequest(input:$input)"`
+	}
+	b := githubv4.String(body)
+	input := githubv4.CreatePullRequestInput{
+		RepositoryID: repositoryID,
+		BaseRefName:  githubv4.String(base),
+		HeadRefName:  githubv4.String(head),
+		Title:        githubv4.String(title),
+		Body:         &b,
+	}
+	if err := c.v4.Mutate(ctx, &m, input, nil); err != nil {
+		return nil, err
+	}
+	return &m.CreatePullRequest.PullRequest, nil
+}
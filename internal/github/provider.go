@@ -0,0 +1,425 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/shurcooL/githubv4"
+)
+
+// Provider is the GitHub implementation of provider.Provider.
+type Provider struct {
+	ctx             context.Context
+	client          *Client
+	pInfo           *git.GitLabProjectInfo
+	repositoryOwner string
+	repositoryName  string
+}
+
+// NewProvider returns the provider of the repository, the API calls are sent
+// by httpClient and canceled by ctx.
+func NewProvider(ctx context.Context, pInfo *git.GitLabProjectInfo, httpClient *http.Client) (*Provider, error) {
+	spProject := strings.Split(pInfo.Project, "/")
+	if len(spProject) != 2 {
+		return nil, fmt.Errorf("Invalid GitHub repository, %s", pInfo.Project)
+	}
+	return &Provider{
+		ctx:             ctx,
+		client:          NewClient(pInfo.Domain, pInfo.Token, httpClient),
+		pInfo:           pInfo,
+		repositoryOwner: spProject[0],
+		repositoryName:  spProject[1],
+	}, nil
+}
+
+func (p *Provider) ShowIssue(number int) (*provider.Issue, error) {
+	issue, err := p.client.ShowIssue(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return nil, err
+	}
+	return issue.toProvider(), nil
+}
+
+func (p *Provider) ListIssue(opt *provider.ListIssueOption) ([]provider.Issue, error) {
+	direction, err := toOrderDirection(opt.Direction)
+	if err != nil {
+		return nil, err
+	}
+	sort, err := toIssueOrderField(opt.Sort)
+	if err != nil {
+		return nil, err
+	}
+	var states githubv4.IssueState
+	switch opt.States {
+	case provider.StateOpen:
+		states = githubv4.IssueStateOpen
+	case provider.StateClosed:
+		states = githubv4.IssueStateClosed
+	default:
+		return nil, &provider.NotSupportedOptionError{Provider: "GitHub", Option: "issue state", Value: opt.States}
+	}
+
+	issues, err := p.client.ListIssue(p.ctx, p.repositoryOwner, p.repositoryName, &ListProjectIssueOption{
+		Num:       opt.Num,
+		Sort:      sort,
+		Direction: direction,
+		States:    states,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]provider.Issue, len(issues))
+	for i := range issues {
+		results[i] = *issues[i].toProvider()
+	}
+	return results, nil
+}
+
+func (p *Provider) ShowPullRequest(number int) (*provider.PullRequest, error) {
+	pullRequest, err := p.client.ShowPullRequest(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return nil, err
+	}
+	return pullRequest.toProvider(), nil
+}
+
+func (p *Provider) ListPullRequest(opt *provider.ListPullRequestOption) ([]provider.PullRequest, error) {
+	direction, err := toOrderDirection(opt.Direction)
+	if err != nil {
+		return nil, err
+	}
+	sort, err := toIssueOrderField(opt.Sort)
+	if err != nil {
+		return nil, err
+	}
+	var states githubv4.PullRequestState
+	switch opt.States {
+	case provider.StateOpen:
+		states = githubv4.PullRequestStateOpen
+	case provider.StateMerged:
+		states = githubv4.PullRequestStateMerged
+	case provider.StateClosed:
+		states = githubv4.PullRequestStateClosed
+	default:
+		return nil, &provider.NotSupportedOptionError{Provider: "GitHub", Option: "pull request state", Value: opt.States}
+	}
+
+	pullRequests, err := p.client.ListPullRequest(p.ctx, p.repositoryOwner, p.repositoryName, &ListProjectPullRequestOption{
+		Num:       opt.Num,
+		Sort:      sort,
+		Direction: direction,
+		States:    states,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]provider.PullRequest, len(pullRequests))
+	for i := range pullRequests {
+		results[i] = *pullRequests[i].toProvider()
+	}
+	return results, nil
+}
+
+func (p *Provider) ListRelease(opt *provider.ListReleaseOption) ([]provider.Release, error) {
+	direction, err := toOrderDirection(opt.Direction)
+	if err != nil {
+		return nil, err
+	}
+	var sort githubv4.ReleaseOrderField
+	switch opt.Sort {
+	case provider.SortName:
+		sort = githubv4.ReleaseOrderFieldName
+	case provider.SortCreatedAt:
+		sort = githubv4.ReleaseOrderFieldCreatedAt
+	default:
+		return nil, &provider.NotSupportedOptionError{Provider: "GitHub", Option: "release sort", Value: opt.Sort}
+	}
+
+	releases, err := p.client.ListRelease(p.ctx, p.repositoryOwner, p.repositoryName, &ListProjectReleaseOption{
+		Num:       opt.Num,
+		Sort:      sort,
+		Direction: direction,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]provider.Release, len(releases))
+	for i := range releases {
+		results[i] = *releases[i].toProvider()
+	}
+	return results, nil
+}
+
+func (p *Provider) ShowRelease(tagName string) (*provider.Release, error) {
+	release, err := p.client.ShowRelease(p.ctx, p.repositoryOwner, p.repositoryName, tagName)
+	if err != nil {
+		return nil, err
+	}
+	return release.toProvider(), nil
+}
+
+func (p *Provider) ListIssueComments(number int) ([]provider.Comment, error) {
+	comments, err := p.client.ListIssueComments(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return nil, err
+	}
+	return toProviderComments(comments), nil
+}
+
+func (p *Provider) ListPullRequestComments(number int) ([]provider.Comment, error) {
+	comments, err := p.client.ListPullRequestComments(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return nil, err
+	}
+	return toProviderComments(comments), nil
+}
+
+func (p *Provider) CommentIssue(number int, body string) error {
+	issue, err := p.client.ShowIssue(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return err
+	}
+	return p.client.AddComment(p.ctx, issue.ID, body)
+}
+
+func (p *Provider) CommentPullRequest(number int, body string) error {
+	pullRequest, err := p.client.ShowPullRequest(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return err
+	}
+	return p.client.AddComment(p.ctx, pullRequest.ID, body)
+}
+
+func (p *Provider) EditIssueComment(number int, commentID string, body string) error {
+	return p.client.UpdateIssueComment(p.ctx, githubv4.ID(commentID), body)
+}
+
+func (p *Provider) EditPullRequestComment(number int, commentID string, body string) error {
+	return p.client.UpdateIssueComment(p.ctx, githubv4.ID(commentID), body)
+}
+
+func (p *Provider) CurrentUser() (string, error) {
+	return p.client.Viewer(p.ctx)
+}
+
+func (p *Provider) CloseIssue(number int) error {
+	issue, err := p.client.ShowIssue(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return err
+	}
+	return p.client.CloseIssue(p.ctx, issue.ID)
+}
+
+func (p *Provider) ClosePullRequest(number int) error {
+	pullRequest, err := p.client.ShowPullRequest(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return err
+	}
+	return p.client.ClosePullRequest(p.ctx, pullRequest.ID)
+}
+
+func (p *Provider) CreateIssue(opt *provider.CreateIssueOption) (*provider.Issue, error) {
+	repositoryID, err := p.client.RepositoryID(p.ctx, p.repositoryOwner, p.repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	issue, err := p.client.CreateIssue(p.ctx, repositoryID, opt.Title, opt.Body)
+	if err != nil {
+		return nil, err
+	}
+	return issue.toProvider(), nil
+}
+
+func (p *Provider) CreatePullRequest(opt *provider.CreatePullRequestOption) (*provider.PullRequest, error) {
+	repositoryID, err := p.client.RepositoryID(p.ctx, p.repositoryOwner, p.repositoryName)
+	if err != nil {
+		return nil, err
+	}
+	pullRequest, err := p.client.CreatePullRequest(p.ctx, repositoryID, opt.Base, opt.Head, opt.Title, opt.Body)
+	if err != nil {
+		return nil, err
+	}
+	return pullRequest.toProvider(), nil
+}
+
+func (p *Provider) LabelIssue(number int, labels []string) error {
+	issue, err := p.client.ShowIssue(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return err
+	}
+	return p.client.AddLabels(p.ctx, p.repositoryOwner, p.repositoryName, issue.ID, labels)
+}
+
+func (p *Provider) LabelPullRequest(number int, labels []string) error {
+	pullRequest, err := p.client.ShowPullRequest(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return err
+	}
+	return p.client.AddLabels(p.ctx, p.repositoryOwner, p.repositoryName, pullRequest.ID, labels)
+}
+
+func (p *Provider) AssignIssue(number int, users []string) error {
+	issue, err := p.client.ShowIssue(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return err
+	}
+	return p.client.AddAssignees(p.ctx, issue.ID, users)
+}
+
+func (p *Provider) AssignPullRequest(number int, users []string) error {
+	pullRequest, err := p.client.ShowPullRequest(p.ctx, p.repositoryOwner, p.repositoryName, number)
+	if err != nil {
+		return err
+	}
+	return p.client.AddAssignees(p.ctx, pullRequest.ID, users)
+}
+
+func (p *Provider) PullRequestRef(number int) string {
+	return fmt.Sprintf("pull/%d/head", number)
+}
+
+func (p *Provider) IssueURL(number int) string {
+	return strings.Join([]string{p.pInfo.SubpageUrl("issues"), strconv.Itoa(number)}, "/")
+}
+
+func (p *Provider) PullRequestURL(number int) string {
+	return strings.Join([]string{p.pInfo.SubpageUrl("pull"), strconv.Itoa(number)}, "/")
+}
+
+func (p *Provider) ReleaseURL(tagName string) string {
+	return strings.Join([]string{p.pInfo.SubpageUrl("releases/tag"), tagName}, "/")
+}
+
+func (p *Provider) RepositoryURL() string {
+	return p.pInfo.RepositoryUrl()
+}
+
+func (p *Provider) TreeURL(ref provider.Ref, path string, startLine, endLine int) string {
+	if path == "" {
+		return p.pInfo.BranchUrl(ref.Name)
+	}
+	if startLine == 0 {
+		return p.pInfo.BranchPath(ref.Name, path)
+	}
+	line := fmt.Sprintf("L%d", startLine)
+	if endLine != 0 {
+		line = fmt.Sprintf("L%d-L%d", startLine, endLine)
+	}
+	return p.pInfo.BranchFileWithLine(ref.Name, path, line)
+}
+
+func (p *Provider) CommitsURL(ref provider.Ref) string {
+	return p.pInfo.SubpageUrl("commits/" + ref.Name)
+}
+
+func (p *Provider) SettingsURL() string {
+	return p.pInfo.SubpageUrl("settings")
+}
+
+func (p *Provider) WikiURL() string {
+	return p.pInfo.SubpageUrl("wiki")
+}
+
+func (p *Provider) ActionsURL() string {
+	return p.pInfo.SubpageUrl("actions")
+}
+
+func (p *Provider) ReadDir(dir string) ([]string, error) {
+	return p.client.ReadDir(p.ctx, p.repositoryOwner, p.repositoryName, dir)
+}
+
+func (p *Provider) ReadFile(path string) ([]byte, error) {
+	return p.client.ReadFile(p.ctx, p.repositoryOwner, p.repositoryName, path)
+}
+
+func (p *Provider) RateLimit() (*provider.RateLimit, error) {
+	rateLimit, err := p.client.RateLimit(p.ctx)
+	if err != nil {
+		return nil, err
+	}
+	return rateLimit.toProvider(), nil
+}
+
+func toOrderDirection(direction string) (githubv4.OrderDirection, error) {
+	switch direction {
+	case provider.DirectionAsc:
+		return githubv4.OrderDirectionAsc, nil
+	case provider.DirectionDesc:
+		return githubv4.OrderDirectionDesc, nil
+	}
+	return "", &provider.NotSupportedOptionError{Provider: "GitHub", Option: "order", Value: direction}
+}
+
+func toIssueOrderField(sort string) (githubv4.IssueOrderField, error) {
+	switch sort {
+	case provider.SortComments:
+		return githubv4.IssueOrderFieldComments, nil
+	case provider.SortCreatedAt:
+		return githubv4.IssueOrderFieldCreatedAt, nil
+	case provider.SortUpdatedAt:
+		return githubv4.IssueOrderFieldUpdatedAt, nil
+	}
+	return "", &provider.NotSupportedOptionError{Provider: "GitHub", Option: "sort", Value: sort}
+}
+
+func (i *Issue) toProvider() *provider.Issue {
+	return &provider.Issue{
+		ID:          fmt.Sprint(i.ID),
+		Number:      int(i.Number),
+		Author:      string(i.Author.Login),
+		PublishedAt: i.PublishedAt.Time,
+		Title:       string(i.Title),
+		Body:        string(i.Body),
+	}
+}
+
+func (i *PullRequest) toProvider() *provider.PullRequest {
+	return &provider.PullRequest{
+		ID:          fmt.Sprint(i.ID),
+		Number:      int(i.Number),
+		Author:      string(i.Author.Login),
+		PublishedAt: i.PublishedAt.Time,
+		Title:       string(i.Title),
+		Body:        string(i.Body),
+	}
+}
+
+func (r *RateLimit) toProvider() *provider.RateLimit {
+	return &provider.RateLimit{
+		Limit:     int(r.Limit),
+		Cost:      int(r.Cost),
+		Remaining: int(r.Remaining),
+		Used:      int(r.Used),
+		ResetAt:   r.ResetAt.Time,
+	}
+}
+
+func (i *Release) toProvider() *provider.Release {
+	return &provider.Release{
+		ID:          fmt.Sprint(i.ID),
+		Name:        string(i.Name),
+		TagName:     string(i.TagName),
+		Description: string(i.Description),
+	}
+}
+
+func toProviderComments(comments []Comment) []provider.Comment {
+	results := make([]provider.Comment, len(comments))
+	for i, c := range comments {
+		results[i] = provider.Comment{
+			ID:          fmt.Sprint(c.ID),
+			Author:      string(c.Author.Login),
+			PublishedAt: c.PublishedAt.Time,
+			Body:        string(c.Body),
+		}
+	}
+	return results
+}
@@ -0,0 +1,27 @@
+package github
+
+import (
+	"context"
+
+	"github.com/shurcooL/githubv4"
+)
+
+// RateLimit model struct
+// https://developer.github.com/v4/object/ratelimit/
+type RateLimit struct {
+	Limit     githubv4.Int
+	Cost      githubv4.Int
+	Remaining githubv4.Int
+	Used      githubv4.Int
+	ResetAt   githubv4.DateTime
+}
+
+func (c *Client) RateLimit(ctx context.Context) (*RateLimit, error) {
+	var q struct {
+		RateLimit RateLimit
+	}
+	if err := c.v4.Query(ctx, &q, nil); err != nil {
+		return nil, err
+	}
+	return &q.RateLimit, nil
+}
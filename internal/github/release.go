@@ -4,8 +4,8 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/lighttiger2505/huc/internal/provider"
 	"github.com/shurcooL/githubv4"
-	"golang.org/x/oauth2"
 )
 
 // https://developer.github.com/v4/object/release/
@@ -30,13 +30,7 @@ type ListProjectReleaseOption struct {
 	Direction githubv4.OrderDirection
 }
 
-func ListRelease(token, repositoryOwner, repositoryName string, opt *ListProjectReleaseOption) ([]Release, error) {
-	src := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: token},
-	)
-	httpClient := oauth2.NewClient(context.Background(), src)
-	client := githubv4.NewClient(httpClient)
-
+func (c *Client) ListRelease(ctx context.Context, repositoryOwner, repositoryName string, opt *ListProjectReleaseOption) ([]Release, error) {
 	var q struct {
 		Repository struct {
 			DatabaseID githubv4.Int
@@ -57,9 +51,33 @@ func ListRelease(token, repositoryOwner, repositoryName string, opt *ListProject
 		"releaseFirst": githubv4.Int(opt.Num),
 	}
 
-	if err := client.Query(context.Background(), &q, variables); err != nil {
+	if err := c.v4.Query(ctx, &q, variables); err != nil {
 		return nil, err
 	}
 
 	return q.Repository.Releases.Nodes, nil
 }
+
+// ShowRelease returns the release of the tag.
+func (c *Client) ShowRelease(ctx context.Context, repositoryOwner, repositoryName, tagName string) (*Release, error) {
+	var q struct {
+		Repository struct {
+			Release *Release `graphql:"release(tagName:$tagName)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+
+	variables := map[string]interface{}{
+		"repositoryOwner": githubv4.String(repositoryOwner),
+		"repositoryName":  githubv4.String(repositoryName),
+		"tagName":         githubv4.String(tagName),
+	}
+
+	if err := c.v4.Query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+	// The release of unknown tag is null
+	if q.Repository.Release == nil {
+		return nil, &provider.NotFoundError{Err: fmt.Errorf("Not found release, %s", tagName)}
+	}
+	return q.Repository.Release, nil
+}
@@ -0,0 +1,87 @@
+package github
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/shurcooL/githubv4"
+)
+
+// RepositoryID returns the node ID of repository, it's required to create the issue and the pull request.
+func (c *Client) RepositoryID(ctx context.Context, repositoryOwner, repositoryName string) (githubv4.ID, error) {
+	var q struct {
+		Repository struct {
+			ID githubv4.ID
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+	variables := map[string]interface{}{
+		"repositoryOwner": githubv4.String(repositoryOwner),
+		"repositoryName":  githubv4.String(repositoryName),
+	}
+	if err := c.v4.Query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+	return q.Repository.ID, nil
+}
+
+// ReadDir returns the names of files in the directory of the default branch.
+func (c *Client) ReadDir(ctx context.Context, repositoryOwner, repositoryName, dir string) ([]string, error) {
+	var q struct {
+		Repository struct {
+			Object *struct {
+				Tree struct {
+					Entries []struct {
+						Name githubv4.String
+						Type githubv4.String
+					}
+				} `graphql:"... on Tree"`
+			} `graphql:"object(expression:$expression)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+	variables := map[string]interface{}{
+		"repositoryOwner": githubv4.String(repositoryOwner),
+		"repositoryName":  githubv4.String(repositoryName),
+		"expression":      githubv4.String("HEAD:" + dir),
+	}
+	if err := c.v4.Query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+	if q.Repository.Object == nil {
+		return nil, &provider.NotFoundError{Err: fmt.Errorf("Not found directory, %s", dir)}
+	}
+
+	names := []string{}
+	for _, e := range q.Repository.Object.Tree.Entries {
+		if e.Type == "blob" {
+			names = append(names, string(e.Name))
+		}
+	}
+	return names, nil
+}
+
+// ReadFile returns the text of file in the default branch.
+func (c *Client) ReadFile(ctx context.Context, repositoryOwner, repositoryName, path string) ([]byte, error) {
+	var q struct {
+		Repository struct {
+			Object *struct {
+				Blob struct {
+					Text *githubv4.String
+				} `graphql:"... on Blob"`
+			} `graphql:"object(expression:$expression)"`
+		} `graphql:"repository(owner:$repositoryOwner,name:$repositoryName)"`
+	}
+	variables := map[string]interface{}{
+		"repositoryOwner": githubv4.String(repositoryOwner),
+		"repositoryName":  githubv4.String(repositoryName),
+		"expression":      githubv4.String("HEAD:" + path),
+	}
+	if err := c.v4.Query(ctx, &q, variables); err != nil {
+		return nil, err
+	}
+	// The text of binary file and directory is null
+	if q.Repository.Object == nil || q.Repository.Object.Blob.Text == nil {
+		return nil, &provider.NotFoundError{Err: fmt.Errorf("Not found file, %s", path)}
+	}
+	return []byte(*q.Repository.Object.Blob.Text), nil
+}
@@ -0,0 +1,103 @@
+package github
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+// restError is the error of REST API v3, e.g. "Validation Failed" with the
+// code of field "already_exists".
+type restError struct {
+	StatusCode int
+	Message    string       `json:"message"`
+	Errors     []fieldError `json:"errors"`
+}
+
+type fieldError struct {
+	Resource string `json:"resource"`
+	Message  string `json:"message"`
+	Code     string `json:"code"`
+	Field    string `json:"field"`
+}
+
+func (e *restError) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = http.StatusText(e.StatusCode)
+	}
+	codes := []string{}
+	for _, fe := range e.Errors {
+		if fe.Field != "" && fe.Code != "" {
+			codes = append(codes, fe.Field+" "+fe.Code)
+		}
+	}
+	if len(codes) > 0 {
+		msg += " (" + strings.Join(codes, ", ") + ")"
+	}
+	return fmt.Sprintf("GitHub API error, %d %s", e.StatusCode, msg)
+}
+
+// rest requests to path of REST API v3 and unmarshal the response to dest,
+// the response is ignored when dest is nil.
+func (c *Client) rest(ctx context.Context, method, path string, params url.Values, payload interface{}, dest interface{}) error {
+	u := c.restURL + "/" + strings.TrimPrefix(path, "/")
+	if len(params) > 0 {
+		u = u + "?" + params.Encode()
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, u, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", apiPayloadVersion)
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	}
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		errRes := &restError{StatusCode: res.StatusCode}
+		json.Unmarshal(body, errRes)
+		return provider.StatusError(res.StatusCode, tokenScopes, errRes)
+	}
+
+	if dest == nil || len(body) == 0 {
+		return nil
+	}
+	return json.Unmarshal(body, dest)
+}
+
+// repoPath returns the path of repository API, e.g. "repos/owner/repo/labels"
+func repoPath(owner, repo string, subpaths ...string) string {
+	paths := append([]string{"repos", url.PathEscape(owner), url.PathEscape(repo)}, subpaths...)
+	return strings.Join(paths, "/")
+}
@@ -0,0 +1,205 @@
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mattn/go-isatty"
+)
+
+// isVerbose returns true when the API requests are traced to stderr by the
+// environment variable HUB_VERBOSE.
+func isVerbose() bool {
+	return os.Getenv("HUB_VERBOSE") != ""
+}
+
+// redactedHeaders are the headers that have the credentials.
+var redactedHeaders = map[string]bool{
+	"Authorization": true,
+	"Private-Token": true,
+	"X-Github-Otp":  true,
+	"Cookie":        true,
+	"Set-Cookie":    true,
+}
+
+var authSchemeRegexp = regexp.MustCompile(`(?i)^(basic|bearer|token) .+`)
+
+type verboseTransport struct {
+	Transport   http.RoundTripper
+	Verbose     bool
+	OverrideURL *url.URL
+	Out         io.Writer
+	Colorized   bool
+
+	now func() time.Time
+}
+
+// NewVerboseTransport returns the transport that traces the requests and
+// responses of base to stderr when verbose is true or HUB_VERBOSE is set. The
+// requests are sent to testHost instead of the original host when it's not
+// empty, the original scheme and port are sent by "X-Original-" headers.
+func NewVerboseTransport(base http.RoundTripper, testHost string, verbose bool) http.RoundTripper {
+	tr := &verboseTransport{
+		Transport: base,
+		Verbose:   verbose || isVerbose(),
+		Out:       os.Stderr,
+		Colorized: isTerminal(os.Stderr),
+	}
+	if testHost != "" {
+		tr.OverrideURL, _ = url.Parse(testHost)
+	}
+	return tr
+}
+
+func (t *verboseTransport) RoundTrip(req *http.Request) (resp *http.Response, err error) {
+	if t.OverrideURL != nil {
+		port := "80"
+		if s := strings.Split(req.URL.Host, ":"); len(s) > 1 {
+			port = s[1]
+		}
+
+		req = cloneRequest(req)
+		req.Header.Set("X-Original-Scheme", req.URL.Scheme)
+		req.Header.Set("X-Original-Port", port)
+		req.Host = req.URL.Host
+		req.URL.Scheme = t.OverrideURL.Scheme
+		req.URL.Host = t.OverrideURL.Host
+	}
+
+	if !t.Verbose {
+		return t.Transport.RoundTrip(req)
+	}
+
+	req, err = t.dumpRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	start := t.clock()
+	resp, err = t.Transport.RoundTrip(req)
+	elapsed := t.clock().Sub(start).Round(time.Millisecond)
+	if err != nil {
+		t.verbosePrintln(fmt.Sprintf("< %s (%s)", err, elapsed))
+		return nil, err
+	}
+	t.dumpResponse(resp, elapsed)
+	return resp, nil
+}
+
+func (t *verboseTransport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// dumpRequest prints the request and returns it with the body to be sent again.
+func (t *verboseTransport) dumpRequest(req *http.Request) (*http.Request, error) {
+	lines := []string{fmt.Sprintf("> %s %s", req.Method, req.URL.String())}
+	lines = append(lines, dumpHeaders(req.Header, ">")...)
+
+	if req.Body != nil && req.Body != http.NoBody {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = cloneRequest(req)
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+		lines = append(lines, dumpBody(req, body)...)
+	}
+	t.verbosePrintln(strings.Join(lines, "\n"))
+	return req, nil
+}
+
+func (t *verboseTransport) dumpResponse(resp *http.Response, elapsed time.Duration) {
+	lines := []string{fmt.Sprintf("< %s %s (%s)", resp.Proto, resp.Status, elapsed)}
+	lines = append(lines, dumpHeaders(resp.Header, "<")...)
+	t.verbosePrintln(strings.Join(lines, "\n"))
+}
+
+func (t *verboseTransport) verbosePrintln(msg string) {
+	if t.Colorized {
+		msg = fmt.Sprintf("\033[36m%s\033[0m", msg)
+	}
+	fmt.Fprintln(t.Out, msg)
+}
+
+// dumpHeaders returns the headers in order of name, the credentials are redacted.
+func dumpHeaders(header http.Header, indent string) []string {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var lines []string
+	for _, name := range names {
+		for _, v := range header[name] {
+			if redactedHeaders[http.CanonicalHeaderKey(name)] {
+				v = redact(v)
+			}
+			lines = append(lines, fmt.Sprintf("%s %s: %s", indent, name, v))
+		}
+	}
+	return lines
+}
+
+// redact hides the credential but keeps the scheme of authorization, e.g. "token [REDACTED]".
+func redact(v string) string {
+	if m := authSchemeRegexp.FindStringSubmatch(v); m != nil {
+		return m[1] + " [REDACTED]"
+	}
+	return "[REDACTED]"
+}
+
+// dumpBody returns the lines of request body, the query of GraphQL is
+// printed as it is written instead of the escaped JSON string.
+func dumpBody(req *http.Request, body []byte) []string {
+	if len(body) == 0 {
+		return nil
+	}
+	if !strings.HasSuffix(req.URL.Path, "/graphql") {
+		return []string{string(body)}
+	}
+
+	var payload struct {
+		Query     string          `json:"query"`
+		Variables json.RawMessage `json:"variables"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil || payload.Query == "" {
+		return []string{string(body)}
+	}
+	lines := []string{payload.Query}
+	if len(payload.Variables) > 0 && string(payload.Variables) != "null" {
+		lines = append(lines, "variables: "+string(payload.Variables))
+	}
+	return lines
+}
+
+func cloneRequest(req *http.Request) *http.Request {
+	dup := new(http.Request)
+	*dup = *req
+	dup.URL, _ = url.Parse(req.URL.String())
+	dup.Header = make(http.Header)
+	for k, s := range req.Header {
+		dup.Header[k] = s
+	}
+	return dup
+}
+
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd())
+}
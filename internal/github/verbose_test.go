@@ -0,0 +1,85 @@
+package github
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerboseTransport_RoundTrip(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Ratelimit-Remaining", "4999")
+		w.Write([]byte(`{"data":{}}`))
+	}))
+	defer ts.Close()
+
+	tests := []struct {
+		name   string
+		header map[string]string
+		body   string
+		want   []string
+	}{
+		{
+			name:   "graphql",
+			header: map[string]string{"Authorization": "bearer secret"},
+			body:   `{"query":"query($n:Int!){viewer{login}}","variables":{"n":1}}`,
+			want: []string{
+				"> POST " + ts.URL + "/graphql",
+				"> Authorization: bearer [REDACTED]",
+				"query($n:Int!){viewer{login}}",
+				`variables: {"n":1}`,
+				"< HTTP/1.1 200 OK (0s)",
+				"< X-Ratelimit-Remaining: 4999",
+			},
+		},
+		{
+			name:   "private token",
+			header: map[string]string{"Private-Token": "secret"},
+			body:   `{"title":"bug"}`,
+			want: []string{
+				"> Private-Token: [REDACTED]",
+				`{"title":"bug"}`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		out := &bytes.Buffer{}
+		now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+		tr := &verboseTransport{
+			Transport: http.DefaultTransport,
+			Verbose:   true,
+			Out:       out,
+			now:       func() time.Time { return now },
+		}
+
+		req, err := http.NewRequest("POST", ts.URL+"/graphql", strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		for k, v := range tt.header {
+			req.Header.Set(k, v)
+		}
+		res, err := tr.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		body, _ := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+
+		if string(body) != `{"data":{}}` {
+			t.Errorf("%s: want the response body, got %q", tt.name, body)
+		}
+		if strings.Contains(out.String(), "secret") {
+			t.Errorf("%s: want the token redacted, got %q", tt.name, out.String())
+		}
+		for _, line := range tt.want {
+			if !strings.Contains(out.String(), line+"\n") {
+				t.Errorf("%s: want line %q \ngot  %q", tt.name, line, out.String())
+			}
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package gitlab
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+var UserAgent = "huc"
+
+// tokenScopes are the scopes of personal access token that huc requires.
+var tokenScopes = []string{"api"}
+
+type client struct {
+	ctx        context.Context
+	httpClient *http.Client
+	apiURL     string
+	token      string
+}
+
+func newClient(ctx context.Context, httpClient *http.Client, apiURL, token string) *client {
+	return &client{
+		ctx:        ctx,
+		httpClient: provider.HTTPClient(httpClient),
+		apiURL:     strings.TrimSuffix(apiURL, "/"),
+		token:      token,
+	}
+}
+
+// errorResponse is the error of GitLab API, "message" is a string or a map of field and messages.
+type errorResponse struct {
+	StatusCode int
+	Message    interface{} `json:"message"`
+	ErrorText  string      `json:"error"`
+}
+
+func (e *errorResponse) Error() string {
+	switch {
+	case e.Message != nil:
+		return fmt.Sprintf("GitLab API error, %d %v", e.StatusCode, e.Message)
+	case e.ErrorText != "":
+		return fmt.Sprintf("GitLab API error, %d %s", e.StatusCode, e.ErrorText)
+	}
+	return fmt.Sprintf("GitLab API error, %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// get requests to path relative from API root and unmarshal response to dest.
+func (c *client) get(path string, params url.Values, dest interface{}) error {
+	return c.do("GET", path, params, nil, dest)
+}
+
+// list gets the page of list API, it returns the next page of "X-Next-Page"
+// header, or 0 at the last page.
+func (c *client) list(path string, params url.Values, dest interface{}) (int, error) {
+	header, err := c.request("GET", path, params, nil, dest)
+	if err != nil {
+		return 0, err
+	}
+	next, _ := strconv.Atoi(header.Get("X-Next-Page"))
+	return next, nil
+}
+
+// do requests with the json body, response is ignored when dest is nil.
+func (c *client) do(method, path string, params url.Values, payload interface{}, dest interface{}) error {
+	_, err := c.request(method, path, params, payload, dest)
+	return err
+}
+
+func (c *client) request(method, path string, params url.Values, payload interface{}, dest interface{}) (http.Header, error) {
+	u := c.apiURL + "/" + strings.TrimPrefix(path, "/")
+	if len(params) > 0 {
+		u = u + "?" + params.Encode()
+	}
+
+	var reqBody io.Reader
+	if payload != nil {
+		b, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		reqBody = bytes.NewReader(b)
+	}
+
+	req, err := http.NewRequestWithContext(c.ctx, method, u, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", UserAgent)
+	req.Header.Set("Accept", "application/json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("PRIVATE-TOKEN", c.token)
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		errRes := &errorResponse{StatusCode: res.StatusCode}
+		json.Unmarshal(body, errRes)
+		// The token without the scope is forbidden instead of unauthorized
+		if errRes.ErrorText == "insufficient_scope" {
+			return nil, &provider.AuthError{Scopes: tokenScopes, Err: errRes}
+		}
+		return nil, provider.StatusError(res.StatusCode, tokenScopes, errRes)
+	}
+
+	if dest == nil {
+		return res.Header, nil
+	}
+	return res.Header, json.Unmarshal(body, dest)
+}
+
+// projectPath returns the path of project API, e.g. "projects/group%2Fsubgroup%2Fproject"
+func projectPath(project string, subpaths ...string) string {
+	paths := append([]string{"projects", url.PathEscape(project)}, subpaths...)
+	return strings.Join(paths, "/")
+}
+
+type user struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+}
+
+// https://docs.gitlab.com/ee/api/issues.html
+type issue struct {
+	ID          int       `json:"id"`
+	IID         int       `json:"iid"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       string    `json:"state"`
+	Author      user      `json:"author"`
+	Assignees   []user    `json:"assignees"`
+	CreatedAt   time.Time `json:"created_at"`
+	WebURL      string    `json:"web_url"`
+}
+
+// https://docs.gitlab.com/ee/api/merge_requests.html
+type mergeRequest struct {
+	ID          int       `json:"id"`
+	IID         int       `json:"iid"`
+	Title       string    `json:"title"`
+	Description string    `json:"description"`
+	State       string    `json:"state"`
+	Author      user      `json:"author"`
+	Assignees   []user    `json:"assignees"`
+	CreatedAt   time.Time `json:"created_at"`
+	WebURL      string    `json:"web_url"`
+}
+
+// https://docs.gitlab.com/ee/api/notes.html
+type note struct {
+	ID        int       `json:"id"`
+	Body      string    `json:"body"`
+	Author    user      `json:"author"`
+	CreatedAt time.Time `json:"created_at"`
+	System    bool      `json:"system"`
+}
+
+// https://docs.gitlab.com/ee/api/releases/
+type release struct {
+	Name        string    `json:"name"`
+	TagName     string    `json:"tag_name"`
+	Description string    `json:"description"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// https://docs.gitlab.com/ee/api/repositories.html#list-repository-tree
+type treeEntry struct {
+	Name string `json:"name"`
+	// Type is "blob" or "tree"
+	Type string `json:"type"`
+	Path string `json:"path"`
+}
+
+// https://docs.gitlab.com/ee/api/repository_files.html
+type file struct {
+	FilePath string `json:"file_path"`
+	// Content is encoded by base64
+	Content string `json:"content"`
+}
@@ -0,0 +1,100 @@
+package gitlab
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+// perPage is the max page size of GitLab API.
+const perPage = 100
+
+// https://docs.gitlab.com/ee/api/labels.html
+type label struct {
+	ID          int    `json:"id"`
+	Name        string `json:"name"`
+	Color       string `json:"color"`
+	Description string `json:"description"`
+}
+
+// ListLabels returns the labels of project, the labels of ancestor groups are
+// excluded because they can't be changed by the project.
+func (p *Provider) ListLabels() ([]provider.Label, error) {
+	results := []provider.Label{}
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("include_ancestor_groups", "false")
+		params.Set("per_page", strconv.Itoa(perPage))
+		params.Set("page", strconv.Itoa(page))
+		var labels []label
+		if err := p.client.get(projectPath(p.pInfo.Project, "labels"), params, &labels); err != nil {
+			return nil, err
+		}
+		for _, l := range labels {
+			results = append(results, provider.Label{
+				Name:        l.Name,
+				Color:       strings.ToLower(strings.TrimPrefix(l.Color, "#")),
+				Description: l.Description,
+			})
+		}
+		if len(labels) < perPage {
+			return results, nil
+		}
+	}
+}
+
+func (p *Provider) CreateLabel(l *provider.Label) error {
+	payload := map[string]interface{}{
+		"name":        l.Name,
+		"color":       "#" + l.Color,
+		"description": l.Description,
+	}
+	return p.client.do("POST", projectPath(p.pInfo.Project, "labels"), nil, payload, nil)
+}
+
+// UpdateLabel updates the label, the label is identified by the name instead of the id.
+func (p *Provider) UpdateLabel(name string, l *provider.Label) error {
+	payload := map[string]interface{}{
+		"color":       "#" + l.Color,
+		"description": l.Description,
+	}
+	if l.Name != name {
+		payload["new_name"] = l.Name
+	}
+	return p.client.do("PUT", projectPath(p.pInfo.Project, "labels", url.PathEscape(name)), nil, payload, nil)
+}
+
+func (p *Provider) DeleteLabel(name string) error {
+	return p.client.do("DELETE", projectPath(p.pInfo.Project, "labels", url.PathEscape(name)), nil, nil, nil)
+}
+
+// ListRepositories returns the full paths of the projects of the group and the
+// subgroups, the archived projects are excluded because they are read-only.
+func ListRepositories(ctx context.Context, pInfo *git.GitLabProjectInfo, httpClient *http.Client, group string) ([]string, error) {
+	c := newClient(ctx, httpClient, pInfo.ApiUrl(), pInfo.Token)
+	names := []string{}
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("include_subgroups", "true")
+		params.Set("archived", "false")
+		params.Set("per_page", strconv.Itoa(perPage))
+		params.Set("page", strconv.Itoa(page))
+		var projects []struct {
+			PathWithNamespace string `json:"path_with_namespace"`
+		}
+		if err := c.get("groups/"+url.PathEscape(group)+"/projects", params, &projects); err != nil {
+			return nil, err
+		}
+		for _, pr := range projects {
+			names = append(names, pr.PathWithNamespace)
+		}
+		if len(projects) < perPage {
+			return names, nil
+		}
+	}
+}
@@ -0,0 +1,446 @@
+package gitlab
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+// Provider is the GitLab implementation of provider.Provider.
+type Provider struct {
+	pInfo  *git.GitLabProjectInfo
+	client *client
+}
+
+// NewProvider returns the provider of the project, the API calls are sent
+// by httpClient and canceled by ctx.
+func NewProvider(ctx context.Context, pInfo *git.GitLabProjectInfo, httpClient *http.Client) *Provider {
+	return &Provider{
+		pInfo:  pInfo,
+		client: newClient(ctx, httpClient, pInfo.ApiUrl(), pInfo.Token),
+	}
+}
+
+func (p *Provider) ShowIssue(number int) (*provider.Issue, error) {
+	var i issue
+	if err := p.client.get(projectPath(p.pInfo.Project, "issues", strconv.Itoa(number)), nil, &i); err != nil {
+		return nil, err
+	}
+	return i.toProvider(), nil
+}
+
+func (p *Provider) ListIssue(opt *provider.ListIssueOption) ([]provider.Issue, error) {
+	params, err := listParams(opt.Num, opt.Sort, opt.Direction)
+	if err != nil {
+		return nil, err
+	}
+	switch opt.States {
+	case provider.StateOpen:
+		params.Set("state", "opened")
+	case provider.StateClosed:
+		params.Set("state", "closed")
+	default:
+		return nil, &provider.NotSupportedOptionError{Provider: "GitLab", Option: "issue state", Value: opt.States}
+	}
+
+	results := []provider.Issue{}
+	for page := 1; page != 0 && len(results) < opt.Num; {
+		params.Set("page", strconv.Itoa(page))
+		var issues []issue
+		next, err := p.client.list(projectPath(p.pInfo.Project, "issues"), params, &issues)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(issues) && len(results) < opt.Num; i++ {
+			results = append(results, *issues[i].toProvider())
+		}
+		page = next
+	}
+	return results, nil
+}
+
+func (p *Provider) ShowPullRequest(number int) (*provider.PullRequest, error) {
+	var mr mergeRequest
+	if err := p.client.get(projectPath(p.pInfo.Project, "merge_requests", strconv.Itoa(number)), nil, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toProvider(), nil
+}
+
+func (p *Provider) ListPullRequest(opt *provider.ListPullRequestOption) ([]provider.PullRequest, error) {
+	params, err := listParams(opt.Num, opt.Sort, opt.Direction)
+	if err != nil {
+		return nil, err
+	}
+	switch opt.States {
+	case provider.StateOpen:
+		params.Set("state", "opened")
+	case provider.StateClosed:
+		params.Set("state", "closed")
+	case provider.StateMerged:
+		params.Set("state", "merged")
+	default:
+		return nil, &provider.NotSupportedOptionError{Provider: "GitLab", Option: "merge request state", Value: opt.States}
+	}
+
+	results := []provider.PullRequest{}
+	for page := 1; page != 0 && len(results) < opt.Num; {
+		params.Set("page", strconv.Itoa(page))
+		var mrs []mergeRequest
+		next, err := p.client.list(projectPath(p.pInfo.Project, "merge_requests"), params, &mrs)
+		if err != nil {
+			return nil, err
+		}
+		for i := 0; i < len(mrs) && len(results) < opt.Num; i++ {
+			results = append(results, *mrs[i].toProvider())
+		}
+		page = next
+	}
+	return results, nil
+}
+
+func (p *Provider) ListRelease(opt *provider.ListReleaseOption) ([]provider.Release, error) {
+	params := url.Values{}
+	params.Set("per_page", strconv.Itoa(opt.Num))
+	switch opt.Sort {
+	case provider.SortCreatedAt:
+		params.Set("order_by", "created_at")
+	default:
+		return nil, &provider.NotSupportedOptionError{Provider: "GitLab", Option: "release sort", Value: opt.Sort}
+	}
+	sort, err := toSort(opt.Direction)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("sort", sort)
+
+	var releases []release
+	if err := p.client.get(projectPath(p.pInfo.Project, "releases"), params, &releases); err != nil {
+		return nil, err
+	}
+
+	results := make([]provider.Release, len(releases))
+	for i := range releases {
+		results[i] = *releases[i].toProvider()
+	}
+	return results, nil
+}
+
+// ShowRelease returns the release of the tag, the tag is escaped because it may have "/".
+func (p *Provider) ShowRelease(tagName string) (*provider.Release, error) {
+	var r release
+	if err := p.client.get(projectPath(p.pInfo.Project, "releases", url.PathEscape(tagName)), nil, &r); err != nil {
+		return nil, err
+	}
+	return r.toProvider(), nil
+}
+
+func (p *Provider) ListIssueComments(number int) ([]provider.Comment, error) {
+	return p.listNotes("issues", number)
+}
+
+func (p *Provider) ListPullRequestComments(number int) ([]provider.Comment, error) {
+	return p.listNotes("merge_requests", number)
+}
+
+func (p *Provider) CommentIssue(number int, body string) error {
+	return p.addNote("issues", number, body)
+}
+
+func (p *Provider) CommentPullRequest(number int, body string) error {
+	return p.addNote("merge_requests", number, body)
+}
+
+func (p *Provider) EditIssueComment(number int, commentID string, body string) error {
+	return p.editNote("issues", number, commentID, body)
+}
+
+func (p *Provider) EditPullRequestComment(number int, commentID string, body string) error {
+	return p.editNote("merge_requests", number, commentID, body)
+}
+
+func (p *Provider) CurrentUser() (string, error) {
+	var u user
+	if err := p.client.get("user", nil, &u); err != nil {
+		return "", err
+	}
+	return u.Username, nil
+}
+
+func (p *Provider) CloseIssue(number int) error {
+	return p.update("issues", number, map[string]interface{}{"state_event": "close"})
+}
+
+func (p *Provider) ClosePullRequest(number int) error {
+	return p.update("merge_requests", number, map[string]interface{}{"state_event": "close"})
+}
+
+func (p *Provider) CreateIssue(opt *provider.CreateIssueOption) (*provider.Issue, error) {
+	payload := map[string]interface{}{
+		"title":       opt.Title,
+		"description": opt.Body,
+	}
+	var i issue
+	if err := p.client.do("POST", projectPath(p.pInfo.Project, "issues"), nil, payload, &i); err != nil {
+		return nil, err
+	}
+	return i.toProvider(), nil
+}
+
+func (p *Provider) CreatePullRequest(opt *provider.CreatePullRequestOption) (*provider.PullRequest, error) {
+	payload := map[string]interface{}{
+		"source_branch": opt.Head,
+		"target_branch": opt.Base,
+		"title":         opt.Title,
+		"description":   opt.Body,
+	}
+	var mr mergeRequest
+	if err := p.client.do("POST", projectPath(p.pInfo.Project, "merge_requests"), nil, payload, &mr); err != nil {
+		return nil, err
+	}
+	return mr.toProvider(), nil
+}
+
+func (p *Provider) LabelIssue(number int, labels []string) error {
+	return p.update("issues", number, map[string]interface{}{"add_labels": strings.Join(labels, ",")})
+}
+
+func (p *Provider) LabelPullRequest(number int, labels []string) error {
+	return p.update("merge_requests", number, map[string]interface{}{"add_labels": strings.Join(labels, ",")})
+}
+
+func (p *Provider) AssignIssue(number int, users []string) error {
+	var i issue
+	if err := p.client.get(projectPath(p.pInfo.Project, "issues", strconv.Itoa(number)), nil, &i); err != nil {
+		return err
+	}
+	return p.assign("issues", number, i.Assignees, users)
+}
+
+func (p *Provider) AssignPullRequest(number int, users []string) error {
+	var mr mergeRequest
+	if err := p.client.get(projectPath(p.pInfo.Project, "merge_requests", strconv.Itoa(number)), nil, &mr); err != nil {
+		return err
+	}
+	return p.assign("merge_requests", number, mr.Assignees, users)
+}
+
+func (p *Provider) PullRequestRef(number int) string {
+	return fmt.Sprintf("merge-requests/%d/head", number)
+}
+
+// listNotes returns all notes that are not system notes, e.g. "changed the description".
+func (p *Provider) listNotes(resource string, number int) ([]provider.Comment, error) {
+	results := []provider.Comment{}
+	for page := 1; ; page++ {
+		params := url.Values{}
+		params.Set("sort", "asc")
+		params.Set("order_by", "created_at")
+		params.Set("per_page", strconv.Itoa(perPage))
+		params.Set("page", strconv.Itoa(page))
+
+		var notes []note
+		if err := p.client.get(projectPath(p.pInfo.Project, resource, strconv.Itoa(number), "notes"), params, &notes); err != nil {
+			return nil, err
+		}
+		for _, n := range notes {
+			if n.System {
+				continue
+			}
+			results = append(results, provider.Comment{
+				ID:          strconv.Itoa(n.ID),
+				Author:      n.Author.Username,
+				PublishedAt: n.CreatedAt,
+				Body:        n.Body,
+			})
+		}
+		if len(notes) < perPage {
+			return results, nil
+		}
+	}
+}
+
+func (p *Provider) addNote(resource string, number int, body string) error {
+	path := projectPath(p.pInfo.Project, resource, strconv.Itoa(number), "notes")
+	return p.client.do("POST", path, nil, map[string]interface{}{"body": body}, nil)
+}
+
+func (p *Provider) editNote(resource string, number int, noteID string, body string) error {
+	path := projectPath(p.pInfo.Project, resource, strconv.Itoa(number), "notes", noteID)
+	return p.client.do("PUT", path, nil, map[string]interface{}{"body": body}, nil)
+}
+
+func (p *Provider) update(resource string, number int, payload map[string]interface{}) error {
+	path := projectPath(p.pInfo.Project, resource, strconv.Itoa(number))
+	return p.client.do("PUT", path, nil, payload, nil)
+}
+
+// assign adds users to the current assignees, GitLab API replaces all assignees by ids.
+func (p *Provider) assign(resource string, number int, current []user, usernames []string) error {
+	ids := []int{}
+	for _, u := range current {
+		ids = append(ids, u.ID)
+	}
+	for _, username := range usernames {
+		params := url.Values{}
+		params.Set("username", username)
+		var users []user
+		if err := p.client.get("users", params, &users); err != nil {
+			return err
+		}
+		if len(users) == 0 {
+			return fmt.Errorf("Not found user, %s", username)
+		}
+		ids = append(ids, users[0].ID)
+	}
+	return p.update(resource, number, map[string]interface{}{"assignee_ids": ids})
+}
+
+func (p *Provider) ReadDir(dir string) ([]string, error) {
+	params := url.Values{}
+	params.Set("path", dir)
+	params.Set("per_page", "100")
+	var entries []treeEntry
+	if err := p.client.get(projectPath(p.pInfo.Project, "repository", "tree"), params, &entries); err != nil {
+		return nil, err
+	}
+
+	names := []string{}
+	for _, e := range entries {
+		if e.Type == "blob" {
+			names = append(names, e.Name)
+		}
+	}
+	return names, nil
+}
+
+func (p *Provider) ReadFile(path string) ([]byte, error) {
+	params := url.Values{}
+	params.Set("ref", "HEAD")
+	var f file
+	if err := p.client.get(projectPath(p.pInfo.Project, "repository", "files", url.PathEscape(path)), params, &f); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(f.Content)
+}
+
+func (p *Provider) IssueURL(number int) string {
+	return strings.Join([]string{p.pInfo.SubpageUrl("-/issues"), strconv.Itoa(number)}, "/")
+}
+
+func (p *Provider) PullRequestURL(number int) string {
+	return strings.Join([]string{p.pInfo.SubpageUrl("-/merge_requests"), strconv.Itoa(number)}, "/")
+}
+
+func (p *Provider) ReleaseURL(tagName string) string {
+	return strings.Join([]string{p.pInfo.SubpageUrl("-/releases"), url.PathEscape(tagName)}, "/")
+}
+
+func (p *Provider) RepositoryURL() string {
+	return p.pInfo.RepositoryUrl()
+}
+
+func (p *Provider) TreeURL(ref provider.Ref, path string, startLine, endLine int) string {
+	if path == "" {
+		return p.pInfo.SubpageUrl("-/tree/" + ref.Name)
+	}
+	url := p.pInfo.SubpageUrl("-/blob/" + ref.Name + "/" + path)
+	if startLine == 0 {
+		return url
+	}
+	if endLine != 0 {
+		return fmt.Sprintf("%s#L%d-%d", url, startLine, endLine)
+	}
+	return fmt.Sprintf("%s#L%d", url, startLine)
+}
+
+func (p *Provider) CommitsURL(ref provider.Ref) string {
+	return p.pInfo.SubpageUrl("-/commits/" + ref.Name)
+}
+
+func (p *Provider) SettingsURL() string {
+	return p.pInfo.SubpageUrl("edit")
+}
+
+func (p *Provider) WikiURL() string {
+	return p.pInfo.SubpageUrl("-/wikis/home")
+}
+
+// ActionsURL returns the url of CI/CD pipelines
+func (p *Provider) ActionsURL() string {
+	return p.pInfo.SubpageUrl("-/pipelines")
+}
+
+// listParams returns the params of list API, the page is up to perPage and
+// more pages are read until num items.
+func listParams(num int, sort, direction string) (url.Values, error) {
+	params := url.Values{}
+	if num > perPage {
+		num = perPage
+	}
+	params.Set("per_page", strconv.Itoa(num))
+
+	switch sort {
+	case provider.SortCreatedAt:
+		params.Set("order_by", "created_at")
+	case provider.SortUpdatedAt:
+		params.Set("order_by", "updated_at")
+	default:
+		return nil, &provider.NotSupportedOptionError{Provider: "GitLab", Option: "sort", Value: sort}
+	}
+
+	s, err := toSort(direction)
+	if err != nil {
+		return nil, err
+	}
+	params.Set("sort", s)
+	return params, nil
+}
+
+func toSort(direction string) (string, error) {
+	switch direction {
+	case provider.DirectionAsc:
+		return "asc", nil
+	case provider.DirectionDesc:
+		return "desc", nil
+	}
+	return "", &provider.NotSupportedOptionError{Provider: "GitLab", Option: "order", Value: direction}
+}
+
+func (i *issue) toProvider() *provider.Issue {
+	return &provider.Issue{
+		ID:          strconv.Itoa(i.ID),
+		Number:      i.IID,
+		Author:      i.Author.Username,
+		PublishedAt: i.CreatedAt,
+		Title:       i.Title,
+		Body:        i.Description,
+	}
+}
+
+func (m *mergeRequest) toProvider() *provider.PullRequest {
+	return &provider.PullRequest{
+		ID:          strconv.Itoa(m.ID),
+		Number:      m.IID,
+		Author:      m.Author.Username,
+		PublishedAt: m.CreatedAt,
+		Title:       m.Title,
+		Body:        m.Description,
+	}
+}
+
+func (r *release) toProvider() *provider.Release {
+	return &provider.Release{
+		ID:          r.TagName,
+		Name:        r.Name,
+		TagName:     r.TagName,
+		Description: r.Description,
+	}
+}
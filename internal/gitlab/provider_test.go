@@ -0,0 +1,343 @@
+package gitlab
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/git"
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+func setupTestProvider(handler http.HandlerFunc) (*Provider, *httptest.Server) {
+	server := httptest.NewServer(handler)
+	pInfo := &git.GitLabProjectInfo{
+		Domain:  "gitlab.example.com",
+		Project: "group/subgroup/project",
+		Token:   "token",
+	}
+	return &Provider{
+		pInfo:  pInfo,
+		client: newClient(context.Background(), nil, server.URL+"/api/v4", pInfo.Token),
+	}, server
+}
+
+func TestProvider_ListIssue(t *testing.T) {
+	p, server := setupTestProvider(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.EscapedPath(), "/api/v4/projects/group%2Fsubgroup%2Fproject/issues"; got != want {
+			t.Errorf("bad request path \nwant %q \ngot  %q", want, got)
+		}
+		if got, want := r.URL.RawQuery, "order_by=updated_at&page=1&per_page=20&sort=asc&state=opened"; got != want {
+			t.Errorf("bad request query \nwant %q \ngot  %q", want, got)
+		}
+		if got := r.Header.Get("PRIVATE-TOKEN"); got != "token" {
+			t.Errorf("bad private token, %q", got)
+		}
+		fmt.Fprint(w, `[{"id":100,"iid":1,"title":"title","description":"body","author":{"username":"user"},"created_at":"2019-07-01T00:00:00Z"}]`)
+	})
+	defer server.Close()
+
+	got, err := p.ListIssue(&provider.ListIssueOption{
+		Num:       20,
+		Sort:      provider.SortUpdatedAt,
+		Direction: provider.DirectionAsc,
+		States:    provider.StateOpen,
+	})
+	if err != nil {
+		t.Fatalf("ListIssue() error = %v", err)
+	}
+	want := []provider.Issue{{
+		ID:          "100",
+		Number:      1,
+		Author:      "user",
+		PublishedAt: time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+		Title:       "title",
+		Body:        "body",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListIssue() \nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestProvider_ListIssue_Pages(t *testing.T) {
+	var queries []string
+	p, server := setupTestProvider(func(w http.ResponseWriter, r *http.Request) {
+		queries = append(queries, r.URL.RawQuery)
+		page := r.URL.Query().Get("page")
+		if page == "1" {
+			w.Header().Set("X-Next-Page", "2")
+		}
+		items := []string{}
+		for i := 0; i < 100; i++ {
+			items = append(items, fmt.Sprintf(`{"iid":%s%02d}`, page, i))
+		}
+		fmt.Fprint(w, "["+strings.Join(items, ",")+"]")
+	})
+	defer server.Close()
+
+	got, err := p.ListIssue(&provider.ListIssueOption{
+		Num:       150,
+		Sort:      provider.SortCreatedAt,
+		Direction: provider.DirectionDesc,
+		States:    provider.StateOpen,
+	})
+	if err != nil {
+		t.Fatalf("ListIssue() error = %v", err)
+	}
+	if len(got) != 150 || got[149].Number != 249 {
+		t.Errorf("ListIssue() want 150 issues to #249, got %d", len(got))
+	}
+	want := []string{
+		"order_by=created_at&page=1&per_page=100&sort=desc&state=opened",
+		"order_by=created_at&page=2&per_page=100&sort=desc&state=opened",
+	}
+	if !reflect.DeepEqual(queries, want) {
+		t.Errorf("bad request queries \nwant %#v \ngot  %#v", want, queries)
+	}
+}
+
+func TestProvider_ListIssue_NotSupportedSort(t *testing.T) {
+	p := NewProvider(context.Background(), &git.GitLabProjectInfo{Domain: "gitlab.example.com", Project: "group/project"}, nil)
+	_, err := p.ListIssue(&provider.ListIssueOption{
+		Num:       20,
+		Sort:      provider.SortComments,
+		Direction: provider.DirectionDesc,
+		States:    provider.StateOpen,
+	})
+	if _, ok := err.(*provider.NotSupportedOptionError); !ok {
+		t.Errorf("ListIssue() want NotSupportedOptionError, got %v", err)
+	}
+}
+
+func TestProvider_ShowPullRequest_Error(t *testing.T) {
+	p, server := setupTestProvider(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.EscapedPath(), "/api/v4/projects/group%2Fsubgroup%2Fproject/merge_requests/3"; got != want {
+			t.Errorf("bad request path \nwant %q \ngot  %q", want, got)
+		}
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"message":"404 Not found"}`)
+	})
+	defer server.Close()
+
+	_, err := p.ShowPullRequest(3)
+	var errRes *errorResponse
+	if !errors.As(err, &errRes) || errRes.StatusCode != http.StatusNotFound {
+		t.Errorf("ShowPullRequest() want 404 error, got %v", err)
+	}
+	if _, ok := err.(*provider.NotFoundError); !ok {
+		t.Errorf("ShowPullRequest() want NotFoundError, got %T", err)
+	}
+}
+
+func TestProvider_ShowRelease(t *testing.T) {
+	p, server := setupTestProvider(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.EscapedPath(), "/api/v4/projects/group%2Fsubgroup%2Fproject/releases/release%2Fv1.0.0"; got != want {
+			t.Errorf("bad request path \nwant %q \ngot  %q", want, got)
+		}
+		fmt.Fprint(w, `{"name":"v1.0.0","tag_name":"release/v1.0.0","description":"notes"}`)
+	})
+	defer server.Close()
+
+	got, err := p.ShowRelease("release/v1.0.0")
+	if err != nil {
+		t.Fatalf("ShowRelease() error = %v", err)
+	}
+	if got.TagName != "release/v1.0.0" || got.Description != "notes" {
+		t.Errorf("ShowRelease() unexpected release, %#v", got)
+	}
+}
+
+func TestProvider_URL(t *testing.T) {
+	p := NewProvider(context.Background(), &git.GitLabProjectInfo{Domain: "gitlab.example.com", Project: "group/subgroup/project"}, nil)
+	tests := []struct {
+		got  string
+		want string
+	}{
+		{got: p.IssueURL(1), want: "https://gitlab.example.com/group/subgroup/project/-/issues/1"},
+		{got: p.PullRequestURL(2), want: "https://gitlab.example.com/group/subgroup/project/-/merge_requests/2"},
+		{got: p.ReleaseURL("v1.0.0"), want: "https://gitlab.example.com/group/subgroup/project/-/releases/v1.0.0"},
+		{got: p.TreeURL(provider.Ref{Name: "master"}, "", 0, 0), want: "https://gitlab.example.com/group/subgroup/project/-/tree/master"},
+		{got: p.TreeURL(provider.Ref{Name: "master"}, "cmd/root.go", 10, 0), want: "https://gitlab.example.com/group/subgroup/project/-/blob/master/cmd/root.go#L10"},
+		{got: p.TreeURL(provider.Ref{Name: "abc123", Commit: true}, "cmd/root.go", 10, 20), want: "https://gitlab.example.com/group/subgroup/project/-/blob/abc123/cmd/root.go#L10-20"},
+		{got: p.CommitsURL(provider.Ref{Name: "master"}), want: "https://gitlab.example.com/group/subgroup/project/-/commits/master"},
+		{got: p.ActionsURL(), want: "https://gitlab.example.com/group/subgroup/project/-/pipelines"},
+	}
+	for i, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("#%d: bad url \nwant %q \ngot  %q", i, tt.want, tt.got)
+		}
+	}
+}
+
+func TestProvider_ListIssueComments(t *testing.T) {
+	p, server := setupTestProvider(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.EscapedPath(), "/api/v4/projects/group%2Fsubgroup%2Fproject/issues/1/notes"; got != want {
+			t.Errorf("bad request path \nwant %q \ngot  %q", want, got)
+		}
+		fmt.Fprint(w, `[
+			{"id":10,"body":"changed the description","author":{"username":"user"},"created_at":"2019-07-01T00:00:00Z","system":true},
+			{"id":11,"body":"comment","author":{"username":"user"},"created_at":"2019-07-02T00:00:00Z","system":false}
+		]`)
+	})
+	defer server.Close()
+
+	got, err := p.ListIssueComments(1)
+	if err != nil {
+		t.Fatalf("ListIssueComments() error = %v", err)
+	}
+	want := []provider.Comment{{
+		ID:          "11",
+		Author:      "user",
+		PublishedAt: time.Date(2019, 7, 2, 0, 0, 0, 0, time.UTC),
+		Body:        "comment",
+	}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListIssueComments() \nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestProvider_CloseIssue(t *testing.T) {
+	p, server := setupTestProvider(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Method, "PUT"; got != want {
+			t.Errorf("bad request method \nwant %q \ngot  %q", want, got)
+		}
+		if got, want := r.URL.EscapedPath(), "/api/v4/projects/group%2Fsubgroup%2Fproject/issues/1"; got != want {
+			t.Errorf("bad request path \nwant %q \ngot  %q", want, got)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"state_event":"close"}`; got != want {
+			t.Errorf("bad request body \nwant %q \ngot  %q", want, got)
+		}
+		fmt.Fprint(w, `{}`)
+	})
+	defer server.Close()
+
+	if err := p.CloseIssue(1); err != nil {
+		t.Errorf("CloseIssue() error = %v", err)
+	}
+}
+
+func TestProvider_CreatePullRequest(t *testing.T) {
+	p, server := setupTestProvider(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Method, "POST"; got != want {
+			t.Errorf("bad request method \nwant %q \ngot  %q", want, got)
+		}
+		if got, want := r.URL.EscapedPath(), "/api/v4/projects/group%2Fsubgroup%2Fproject/merge_requests"; got != want {
+			t.Errorf("bad request path \nwant %q \ngot  %q", want, got)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"description":"body","source_branch":"feature","target_branch":"main","title":"title"}`; got != want {
+			t.Errorf("bad request body \nwant %q \ngot  %q", want, got)
+		}
+		fmt.Fprint(w, `{"id":200,"iid":2,"title":"title","description":"body","author":{"username":"user"},"created_at":"2019-07-01T00:00:00Z"}`)
+	})
+	defer server.Close()
+
+	got, err := p.CreatePullRequest(&provider.CreatePullRequestOption{
+		Title: "title",
+		Body:  "body",
+		Base:  "main",
+		Head:  "feature",
+	})
+	if err != nil {
+		t.Fatalf("CreatePullRequest() error = %v", err)
+	}
+	want := &provider.PullRequest{
+		ID:          "200",
+		Number:      2,
+		Author:      "user",
+		PublishedAt: time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+		Title:       "title",
+		Body:        "body",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CreatePullRequest() \nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestProvider_ReadFile(t *testing.T) {
+	p, server := setupTestProvider(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.RequestURI() {
+		case "/api/v4/projects/group%2Fsubgroup%2Fproject/repository/tree?path=.github&per_page=100":
+			fmt.Fprint(w, `[{"name":"ISSUE_TEMPLATE","type":"tree"},{"name":"pull_request_template.md","type":"blob"}]`)
+		case "/api/v4/projects/group%2Fsubgroup%2Fproject/repository/files/.github%2Fpull_request_template.md?ref=HEAD":
+			fmt.Fprint(w, `{"file_path":".github/pull_request_template.md","content":"IyMgU3VtbWFyeQo="}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"message":"404 File Not Found"}`)
+		}
+	})
+	defer server.Close()
+
+	names, err := p.ReadDir(".github")
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if want := []string{"pull_request_template.md"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("ReadDir() \nwant %#v \ngot  %#v", want, names)
+	}
+
+	b, err := p.ReadFile(".github/pull_request_template.md")
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+	if got, want := string(b), "## Summary\n"; got != want {
+		t.Errorf("ReadFile() \nwant %q \ngot  %q", want, got)
+	}
+
+	_, err = p.ReadFile(".github/ISSUE_TEMPLATE.md")
+	var notFoundErr *provider.NotFoundError
+	if !errors.As(err, &notFoundErr) {
+		t.Errorf("ReadFile() want NotFoundError, got %v", err)
+	}
+}
+
+func TestProvider_EditPullRequestComment(t *testing.T) {
+	p, server := setupTestProvider(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Method, "PUT"; got != want {
+			t.Errorf("bad request method \nwant %q \ngot  %q", want, got)
+		}
+		if got, want := r.URL.EscapedPath(), "/api/v4/projects/group%2Fsubgroup%2Fproject/merge_requests/3/notes/302"; got != want {
+			t.Errorf("bad request path \nwant %q \ngot  %q", want, got)
+		}
+		body, _ := ioutil.ReadAll(r.Body)
+		if got, want := string(body), `{"body":"edited"}`; got != want {
+			t.Errorf("bad request body \nwant %q \ngot  %q", want, got)
+		}
+		fmt.Fprint(w, `{}`)
+	})
+	defer server.Close()
+
+	if err := p.EditPullRequestComment(3, "302", "edited"); err != nil {
+		t.Errorf("EditPullRequestComment() error = %v", err)
+	}
+}
+
+func TestProvider_ListLabels(t *testing.T) {
+	p, server := setupTestProvider(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.URL.EscapedPath(), "/api/v4/projects/group%2Fsubgroup%2Fproject/labels"; got != want {
+			t.Errorf("bad request path \nwant %q \ngot  %q", want, got)
+		}
+		if got, want := r.URL.Query().Get("include_ancestor_groups"), "false"; got != want {
+			t.Errorf("bad include_ancestor_groups \nwant %q \ngot  %q", want, got)
+		}
+		fmt.Fprint(w, `[{"id":1,"name":"bug","color":"#D73A4A","description":"Something isn't working"}]`)
+	})
+	defer server.Close()
+
+	got, err := p.ListLabels()
+	if err != nil {
+		t.Fatalf("ListLabels() error = %v", err)
+	}
+	want := []provider.Label{{Name: "bug", Color: "d73a4a", Description: "Something isn't working"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ListLabels() \nwant %#v \ngot  %#v", want, got)
+	}
+}
@@ -0,0 +1,279 @@
+// Package httpcache caches the responses of REST and GraphQL APIs on disk.
+package httpcache
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/cache"
+)
+
+// HeaderFromCache is set to the response served from the cache.
+const HeaderFromCache = "X-Huc-Cache"
+
+// storeTTL is how long the stale responses are kept for the revalidation by ETag.
+const storeTTL = 7 * 24 * time.Hour
+
+type ttlKey struct{}
+
+// WithTTL returns the context that the response of request is cached for ttl,
+// it overrides TTL of Transport.
+func WithTTL(ctx context.Context, ttl time.Duration) context.Context {
+	return context.WithValue(ctx, ttlKey{}, ttl)
+}
+
+// Transport serves the cached responses of GET requests and GraphQL queries
+// within TTL. The expired response is revalidated by "If-None-Match" when it
+// has ETag. Nothing is cached when TTL is zero, but the cache of repository is
+// cleared by the successful request that may change it, e.g. closing an issue.
+type Transport struct {
+	Base  http.RoundTripper
+	Cache *cache.Cache
+	TTL   time.Duration
+
+	now func() time.Time
+}
+
+// NewTransport returns Transport that stores the responses in the cache directory of huc.
+func NewTransport(base http.RoundTripper, ttl time.Duration) *Transport {
+	return &Transport{
+		Base:  base,
+		Cache: cache.New(filepath.Join(cache.DefaultDir(), "http"), storeTTL),
+		TTL:   ttl,
+		now:   time.Now,
+	}
+}
+
+// entry is the cached response
+type entry struct {
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+	StoredAt   time.Time
+}
+
+func (e *entry) response(req *http.Request) *http.Response {
+	header := http.Header{}
+	for k, v := range e.Header {
+		header[k] = v
+	}
+	header.Set(HeaderFromCache, "hit")
+	return &http.Response{
+		Status:        http.StatusText(e.StatusCode),
+		StatusCode:    e.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(bytes.NewReader(e.Body)),
+		ContentLength: int64(len(e.Body)),
+		Request:       req,
+	}
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) ttl(req *http.Request) time.Duration {
+	if ttl, ok := req.Context().Value(ttlKey{}).(time.Duration); ok {
+		return ttl
+	}
+	return t.TTL
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req, key, cacheable, err := cacheKey(req)
+	if err != nil {
+		return nil, err
+	}
+	c := t.scope(req.URL.Host, repository(req))
+	if !cacheable {
+		res, err := t.base().RoundTrip(req)
+		if err == nil && res.StatusCode < 400 && req.Method != "HEAD" && req.Method != "OPTIONS" {
+			// The cached responses of the repository may be changed by the request
+			c.Clear()
+		}
+		return res, err
+	}
+
+	ttl := t.ttl(req)
+	if ttl <= 0 {
+		return t.base().RoundTrip(req)
+	}
+
+	var e entry
+	if !c.Get(key, &e) {
+		return t.fetch(c, req, key)
+	}
+	if t.now().Sub(e.StoredAt) < ttl {
+		return e.response(req), nil
+	}
+
+	etag := e.Header.Get("ETag")
+	if etag == "" || req.Method != "GET" {
+		return t.fetch(c, req, key)
+	}
+	revalidate := req.Clone(req.Context())
+	revalidate.Header.Set("If-None-Match", etag)
+	res, err := t.base().RoundTrip(revalidate)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusNotModified {
+		return t.store(c, key, res)
+	}
+	res.Body.Close()
+	e.StoredAt = t.now()
+	c.Set(key, &e)
+	return e.response(req), nil
+}
+
+// scope returns the cache of the repository in the host. The repository is
+// the directory in the one of host, the cache of host includes all of them
+// when repo is empty.
+func (t *Transport) scope(host, repo string) *cache.Cache {
+	dir := filepath.Join(t.Cache.Dir, hash([]byte(strings.ToLower(host))))
+	if repo != "" {
+		dir = filepath.Join(dir, hash([]byte(strings.ToLower(repo))))
+	}
+	return cache.New(dir, t.Cache.TTL)
+}
+
+func (t *Transport) fetch(c *cache.Cache, req *http.Request, key string) (*http.Response, error) {
+	res, err := t.base().RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	return t.store(c, key, res)
+}
+
+// store caches the successful response and returns it with the buffered body.
+func (t *Transport) store(c *cache.Cache, key string, res *http.Response) (*http.Response, error) {
+	if res.StatusCode != http.StatusOK {
+		return res, nil
+	}
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	// GraphQL API responds errors with 200
+	if isGraphQL(res.Request) && hasGraphQLErrors(body) {
+		return res, nil
+	}
+	// Cache is the optimization, the response is returned when it's failed
+	c.Set(key, &entry{
+		StatusCode: res.StatusCode,
+		Header:     res.Header,
+		Body:       body,
+		StoredAt:   t.now(),
+	})
+	return res, nil
+}
+
+// repositoryPathRe matches the repository in the path of REST API, e.g.
+// "/repos/owner/repo" of GitHub and Gitea and "/projects/group%2Fproject" of GitLab.
+var repositoryPathRe = regexp.MustCompile(`/(?:repos/([^/]+/[^/]+)|projects/([^/]+))`)
+
+// repository returns the repository of request, e.g. "owner/repo". The one of
+// GraphQL is the variables "repositoryOwner" and "repositoryName". It's empty
+// when the request is not of a repository, e.g. the mutation by node ID.
+func repository(req *http.Request) string {
+	if isGraphQL(req) {
+		if req.GetBody == nil {
+			return ""
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return ""
+		}
+		defer body.Close()
+		var payload struct {
+			Variables struct {
+				Owner string `json:"repositoryOwner"`
+				Name  string `json:"repositoryName"`
+			} `json:"variables"`
+		}
+		if err := json.NewDecoder(body).Decode(&payload); err != nil || payload.Variables.Name == "" {
+			return ""
+		}
+		return payload.Variables.Owner + "/" + payload.Variables.Name
+	}
+
+	m := repositoryPathRe.FindStringSubmatch(req.URL.EscapedPath())
+	if m == nil {
+		return ""
+	}
+	repo, err := url.PathUnescape(m[1] + m[2])
+	if err != nil {
+		return ""
+	}
+	return repo
+}
+
+// cacheKey returns the key of request, it's false when the request is not
+// cacheable. The request is returned with the copy of body that is read to
+// hash the GraphQL query.
+func cacheKey(req *http.Request) (*http.Request, string, bool, error) {
+	// The responses are different by the user
+	auth := req.Header.Get("Authorization") + req.Header.Get("PRIVATE-TOKEN")
+	parts := []string{req.Method, req.URL.String(), hash([]byte(auth)), req.Header.Get("Accept")}
+
+	switch {
+	case req.Method == "GET":
+		return req, strings.Join(parts, "\n"), true, nil
+	case req.Method == "POST" && isGraphQL(req) && req.Body != nil:
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, "", false, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+
+		var payload struct {
+			Query string `json:"query"`
+		}
+		if err := json.Unmarshal(body, &payload); err != nil || strings.HasPrefix(strings.TrimSpace(payload.Query), "mutation") {
+			return req, "", false, nil
+		}
+		return req, strings.Join(append(parts, hash(body)), "\n"), true, nil
+	}
+	return req, "", false, nil
+}
+
+func isGraphQL(req *http.Request) bool {
+	return req != nil && strings.HasSuffix(req.URL.Path, "/graphql")
+}
+
+func hasGraphQLErrors(body []byte) bool {
+	var res struct {
+		Errors []interface{} `json:"errors"`
+	}
+	return json.Unmarshal(body, &res) == nil && len(res.Errors) > 0
+}
+
+func hash(b []byte) string {
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
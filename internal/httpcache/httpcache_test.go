@@ -0,0 +1,203 @@
+package httpcache
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/cache"
+)
+
+// testServer counts the requests and responds the ETag of the current body.
+type testServer struct {
+	body     string
+	requests []string
+}
+
+func (s *testServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path+" "+r.Header.Get("If-None-Match"))
+	etag := `"` + s.body + `"`
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Write([]byte(s.body))
+}
+
+func setupTestTransport(t *testing.T, ttl time.Duration) (*Transport, *time.Time, func()) {
+	dir, err := ioutil.TempDir("", "huc-httpcache")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	tr := &Transport{
+		Cache: cache.New(dir, storeTTL),
+		TTL:   ttl,
+		now:   func() time.Time { return now },
+	}
+	return tr, &now, func() { os.RemoveAll(dir) }
+}
+
+func request(t *testing.T, tr *Transport, method, url, body string) (string, bool) {
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Authorization", "token secret")
+	res, err := (&http.Client{Transport: tr}).Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(b), res.Header.Get(HeaderFromCache) != ""
+}
+
+func TestTransport_ETag(t *testing.T) {
+	s := &testServer{body: "first"}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	tr, now, teardown := setupTestTransport(t, time.Minute)
+	defer teardown()
+
+	tests := []struct {
+		name      string
+		elapsed   time.Duration
+		body      string
+		want      string
+		wantCache bool
+	}{
+		{name: "first request", want: "first"},
+		{name: "fresh", elapsed: 30 * time.Second, want: "first", wantCache: true},
+		{name: "not modified", elapsed: 2 * time.Minute, want: "first", wantCache: true},
+		{name: "modified", elapsed: 2 * time.Minute, body: "second", want: "second"},
+	}
+	for _, tt := range tests {
+		*now = now.Add(tt.elapsed)
+		if tt.body != "" {
+			s.body = tt.body
+		}
+		got, fromCache := request(t, tr, "GET", ts.URL+"/issues", "")
+		if got != tt.want || fromCache != tt.wantCache {
+			t.Errorf("%s: \nwant %q, %v \ngot  %q, %v", tt.name, tt.want, tt.wantCache, got, fromCache)
+		}
+	}
+
+	want := []string{
+		"GET /issues ",
+		`GET /issues "first"`,
+		`GET /issues "first"`,
+	}
+	if !reflect.DeepEqual(s.requests, want) {
+		t.Errorf("requests \nwant %#v \ngot  %#v", want, s.requests)
+	}
+}
+
+func TestTransport_GraphQL(t *testing.T) {
+	s := &testServer{body: `{"data":{}}`}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	tr, _, teardown := setupTestTransport(t, time.Minute)
+	defer teardown()
+
+	query := `{"query":"query{repository{issues{nodes{number}}}}"}`
+	other := `{"query":"query{repository{pullRequests{nodes{number}}}}"}`
+	mutation := `{"query":"mutation{closeIssue{clientMutationId}}"}`
+
+	tests := []struct {
+		name      string
+		body      string
+		wantCache bool
+	}{
+		{name: "query", body: query},
+		{name: "same query", body: query, wantCache: true},
+		{name: "other query", body: other},
+		{name: "mutation", body: mutation},
+		{name: "query after mutation", body: query},
+	}
+	for _, tt := range tests {
+		if _, fromCache := request(t, tr, "POST", ts.URL+"/graphql", tt.body); fromCache != tt.wantCache {
+			t.Errorf("%s: want from cache %v, got %v", tt.name, tt.wantCache, fromCache)
+		}
+	}
+	if len(s.requests) != 4 {
+		t.Errorf("want 4 requests, got %#v", s.requests)
+	}
+}
+
+func TestTransport_Invalidate(t *testing.T) {
+	s := &testServer{body: "first"}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	tr, _, teardown := setupTestTransport(t, time.Minute)
+	defer teardown()
+
+	repo := ts.URL + "/repos/owner/repo/issues"
+	other := ts.URL + "/repos/owner/other/issues"
+	project := ts.URL + "/api/v4/projects/group%2Fproject/issues"
+
+	tests := []struct {
+		name      string
+		method    string
+		url       string
+		wantCache bool
+	}{
+		{name: "repository", method: "GET", url: repo},
+		{name: "other repository", method: "GET", url: other},
+		{name: "project", method: "GET", url: project},
+		{name: "head", method: "HEAD", url: repo},
+		{name: "repository after head", method: "GET", url: repo, wantCache: true},
+		{name: "comment", method: "POST", url: ts.URL + "/repos/owner/repo/issues/1/comments"},
+		{name: "repository after comment", method: "GET", url: repo},
+		{name: "other repository after comment", method: "GET", url: other, wantCache: true},
+		{name: "project after comment", method: "GET", url: project, wantCache: true},
+		{name: "close project issue", method: "PUT", url: ts.URL + "/api/v4/projects/group%2Fproject/issues/1"},
+		{name: "project after close", method: "GET", url: project},
+		{name: "repository after close", method: "GET", url: repo, wantCache: true},
+	}
+	for _, tt := range tests {
+		if _, fromCache := request(t, tr, tt.method, tt.url, ""); fromCache != tt.wantCache {
+			t.Errorf("%s: want from cache %v, got %v", tt.name, tt.wantCache, fromCache)
+		}
+	}
+}
+
+func TestTransport_Disabled(t *testing.T) {
+	s := &testServer{body: "first"}
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+	tr, _, teardown := setupTestTransport(t, 0)
+	defer teardown()
+
+	for i := 0; i < 2; i++ {
+		if _, fromCache := request(t, tr, "GET", ts.URL+"/issues", ""); fromCache {
+			t.Errorf("want no cache when TTL is 0")
+		}
+	}
+
+	// The TTL of request overrides the transport
+	req, err := http.NewRequest("GET", ts.URL+"/issues", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req = req.WithContext(WithTTL(req.Context(), time.Minute))
+	for i := 0; i < 2; i++ {
+		res, err := tr.RoundTrip(req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		if fromCache := res.Header.Get(HeaderFromCache) != ""; fromCache != (i == 1) {
+			t.Errorf("request %d: want from cache %v, got %v", i, i == 1, fromCache)
+		}
+	}
+}
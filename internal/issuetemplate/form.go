@@ -0,0 +1,258 @@
+package issuetemplate
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/ui"
+)
+
+// The types of form element.
+// https://docs.github.com/en/communities/using-templates-to-encourage-useful-issues-and-pull-requests/syntax-for-githubs-form-schema
+const (
+	ElementMarkdown   = "markdown"
+	ElementInput      = "input"
+	ElementTextarea   = "textarea"
+	ElementDropdown   = "dropdown"
+	ElementCheckboxes = "checkboxes"
+)
+
+// noResponse is the value of empty field, it's same as GitHub.
+const noResponse = "_No response_"
+
+// Form is the YAML issue form.
+type Form struct {
+	Name        string     `yaml:"name"`
+	Description string     `yaml:"description"`
+	Title       string     `yaml:"title"`
+	Labels      stringList `yaml:"labels"`
+	Assignees   stringList `yaml:"assignees"`
+	Body        []Element  `yaml:"body"`
+}
+
+// Element is the field of form.
+type Element struct {
+	Type        string      `yaml:"type"`
+	ID          string      `yaml:"id"`
+	Attributes  Attributes  `yaml:"attributes"`
+	Validations Validations `yaml:"validations"`
+}
+
+type Attributes struct {
+	Label       string `yaml:"label"`
+	Description string `yaml:"description"`
+	Placeholder string `yaml:"placeholder"`
+	// Value is the default of input and textarea, or the text of markdown
+	Value string `yaml:"value"`
+	// Render is the language of code block that the textarea is rendered in
+	Render   string   `yaml:"render"`
+	Multiple bool     `yaml:"multiple"`
+	Options  []Option `yaml:"options"`
+}
+
+type Validations struct {
+	Required bool `yaml:"required"`
+}
+
+// Option is the option of dropdown or checkboxes. The option of dropdown is a
+// string, and the checkbox is a map of label and required.
+type Option struct {
+	Label    string `yaml:"label"`
+	Required bool   `yaml:"required"`
+}
+
+func (o *Option) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err == nil {
+		*o = Option{Label: s}
+		return nil
+	}
+	type plain Option
+	return unmarshal((*plain)(o))
+}
+
+func (f *Form) validate() error {
+	if len(f.Body) == 0 {
+		return fmt.Errorf("body is required")
+	}
+	for i, e := range f.Body {
+		switch e.Type {
+		case ElementMarkdown:
+			continue
+		case ElementInput, ElementTextarea:
+		case ElementDropdown, ElementCheckboxes:
+			if len(e.Attributes.Options) == 0 {
+				return fmt.Errorf("options of %s are required", e.label(i))
+			}
+		default:
+			return fmt.Errorf("unknown type of body, '%s'", e.Type)
+		}
+		if e.Attributes.Label == "" {
+			return fmt.Errorf("label of %s is required", e.label(i))
+		}
+	}
+	return nil
+}
+
+func (e *Element) label(i int) string {
+	if e.ID != "" {
+		return e.ID
+	}
+	return fmt.Sprintf("body[%d]", i)
+}
+
+// Ask asks the fields and returns the markdown body. The required fields are
+// asked again until they are answered.
+func (f *Form) Ask(u ui.UI) (string, error) {
+	var b bytes.Buffer
+	for i, e := range f.Body {
+		if e.Type == ElementMarkdown {
+			continue
+		}
+		if e.Attributes.Description != "" {
+			u.Message(e.Attributes.Description)
+		}
+
+		var value string
+		var err error
+		switch e.Type {
+		case ElementInput:
+			value, err = f.askInput(u, &e)
+		case ElementTextarea:
+			value, err = f.askTextarea(u, &e, i)
+		case ElementDropdown:
+			value, err = f.askDropdown(u, &e)
+		case ElementCheckboxes:
+			value, err = f.askCheckboxes(u, &e)
+		}
+		if err != nil {
+			return "", err
+		}
+		if value == "" {
+			value = noResponse
+		}
+		fmt.Fprintf(&b, "### %s\n\n%s\n\n", e.Attributes.Label, value)
+	}
+	return trimLines(&b), nil
+}
+
+func (f *Form) askInput(u ui.UI, e *Element) (string, error) {
+	query := e.Attributes.Label + ":"
+	if e.Attributes.Value != "" {
+		query = fmt.Sprintf("%s (%s):", e.Attributes.Label, e.Attributes.Value)
+	}
+	for {
+		value, err := u.Ask(query)
+		if err != nil {
+			return "", err
+		}
+		value = strings.TrimSpace(value)
+		if value == "" {
+			value = e.Attributes.Value
+		}
+		if value != "" || !e.Validations.Required {
+			return value, nil
+		}
+		u.Error(fmt.Sprintf("%s is required", e.Attributes.Label))
+	}
+}
+
+func (f *Form) askTextarea(u ui.UI, e *Element, i int) (string, error) {
+	text := e.Attributes.Value
+	if text == "" {
+		text = e.Attributes.Placeholder
+	}
+	filename := strings.ToUpper(e.label(i)) + ".md"
+	for {
+		value, err := u.Editor(filename, text)
+		if err != nil {
+			return "", err
+		}
+		value = strings.TrimSpace(value)
+		// The placeholder is not the answer, it's a hint as GitHub
+		if value == strings.TrimSpace(e.Attributes.Placeholder) && e.Attributes.Value == "" {
+			value = ""
+		}
+		if value == "" && e.Validations.Required {
+			u.Error(fmt.Sprintf("%s is required", e.Attributes.Label))
+			continue
+		}
+		if value != "" && e.Attributes.Render != "" {
+			value = fmt.Sprintf("```%s\n%s\n```", e.Attributes.Render, value)
+		}
+		return value, nil
+	}
+}
+
+func (f *Form) askDropdown(u ui.UI, e *Element) (string, error) {
+	options := make([]string, len(e.Attributes.Options))
+	for i, o := range e.Attributes.Options {
+		options[i] = o.Label
+	}
+	if !e.Attributes.Multiple {
+		i, err := u.Select(e.Attributes.Label, options)
+		if err != nil {
+			return "", err
+		}
+		return options[i], nil
+	}
+
+	for {
+		indices, err := u.MultiSelect(e.Attributes.Label, options)
+		if err != nil {
+			return "", err
+		}
+		selected := []string{}
+		for _, i := range indices {
+			selected = append(selected, options[i])
+		}
+		if len(selected) > 0 || !e.Validations.Required {
+			return strings.Join(selected, ", "), nil
+		}
+		u.Error(fmt.Sprintf("%s is required", e.Attributes.Label))
+	}
+}
+
+func (f *Form) askCheckboxes(u ui.UI, e *Element) (string, error) {
+	options := make([]string, len(e.Attributes.Options))
+	for i, o := range e.Attributes.Options {
+		options[i] = o.Label
+	}
+	for {
+		indices, err := u.MultiSelect(e.Attributes.Label, options)
+		if err != nil {
+			return "", err
+		}
+		checked := make([]bool, len(options))
+		for _, i := range indices {
+			checked[i] = true
+		}
+
+		missing := []string{}
+		lines := []string{}
+		for i, o := range e.Attributes.Options {
+			if o.Required && !checked[i] {
+				missing = append(missing, o.Label)
+			}
+			mark := " "
+			if checked[i] {
+				mark = "X"
+			}
+			lines = append(lines, fmt.Sprintf("- [%s] %s", mark, o.Label))
+		}
+		if len(missing) == 0 {
+			return strings.Join(lines, "\n"), nil
+		}
+		u.Error(fmt.Sprintf("Required to check, %s", strings.Join(missing, ", ")))
+	}
+}
+
+// trimLines removes the trailing spaces of each line, the rendered body is stable.
+func trimLines(b *bytes.Buffer) string {
+	lines := strings.Split(b.String(), "\n")
+	for i := range lines {
+		lines[i] = strings.TrimRight(lines[i], " \t")
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")) + "\n"
+}
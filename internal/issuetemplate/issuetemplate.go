@@ -0,0 +1,289 @@
+// Package issuetemplate finds the templates of issue and pull request in
+// ".github" of the repository. The markdown templates have the front matter
+// of name, labels and assignees, and the YAML issue forms are asked field by
+// field and rendered to the markdown body.
+package issuetemplate
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/ui"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// The kinds of template.
+const (
+	Issue       = "issue"
+	PullRequest = "pull_request"
+)
+
+const templateDir = ".github"
+
+// Source reads the files of repository, it's the local checkout or
+// provider.FileReader when there is no checkout.
+type Source interface {
+	// ReadDir returns the names of files in the directory
+	ReadDir(dir string) ([]string, error)
+	ReadFile(path string) ([]byte, error)
+}
+
+// DirSource reads the files of the local checkout in the directory.
+type DirSource string
+
+func (d DirSource) ReadDir(dir string) ([]string, error) {
+	infos, err := ioutil.ReadDir(filepath.Join(string(d), filepath.FromSlash(dir)))
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, info := range infos {
+		if !info.IsDir() {
+			names = append(names, info.Name())
+		}
+	}
+	return names, nil
+}
+
+func (d DirSource) ReadFile(path string) ([]byte, error) {
+	return ioutil.ReadFile(filepath.Join(string(d), filepath.FromSlash(path)))
+}
+
+// Template is the template of issue or pull request.
+type Template struct {
+	// Name is the name in the front matter or the form, it's the filename when it's empty
+	Name      string
+	About     string
+	Title     string
+	Labels    []string
+	Assignees []string
+	// Body is the markdown without the front matter
+	Body string
+	// Form is the issue form of YAML template, it's nil for markdown template
+	Form *Form
+	// Path is the path from the root of repository, e.g. ".github/ISSUE_TEMPLATE/bug.yml"
+	Path string
+}
+
+// Find returns the templates of the kind in the order of path. The single
+// template, e.g. ".github/pull_request_template.md", is matched ignoring case.
+func Find(src Source, kind string) ([]*Template, error) {
+	var single, dir string
+	exts := []string{".md"}
+	switch kind {
+	case Issue:
+		single = "issue_template.md"
+		dir = path.Join(templateDir, "ISSUE_TEMPLATE")
+		exts = append(exts, ".yml", ".yaml")
+	case PullRequest:
+		single = "pull_request_template.md"
+		dir = path.Join(templateDir, "PULL_REQUEST_TEMPLATE")
+	default:
+		return nil, fmt.Errorf("Invalid template kind, %s", kind)
+	}
+
+	paths := []string{}
+	names, err := readDir(src, templateDir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		if strings.EqualFold(name, single) {
+			paths = append(paths, path.Join(templateDir, name))
+		}
+	}
+	names, err = readDir(src, dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range names {
+		ext := strings.ToLower(path.Ext(name))
+		// config.yml is the settings of template chooser
+		if !hasString(exts, ext) || strings.TrimSuffix(name, path.Ext(name)) == "config" {
+			continue
+		}
+		paths = append(paths, path.Join(dir, name))
+	}
+	sort.Strings(paths)
+
+	templates := []*Template{}
+	for _, p := range paths {
+		b, err := src.ReadFile(p)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read template, %s", err)
+		}
+		t, err := Parse(p, b)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	return templates, nil
+}
+
+// readDir returns no files when the directory doesn't exist.
+func readDir(src Source, dir string) ([]string, error) {
+	names, err := src.ReadDir(dir)
+	var notFoundErr *provider.NotFoundError
+	if os.IsNotExist(err) || errors.As(err, &notFoundErr) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read templates, %s", err)
+	}
+	return names, nil
+}
+
+// Parse parses the markdown template or the YAML issue form by the extension of path.
+func Parse(p string, b []byte) (*Template, error) {
+	t := &Template{Path: p}
+	switch strings.ToLower(path.Ext(p)) {
+	case ".yml", ".yaml":
+		form := &Form{}
+		if err := yaml.Unmarshal(b, form); err != nil {
+			return nil, fmt.Errorf("Invalid issue form %s, %s", p, err)
+		}
+		if err := form.validate(); err != nil {
+			return nil, fmt.Errorf("Invalid issue form %s, %s", p, err)
+		}
+		t.Name = form.Name
+		t.About = form.Description
+		t.Title = form.Title
+		t.Labels = form.Labels
+		t.Assignees = form.Assignees
+		t.Form = form
+	default:
+		var m frontMatter
+		body, err := splitFrontMatter(b, &m)
+		if err != nil {
+			return nil, fmt.Errorf("Invalid front matter of %s, %s", p, err)
+		}
+		t.Name = m.Name
+		t.About = m.About
+		t.Title = m.Title
+		t.Labels = m.Labels
+		t.Assignees = m.Assignees
+		t.Body = body
+	}
+	if t.Name == "" {
+		t.Name = strings.TrimSuffix(path.Base(p), path.Ext(p))
+	}
+	return t, nil
+}
+
+type frontMatter struct {
+	Name      string     `yaml:"name"`
+	About     string     `yaml:"about"`
+	Title     string     `yaml:"title"`
+	Labels    stringList `yaml:"labels"`
+	Assignees stringList `yaml:"assignees"`
+}
+
+// splitFrontMatter parses the front matter between "---" lines and returns the rest.
+func splitFrontMatter(b []byte, dest interface{}) (string, error) {
+	text := strings.Replace(string(b), "\r\n", "\n", -1)
+	if !strings.HasPrefix(text, "---\n") {
+		return text, nil
+	}
+	end := strings.Index(text[4:], "\n---")
+	if end < 0 {
+		return text, nil
+	}
+	if err := yaml.Unmarshal([]byte(text[4:4+end]), dest); err != nil {
+		return "", err
+	}
+	body := text[4+end+len("\n---"):]
+	return strings.TrimPrefix(body, "\n"), nil
+}
+
+// stringList is the list of YAML, or the string separated by comma, e.g. "bug, help wanted".
+type stringList []string
+
+func (l *stringList) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var list []string
+	if err := unmarshal(&list); err == nil {
+		*l = list
+		return nil
+	}
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	*l = nil
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			*l = append(*l, v)
+		}
+	}
+	return nil
+}
+
+// Choose returns the template of the name, or asks the template when the
+// name is empty. It's nil when there is no template or the blank is chosen.
+func Choose(templates []*Template, name string, u ui.UI) (*Template, error) {
+	if name != "" {
+		for _, t := range templates {
+			base := path.Base(t.Path)
+			if strings.EqualFold(t.Name, name) || base == name || strings.TrimSuffix(base, path.Ext(base)) == name {
+				return t, nil
+			}
+		}
+		return nil, fmt.Errorf("Not found template, %s", name)
+	}
+
+	switch len(templates) {
+	case 0:
+		return nil, nil
+	case 1:
+		return templates[0], nil
+	}
+	options := make([]string, 0, len(templates)+1)
+	for _, t := range templates {
+		option := t.Name
+		if t.About != "" {
+			option += " - " + t.About
+		}
+		options = append(options, option)
+	}
+	options = append(options, "Blank")
+	i, err := u.Select("Choose a template", options)
+	if err != nil {
+		return nil, err
+	}
+	if i == len(templates) {
+		return nil, nil
+	}
+	return templates[i], nil
+}
+
+// ApplyTitle returns the title with the prefix of template, e.g. "[BUG] " of "[BUG] crash on start".
+// It's empty for the empty title, the prefix is not the title.
+func (t *Template) ApplyTitle(title string) string {
+	prefix := t.Title
+	if strings.TrimSpace(title) == "" {
+		return ""
+	}
+	if prefix == "" || strings.HasPrefix(title, strings.TrimSpace(prefix)) {
+		return title
+	}
+	if !strings.HasSuffix(prefix, " ") {
+		prefix += " "
+	}
+	return prefix + title
+}
+
+func hasString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,315 @@
+package issuetemplate
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/lighttiger2505/huc/internal/ui"
+)
+
+// mapSource is the files of repository by the path.
+type mapSource map[string]string
+
+func (s mapSource) ReadDir(dir string) ([]string, error) {
+	names := []string{}
+	for p := range s {
+		if filepath.ToSlash(filepath.Dir(p)) == dir {
+			names = append(names, filepath.Base(p))
+		}
+	}
+	if len(names) == 0 {
+		return nil, &provider.NotFoundError{Err: fmt.Errorf("Not found directory, %s", dir)}
+	}
+	return names, nil
+}
+
+func (s mapSource) ReadFile(path string) ([]byte, error) {
+	content, ok := s[path]
+	if !ok {
+		return nil, &provider.NotFoundError{Err: fmt.Errorf("Not found file, %s", path)}
+	}
+	return []byte(content), nil
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		path    string
+		content string
+		want    *Template
+	}{
+		{
+			name:    "front matter",
+			path:    ".github/ISSUE_TEMPLATE/feature.md",
+			content: "---\nname: Feature request\nabout: Suggest an idea\ntitle: '[FEAT]'\nlabels: enhancement, help wanted\nassignees:\n  - octocat\n---\n**Describe the solution**\n",
+			want: &Template{
+				Name:      "Feature request",
+				About:     "Suggest an idea",
+				Title:     "[FEAT]",
+				Labels:    []string{"enhancement", "help wanted"},
+				Assignees: []string{"octocat"},
+				Body:      "**Describe the solution**\n",
+				Path:      ".github/ISSUE_TEMPLATE/feature.md",
+			},
+		},
+		{
+			name:    "without front matter",
+			path:    ".github/pull_request_template.md",
+			content: "## Summary\r\n\r\n## Test plan\r\n",
+			want: &Template{
+				Name: "pull_request_template",
+				Body: "## Summary\n\n## Test plan\n",
+				Path: ".github/pull_request_template.md",
+			},
+		},
+		{
+			name:    "issue form",
+			path:    ".github/ISSUE_TEMPLATE/bug.yml",
+			content: "name: Bug report\ndescription: File a bug\nlabels: [bug, triage]\nbody:\n  - type: dropdown\n    attributes:\n      label: OS\n      options: [Linux, macOS]\n",
+			want: &Template{
+				Name:   "Bug report",
+				About:  "File a bug",
+				Labels: []string{"bug", "triage"},
+				Form: &Form{
+					Name:        "Bug report",
+					Description: "File a bug",
+					Labels:      stringList{"bug", "triage"},
+					Body: []Element{{
+						Type: ElementDropdown,
+						Attributes: Attributes{
+							Label:   "OS",
+							Options: []Option{{Label: "Linux"}, {Label: "macOS"}},
+						},
+					}},
+				},
+				Path: ".github/ISSUE_TEMPLATE/bug.yml",
+			},
+		},
+	}
+	for _, tt := range tests {
+		got, err := Parse(tt.path, []byte(tt.content))
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		// The labels of form are the same slice
+		if got.Form != nil {
+			got.Labels = []string(got.Form.Labels)
+		}
+		if !reflect.DeepEqual(tt.want, got) {
+			t.Errorf("%s: \nwant %#v \ngot  %#v", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestParse_InvalidForm(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "unknown type",
+			content: "name: Bug\nbody:\n  - type: radio\n    attributes:\n      label: OS\n",
+			wantErr: "Invalid issue form bug.yml, unknown type of body, 'radio'",
+		},
+		{
+			name:    "no options",
+			content: "name: Bug\nbody:\n  - type: dropdown\n    id: os\n    attributes:\n      label: OS\n",
+			wantErr: "Invalid issue form bug.yml, options of os are required",
+		},
+		{
+			name:    "no label",
+			content: "name: Bug\nbody:\n  - type: input\n",
+			wantErr: "Invalid issue form bug.yml, label of body[0] is required",
+		},
+	}
+	for _, tt := range tests {
+		_, err := Parse("bug.yml", []byte(tt.content))
+		if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("%s: error \nwant %#v \ngot  %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestFind(t *testing.T) {
+	src := mapSource{
+		".github/PULL_REQUEST_TEMPLATE.md":         "## Summary\n",
+		".github/CODEOWNERS":                       "* @octocat\n",
+		".github/ISSUE_TEMPLATE/config.yml":        "blank_issues_enabled: false\n",
+		".github/ISSUE_TEMPLATE/feature.md":        "---\nname: Feature\n---\n",
+		".github/ISSUE_TEMPLATE/bug.yaml":          "name: Bug\nbody:\n  - type: input\n    attributes:\n      label: Version\n",
+		".github/ISSUE_TEMPLATE/notes.txt":         "not a template",
+		".github/PULL_REQUEST_TEMPLATE/release.md": "## Release\n",
+	}
+	tests := []struct {
+		kind string
+		want []string
+	}{
+		{kind: Issue, want: []string{".github/ISSUE_TEMPLATE/bug.yaml", ".github/ISSUE_TEMPLATE/feature.md"}},
+		{kind: PullRequest, want: []string{".github/PULL_REQUEST_TEMPLATE.md", ".github/PULL_REQUEST_TEMPLATE/release.md"}},
+	}
+	for _, tt := range tests {
+		templates, err := Find(src, tt.kind)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.kind, err)
+		}
+		got := []string{}
+		for _, tmpl := range templates {
+			got = append(got, tmpl.Path)
+		}
+		if !reflect.DeepEqual(tt.want, got) {
+			t.Errorf("%s: \nwant %#v \ngot  %#v", tt.kind, tt.want, got)
+		}
+	}
+
+	templates, err := Find(mapSource{}, Issue)
+	if err != nil || len(templates) != 0 {
+		t.Errorf("no templates \nwant %#v \ngot  %#v, %v", []*Template{}, templates, err)
+	}
+}
+
+func TestDirSource(t *testing.T) {
+	dir, err := ioutil.TempDir("", "issuetemplate")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.MkdirAll(filepath.Join(dir, ".github", "ISSUE_TEMPLATE"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(filepath.Join(dir, ".github", "issue_template.md"), []byte("## Problem\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The directory of checkout without PULL_REQUEST_TEMPLATE is not an error
+	templates, err := Find(DirSource(dir), Issue)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(templates) != 1 || templates[0].Body != "## Problem\n" {
+		t.Errorf("templates \nwant %#v \ngot  %#v", ".github/issue_template.md", templates)
+	}
+}
+
+func TestChoose(t *testing.T) {
+	bug := &Template{Name: "Bug report", About: "File a bug", Path: ".github/ISSUE_TEMPLATE/bug.yml"}
+	feature := &Template{Name: "Feature", Path: ".github/ISSUE_TEMPLATE/feature.md"}
+	templates := []*Template{bug, feature}
+
+	tests := []struct {
+		name      string
+		templates []*Template
+		flag      string
+		answers   []ui.Answer
+		want      *Template
+		wantErr   error
+	}{
+		{name: "no templates", templates: nil, want: nil},
+		{name: "only one", templates: []*Template{feature}, want: feature},
+		{name: "by name", templates: templates, flag: "bug report", want: bug},
+		{name: "by filename", templates: templates, flag: "feature.md", want: feature},
+		{name: "by basename", templates: templates, flag: "bug", want: bug},
+		{
+			name:      "chosen",
+			templates: templates,
+			answers:   []ui.Answer{{Prompt: ui.PromptSelect, Indices: []int{1}}},
+			want:      feature,
+		},
+		{
+			name:      "blank",
+			templates: templates,
+			answers:   []ui.Answer{{Prompt: ui.PromptSelect, Indices: []int{2}}},
+			want:      nil,
+		},
+		{
+			name:      "aborted",
+			templates: templates,
+			answers:   []ui.Answer{{Prompt: ui.PromptSelect, Err: ui.ErrInterrupted}},
+			wantErr:   ui.ErrInterrupted,
+		},
+	}
+	for _, tt := range tests {
+		got, err := Choose(tt.templates, tt.flag, &ui.ScriptedUi{Answers: tt.answers})
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error \nwant %v \ngot  %v", tt.name, tt.wantErr, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: \nwant %#v \ngot  %#v", tt.name, tt.want, got)
+		}
+	}
+
+	if _, err := Choose(templates, "question", &ui.ScriptedUi{}); err == nil || err.Error() != "Not found template, question" {
+		t.Errorf("unknown template, got %v", err)
+	}
+}
+
+func TestTemplate_ApplyTitle(t *testing.T) {
+	tests := []struct {
+		prefix string
+		title  string
+		want   string
+	}{
+		{prefix: "", title: "Crash", want: "Crash"},
+		{prefix: "[BUG] ", title: "Crash", want: "[BUG] Crash"},
+		{prefix: "[BUG]", title: "Crash", want: "[BUG] Crash"},
+		{prefix: "[BUG] ", title: "[BUG] Crash", want: "[BUG] Crash"},
+		{prefix: "[BUG] ", title: "", want: ""},
+		{prefix: "[BUG] ", title: "  ", want: ""},
+	}
+	for _, tt := range tests {
+		tmpl := &Template{Title: tt.prefix}
+		if got := tmpl.ApplyTitle(tt.title); got != tt.want {
+			t.Errorf("ApplyTitle(%q) of %q \nwant %#v \ngot  %#v", tt.title, tt.prefix, tt.want, got)
+		}
+	}
+}
+
+func TestForm_Ask(t *testing.T) {
+	form := &Form{
+		Body: []Element{
+			{Type: ElementMarkdown, Attributes: Attributes{Value: "Thanks!"}},
+			{Type: ElementInput, ID: "version", Attributes: Attributes{Label: "Version", Value: "latest"}},
+			{Type: ElementTextarea, ID: "logs", Attributes: Attributes{Label: "Logs", Render: "shell", Placeholder: "Paste the logs"}},
+			{Type: ElementTextarea, ID: "steps", Attributes: Attributes{Label: "Steps", Placeholder: "1. Run"}, Validations: Validations{Required: true}},
+			{Type: ElementDropdown, Attributes: Attributes{Label: "Browsers", Multiple: true, Options: []Option{{Label: "Firefox"}, {Label: "Chrome"}, {Label: "Safari"}}}},
+			{Type: ElementCheckboxes, Attributes: Attributes{Label: "Checks", Options: []Option{{Label: "Searched issues", Required: true}, {Label: "Read the docs"}}}},
+		},
+	}
+	u := &ui.ScriptedUi{
+		Answers: []ui.Answer{
+			{Prompt: ui.PromptAsk, Query: "Version (latest):", Text: ""},
+			{Prompt: ui.PromptEditor, Query: "LOGS.md", Text: "panic: nil map\n"},
+			{Prompt: ui.PromptEditor, Query: "STEPS.md", Text: "1. Run"},
+			{Prompt: ui.PromptEditor, Query: "STEPS.md", Text: "1. Run huc issue create\n"},
+			{Prompt: ui.PromptMultiSelect, Query: "Browsers", Indices: []int{2, 0}},
+			{Prompt: ui.PromptMultiSelect, Query: "Checks", Indices: []int{1}},
+			{Prompt: ui.PromptMultiSelect, Query: "Checks", Indices: []int{0}},
+		},
+	}
+
+	got, err := form.Ask(u)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "### Version\n\nlatest\n\n" +
+		"### Logs\n\n```shell\npanic: nil map\n```\n\n" +
+		"### Steps\n\n1. Run huc issue create\n\n" +
+		"### Browsers\n\nSafari, Firefox\n\n" +
+		"### Checks\n\n- [X] Searched issues\n- [ ] Read the docs\n"
+	if got != want {
+		t.Errorf("body \nwant %q \ngot  %q", want, got)
+	}
+	wantErrors := []string{"Steps is required", "Required to check, Searched issues"}
+	if !reflect.DeepEqual(wantErrors, u.Errors) {
+		t.Errorf("errors \nwant %#v \ngot  %#v", wantErrors, u.Errors)
+	}
+	if len(u.Unanswered()) != 0 {
+		t.Errorf("unanswered %#v", u.Unanswered())
+	}
+}
@@ -0,0 +1,190 @@
+// Package labelsync reconciles the labels of repository with the manifest,
+// the YAML list of labels committed in the repository, e.g. "labels.yml".
+//
+//   - name: bug
+//     color: d73a4a
+//     description: Something isn't working
+//     aliases: [defect]
+//
+// The label of the alias is renamed to the name, the labels of issues are kept
+// by the rename.
+package labelsync
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Label is the label in the manifest.
+type Label struct {
+	Name        string   `yaml:"name"`
+	Color       string   `yaml:"color"`
+	Description string   `yaml:"description"`
+	Aliases     []string `yaml:"aliases"`
+}
+
+var colorPattern = regexp.MustCompile(`^[0-9a-f]{6}$`)
+
+// NormalizeColor returns the color in lower case without "#", e.g. "d73a4a" of "#D73A4A".
+func NormalizeColor(color string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.TrimSpace(color), "#"))
+}
+
+// ValidColor returns true when the color is the hex code of 6 digits.
+func ValidColor(color string) bool {
+	return colorPattern.MatchString(NormalizeColor(color))
+}
+
+// Parse parses the manifest. The names and the aliases must be unique
+// ignoring case, the labels of hosting services are case-insensitive.
+func Parse(b []byte) ([]Label, error) {
+	var labels []Label
+	if err := yaml.UnmarshalStrict(b, &labels); err != nil {
+		return nil, fmt.Errorf("Invalid label manifest, %s", err)
+	}
+
+	seen := map[string]bool{}
+	for i := range labels {
+		l := &labels[i]
+		l.Name = strings.TrimSpace(l.Name)
+		if l.Name == "" {
+			return nil, fmt.Errorf("Invalid label manifest, name of labels[%d] is required", i)
+		}
+		if !ValidColor(l.Color) {
+			return nil, fmt.Errorf("Invalid label manifest, color of %s must be hex code, '%s'", l.Name, l.Color)
+		}
+		l.Color = NormalizeColor(l.Color)
+		for _, name := range append([]string{l.Name}, l.Aliases...) {
+			key := strings.ToLower(name)
+			if seen[key] {
+				return nil, fmt.Errorf("Invalid label manifest, %s is duplicated", name)
+			}
+			seen[key] = true
+		}
+	}
+	return labels, nil
+}
+
+// The kinds of change.
+const (
+	Create = "create"
+	Update = "update"
+	Delete = "delete"
+)
+
+// Change is the change to the label of repository.
+type Change struct {
+	Kind string
+	// Current is the label in the repository, it's nil for Create
+	Current *provider.Label
+	// Label is the label of manifest, it's nil for Delete
+	Label *provider.Label
+}
+
+// String returns the change as diff, e.g. "~ defect -> bug, color e11d21 -> d73a4a".
+func (c *Change) String() string {
+	switch c.Kind {
+	case Create:
+		s := fmt.Sprintf("+ %s, color %s", c.Label.Name, c.Label.Color)
+		if c.Label.Description != "" {
+			s += fmt.Sprintf(", description %q", c.Label.Description)
+		}
+		return s
+	case Delete:
+		return "- " + c.Current.Name
+	}
+
+	s := "~ " + c.Current.Name
+	if c.Current.Name != c.Label.Name {
+		s += " -> " + c.Label.Name
+	}
+	if c.Current.Color != c.Label.Color {
+		s += fmt.Sprintf(", color %s -> %s", c.Current.Color, c.Label.Color)
+	}
+	if c.Current.Description != c.Label.Description {
+		s += fmt.Sprintf(", description %q -> %q", c.Current.Description, c.Label.Description)
+	}
+	return s
+}
+
+// Plan returns the changes to make the labels of repository same as the
+// manifest. The label is matched by the name ignoring case, or by the aliases
+// when the name doesn't exist. The labels not in the manifest are deleted by prune.
+func Plan(manifest []Label, current []provider.Label, prune bool) []Change {
+	byName := map[string]int{}
+	for i, l := range current {
+		byName[strings.ToLower(l.Name)] = i
+	}
+	used := make([]bool, len(current))
+
+	changes := []Change{}
+	for _, m := range manifest {
+		want := &provider.Label{Name: m.Name, Color: m.Color, Description: m.Description}
+		i, ok := byName[strings.ToLower(m.Name)]
+		if !ok {
+			for _, alias := range m.Aliases {
+				if j, found := byName[strings.ToLower(alias)]; found && !used[j] {
+					i, ok = j, true
+					break
+				}
+			}
+		}
+		if !ok {
+			changes = append(changes, Change{Kind: Create, Label: want})
+			continue
+		}
+
+		used[i] = true
+		cur := current[i]
+		cur.Color = NormalizeColor(cur.Color)
+		if cur != *want {
+			changes = append(changes, Change{Kind: Update, Current: &cur, Label: want})
+		}
+	}
+
+	if prune {
+		for i := range current {
+			if !used[i] {
+				changes = append(changes, Change{Kind: Delete, Current: &current[i]})
+			}
+		}
+	}
+	return changes
+}
+
+// Client changes the labels of repository, it's provider.Provider.
+type Client interface {
+	CreateLabel(label *provider.Label) error
+	UpdateLabel(name string, label *provider.Label) error
+	DeleteLabel(name string) error
+}
+
+// Apply applies the changes in order, it stops at the first error.
+func Apply(c Client, changes []Change) error {
+	for _, change := range changes {
+		var err error
+		switch change.Kind {
+		case Create:
+			err = c.CreateLabel(change.Label)
+		case Update:
+			err = c.UpdateLabel(change.Current.Name, change.Label)
+		case Delete:
+			err = c.DeleteLabel(change.Current.Name)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot %s label %s, %w", change.Kind, labelName(&change), err)
+		}
+	}
+	return nil
+}
+
+func labelName(c *Change) string {
+	if c.Current != nil {
+		return c.Current.Name
+	}
+	return c.Label.Name
+}
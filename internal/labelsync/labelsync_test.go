@@ -0,0 +1,182 @@
+package labelsync
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+func TestParse(t *testing.T) {
+	content := `
+- name: bug
+  color: "#D73A4A"
+  description: Something isn't working
+  aliases: [defect]
+- name: docs
+  color: 0075ca
+`
+	got, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Label{
+		{Name: "bug", Color: "d73a4a", Description: "Something isn't working", Aliases: []string{"defect"}},
+		{Name: "docs", Color: "0075ca"},
+	}
+	if !reflect.DeepEqual(want, got) {
+		t.Errorf("\nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "no name",
+			content: "- color: d73a4a\n",
+			wantErr: "Invalid label manifest, name of labels[0] is required",
+		},
+		{
+			name:    "invalid color",
+			content: "- name: bug\n  color: red\n",
+			wantErr: "Invalid label manifest, color of bug must be hex code, 'red'",
+		},
+		{
+			name:    "duplicated alias",
+			content: "- name: bug\n  color: d73a4a\n- name: defect\n  color: d73a4a\n  aliases: [Bug]\n",
+			wantErr: "Invalid label manifest, Bug is duplicated",
+		},
+		{
+			name:    "unknown field",
+			content: "- name: bug\n  colour: d73a4a\n",
+			wantErr: "Invalid label manifest, yaml: unmarshal errors:\n  line 2: field colour not found in type labelsync.Label",
+		},
+	}
+	for _, tt := range tests {
+		_, err := Parse([]byte(tt.content))
+		if err == nil || err.Error() != tt.wantErr {
+			t.Errorf("%s: error \nwant %#v \ngot  %v", tt.name, tt.wantErr, err)
+		}
+	}
+}
+
+func TestPlan(t *testing.T) {
+	manifest := []Label{
+		{Name: "bug", Color: "d73a4a", Description: "Something isn't working", Aliases: []string{"defect"}},
+		{Name: "docs", Color: "0075ca", Description: "Documentation"},
+		{Name: "good first issue", Color: "7057ff"},
+		{Name: "triage", Color: "fbca04"},
+	}
+	current := []provider.Label{
+		{Name: "defect", Color: "E11D21"},
+		{Name: "Docs", Color: "0075ca", Description: "Documentation"},
+		{Name: "good first issue", Color: "7057ff"},
+		{Name: "wontfix", Color: "ffffff"},
+	}
+
+	tests := []struct {
+		prune bool
+		want  []string
+	}{
+		{
+			prune: false,
+			want: []string{
+				`~ defect -> bug, color e11d21 -> d73a4a, description "" -> "Something isn't working"`,
+				`~ Docs -> docs`,
+				`+ triage, color fbca04`,
+			},
+		},
+		{
+			prune: true,
+			want: []string{
+				`~ defect -> bug, color e11d21 -> d73a4a, description "" -> "Something isn't working"`,
+				`~ Docs -> docs`,
+				`+ triage, color fbca04`,
+				`- wontfix`,
+			},
+		},
+	}
+	for _, tt := range tests {
+		changes := Plan(manifest, current, tt.prune)
+		got := []string{}
+		for _, c := range changes {
+			got = append(got, c.String())
+		}
+		if !reflect.DeepEqual(tt.want, got) {
+			t.Errorf("prune %v \nwant %#v \ngot  %#v", tt.prune, tt.want, got)
+		}
+	}
+}
+
+func TestPlan_NameAndAliasExist(t *testing.T) {
+	// The label of the name is kept, and the label of the alias is deleted only by prune
+	manifest := []Label{{Name: "bug", Color: "d73a4a", Aliases: []string{"defect"}}}
+	current := []provider.Label{
+		{Name: "defect", Color: "d73a4a"},
+		{Name: "bug", Color: "d73a4a"},
+	}
+	if got := Plan(manifest, current, false); len(got) != 0 {
+		t.Errorf("without prune \nwant no changes \ngot  %#v", got)
+	}
+	got := Plan(manifest, current, true)
+	if len(got) != 1 || got[0].String() != "- defect" {
+		t.Errorf("with prune \nwant %#v \ngot  %#v", "- defect", got)
+	}
+}
+
+// fakeClient records the calls, it fails the call of failName.
+type fakeClient struct {
+	calls    []string
+	failName string
+}
+
+func (c *fakeClient) call(name string) error {
+	c.calls = append(c.calls, name)
+	if name == c.failName {
+		return errors.New("Validation Failed")
+	}
+	return nil
+}
+
+func (c *fakeClient) CreateLabel(label *provider.Label) error {
+	return c.call("create " + label.Name)
+}
+
+func (c *fakeClient) UpdateLabel(name string, label *provider.Label) error {
+	return c.call("update " + name + " " + label.Name)
+}
+
+func (c *fakeClient) DeleteLabel(name string) error {
+	return c.call("delete " + name)
+}
+
+func TestApply(t *testing.T) {
+	changes := []Change{
+		{Kind: Update, Current: &provider.Label{Name: "defect"}, Label: &provider.Label{Name: "bug"}},
+		{Kind: Create, Label: &provider.Label{Name: "triage"}},
+		{Kind: Delete, Current: &provider.Label{Name: "wontfix"}},
+	}
+
+	c := &fakeClient{}
+	if err := Apply(c, changes); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"update defect bug", "create triage", "delete wontfix"}
+	if !reflect.DeepEqual(want, c.calls) {
+		t.Errorf("calls \nwant %#v \ngot  %#v", want, c.calls)
+	}
+
+	c = &fakeClient{failName: "create triage"}
+	err := Apply(c, changes)
+	if wantErr := "cannot create label triage, Validation Failed"; err == nil || err.Error() != wantErr {
+		t.Errorf("error \nwant %#v \ngot  %v", wantErr, err)
+	}
+	if want := []string{"update defect bug", "create triage"}; !reflect.DeepEqual(want, c.calls) {
+		t.Errorf("calls after error \nwant %#v \ngot  %#v", want, c.calls)
+	}
+}
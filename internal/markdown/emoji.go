@@ -0,0 +1,66 @@
+package markdown
+
+// emojis is the commonly used emoji shortcodes of GitHub and GitLab.
+var emojis = map[string]string{
+	"+1":                 "👍",
+	"-1":                 "👎",
+	"100":                "💯",
+	"art":                "🎨",
+	"arrow_down":         "⬇️",
+	"arrow_up":           "⬆️",
+	"bangbang":           "‼️",
+	"beers":              "🍻",
+	"bell":               "🔔",
+	"books":              "📚",
+	"boom":               "💥",
+	"bug":                "🐛",
+	"bulb":               "💡",
+	"checkered_flag":     "🏁",
+	"clap":               "👏",
+	"confused":           "😕",
+	"construction":       "🚧",
+	"cry":                "😢",
+	"eyes":               "👀",
+	"fire":               "🔥",
+	"green_heart":        "💚",
+	"hammer":             "🔨",
+	"heart":              "❤️",
+	"heavy_check_mark":   "✔️",
+	"heavy_minus_sign":   "➖",
+	"heavy_plus_sign":    "➕",
+	"hooray":             "🎉",
+	"information_source": "ℹ️",
+	"joy":                "😂",
+	"laughing":           "😆",
+	"link":               "🔗",
+	"lipstick":           "💄",
+	"lock":               "🔒",
+	"memo":               "📝",
+	"ok_hand":            "👌",
+	"package":            "📦",
+	"pencil":             "📝",
+	"pencil2":            "✏️",
+	"pray":               "🙏",
+	"question":           "❓",
+	"recycle":            "♻️",
+	"rocket":             "🚀",
+	"rotating_light":     "🚨",
+	"see_no_evil":        "🙈",
+	"shipit":             "🐿️",
+	"smile":              "😄",
+	"smiley":             "😃",
+	"sparkles":           "✨",
+	"star":               "⭐",
+	"tada":               "🎉",
+	"thinking":           "🤔",
+	"thumbsdown":         "👎",
+	"thumbsup":           "👍",
+	"truck":              "🚚",
+	"warning":            "⚠️",
+	"wave":               "👋",
+	"white_check_mark":   "✅",
+	"wink":               "😉",
+	"wrench":             "🔧",
+	"x":                  "❌",
+	"zap":                "⚡",
+}
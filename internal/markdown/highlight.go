@@ -0,0 +1,190 @@
+package markdown
+
+import (
+	"strings"
+	"unicode"
+)
+
+// syntax is the minimal definition of language for highlighting.
+type syntax struct {
+	keywords      map[string]bool
+	lineComments  []string
+	stringQuotes  string
+	caseSensitive bool
+}
+
+func newSyntax(keywords string, lineComments []string, stringQuotes string) *syntax {
+	s := &syntax{
+		keywords:      map[string]bool{},
+		lineComments:  lineComments,
+		stringQuotes:  stringQuotes,
+		caseSensitive: true,
+	}
+	for _, k := range strings.Fields(keywords) {
+		s.keywords[k] = true
+	}
+	return s
+}
+
+var (
+	goSyntax = newSyntax(
+		"break case chan const continue default defer else fallthrough for func go goto if import interface map package range return select struct switch type var nil true false iota",
+		[]string{"//"}, "\"'`",
+	)
+	jsSyntax = newSyntax(
+		"async await break case catch class const continue default delete do else export extends false finally for from function if import in instanceof interface let new null return static super switch this throw true try type typeof undefined var void while yield",
+		[]string{"//"}, "\"'`",
+	)
+	pythonSyntax = newSyntax(
+		"and as assert async await break class continue def del elif else except False finally for from global if import in is lambda None nonlocal not or pass raise return True try while with yield",
+		[]string{"#"}, "\"'",
+	)
+	rubySyntax = newSyntax(
+		"begin break case class def do else elsif end ensure false for if in module next nil not or redo rescue retry return self super then true undef unless until when while yield",
+		[]string{"#"}, "\"'",
+	)
+	shellSyntax = newSyntax(
+		"case do done elif else esac export fi for function if in local return then until while",
+		[]string{"#"}, "\"'",
+	)
+	rustSyntax = newSyntax(
+		"as async await break const continue crate else enum extern false fn for if impl in let loop match mod move mut pub ref return self Self static struct super trait true type unsafe use where while",
+		[]string{"//"}, "\"",
+	)
+	cSyntax = newSyntax(
+		"auto bool break case catch char class const continue default delete do double else enum extern false final float for if import int long namespace new null nullptr package private protected public return short signed static struct switch this throw true try typedef union unsigned using void volatile while",
+		[]string{"//"}, "\"'",
+	)
+	sqlSyntax = &syntax{
+		keywords:     map[string]bool{},
+		lineComments: []string{"--"},
+		stringQuotes: "'\"",
+	}
+	yamlSyntax = newSyntax("true false null yes no on off", []string{"#"}, "\"'")
+)
+
+func init() {
+	for _, k := range strings.Fields("select from where and or not insert into values update set delete create table drop alter join left right inner outer on as group by order having limit null is in") {
+		sqlSyntax.keywords[k] = true
+	}
+}
+
+var syntaxes = map[string]*syntax{
+	"go":         goSyntax,
+	"golang":     goSyntax,
+	"js":         jsSyntax,
+	"javascript": jsSyntax,
+	"ts":         jsSyntax,
+	"typescript": jsSyntax,
+	"jsx":        jsSyntax,
+	"tsx":        jsSyntax,
+	"json":       jsSyntax,
+	"py":         pythonSyntax,
+	"python":     pythonSyntax,
+	"rb":         rubySyntax,
+	"ruby":       rubySyntax,
+	"sh":         shellSyntax,
+	"bash":       shellSyntax,
+	"zsh":        shellSyntax,
+	"shell":      shellSyntax,
+	"console":    shellSyntax,
+	"rs":         rustSyntax,
+	"rust":       rustSyntax,
+	"c":          cSyntax,
+	"cpp":        cSyntax,
+	"c++":        cSyntax,
+	"java":       cSyntax,
+	"kotlin":     cSyntax,
+	"cs":         cSyntax,
+	"csharp":     cSyntax,
+	"sql":        sqlSyntax,
+	"yaml":       yamlSyntax,
+	"yml":        yamlSyntax,
+	"toml":       yamlSyntax,
+}
+
+const (
+	colorKeyword = "35"
+	colorString  = "32"
+	colorNumber  = "33"
+	colorComment = "90"
+)
+
+func colorize(text, color string) string {
+	return "\x1b[" + color + "m" + text + "\x1b[0m"
+}
+
+// highlight highlights a line of code by keywords, strings, numbers and comments.
+// The line is returned as it is for unknown language.
+func highlight(lang, line string) string {
+	s, ok := syntaxes[strings.ToLower(lang)]
+	if !ok {
+		return line
+	}
+
+	b := &strings.Builder{}
+	runes := []rune(line)
+	for i := 0; i < len(runes); {
+		rest := string(runes[i:])
+		if comment := s.lineComment(rest); comment {
+			b.WriteString(colorize(rest, colorComment))
+			break
+		}
+
+		c := runes[i]
+		switch {
+		case strings.ContainsRune(s.stringQuotes, c):
+			j := i + 1
+			for j < len(runes) && runes[j] != c {
+				if runes[j] == '\\' {
+					j++
+				}
+				j++
+			}
+			if j >= len(runes) {
+				j = len(runes) - 1
+			}
+			b.WriteString(colorize(string(runes[i:j+1]), colorString))
+			i = j + 1
+		case unicode.IsDigit(c):
+			j := i
+			for j < len(runes) && (unicode.IsDigit(runes[j]) || unicode.IsLetter(runes[j]) || runes[j] == '.' || runes[j] == '_') {
+				j++
+			}
+			b.WriteString(colorize(string(runes[i:j]), colorNumber))
+			i = j
+		case unicode.IsLetter(c) || c == '_':
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j]) || runes[j] == '_') {
+				j++
+			}
+			word := string(runes[i:j])
+			if s.isKeyword(word) {
+				b.WriteString(colorize(word, colorKeyword))
+			} else {
+				b.WriteString(word)
+			}
+			i = j
+		default:
+			b.WriteRune(c)
+			i++
+		}
+	}
+	return b.String()
+}
+
+func (s *syntax) lineComment(text string) bool {
+	for _, c := range s.lineComments {
+		if strings.HasPrefix(text, c) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *syntax) isKeyword(word string) bool {
+	if s.caseSensitive {
+		return s.keywords[word]
+	}
+	return s.keywords[strings.ToLower(word)]
+}
@@ -0,0 +1,239 @@
+package markdown
+
+import (
+	"regexp"
+	"strings"
+)
+
+type style int
+
+const (
+	styleNone style = 0
+	styleBold style = 1 << iota
+	styleItalic
+	styleStrike
+	styleCode
+	styleLink
+	styleURL
+	styleHeading
+	styleQuote
+	styleMarker
+	styleChecked
+)
+
+// ansi returns SGR parameters of the style.
+func (s style) ansi() string {
+	codes := []string{}
+	if s&(styleBold|styleHeading) != 0 {
+		codes = append(codes, "1")
+	}
+	if s&(styleQuote|styleURL) != 0 {
+		codes = append(codes, "2")
+	}
+	if s&styleItalic != 0 {
+		codes = append(codes, "3")
+	}
+	if s&styleLink != 0 {
+		codes = append(codes, "4")
+	}
+	if s&styleStrike != 0 {
+		codes = append(codes, "9")
+	}
+	switch {
+	case s&styleHeading != 0:
+		codes = append(codes, "36")
+	case s&styleCode != 0:
+		codes = append(codes, "33")
+	case s&styleLink != 0:
+		codes = append(codes, "34")
+	case s&styleChecked != 0:
+		codes = append(codes, "32")
+	case s&styleMarker != 0:
+		codes = append(codes, "35")
+	}
+	return strings.Join(codes, ";")
+}
+
+// span is a piece of text in the same style.
+type span struct {
+	text  string
+	style style
+}
+
+func (r *Renderer) style(text string, s style) string {
+	if !r.Color || s == styleNone || text == "" {
+		return text
+	}
+	return "\x1b[" + s.ansi() + "m" + text + "\x1b[0m"
+}
+
+func (r *Renderer) styleSpans(spans []span) string {
+	b := &strings.Builder{}
+	for _, s := range spans {
+		b.WriteString(r.style(s.text, s.style))
+	}
+	return b.String()
+}
+
+var (
+	linkRe     = regexp.MustCompile(`^!?\[((?:[^\[\]]|\[[^\[\]]*\])*)\]\(\s*<?([^\s)>]*)>?(?:\s+"[^"]*")?\s*\)`)
+	autoLinkRe = regexp.MustCompile(`^<((?:https?|ftp)://[^\s>]+|[^\s@<>]+@[^\s@<>]+)>`)
+	bareURLRe  = regexp.MustCompile(`^https?://[^\s<]*[^\s<.,:;"')\]]`)
+	emojiRe    = regexp.MustCompile(`^:([a-z0-9_+-]+):`)
+)
+
+// parseInline parses inline elements, e.g. emphasis, code spans and links.
+func parseInline(text string, base style) []span {
+	spans := []span{}
+	literal := []rune{}
+	add := func(s ...span) {
+		if len(literal) > 0 {
+			spans = append(spans, span{text: string(literal), style: base})
+			literal = []rune{}
+		}
+		spans = append(spans, s...)
+	}
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		c := runes[i]
+		rest := string(runes[i:])
+		prevIsWord := i > 0 && isWordRune(runes[i-1])
+
+		switch {
+		case c == '\\' && i+1 < len(runes) && strings.ContainsRune("\\`*_{}[]()#+-.!|~<>:", runes[i+1]):
+			literal = append(literal, runes[i+1])
+			i++
+			continue
+
+		case c == '`':
+			ticks := countPrefix(runes[i:], '`')
+			fence := strings.Repeat("`", ticks)
+			if end := strings.Index(string(runes[i+ticks:]), fence); end >= 0 {
+				code := []rune(string(runes[i+ticks:])[:end])
+				add(span{text: strings.TrimSpace(string(code)), style: base | styleCode})
+				i += ticks + len(code) + ticks - 1
+				continue
+			}
+			literal = append(literal, runes[i:i+ticks]...)
+			i += ticks - 1
+			continue
+
+		case strings.HasPrefix(rest, "**") || strings.HasPrefix(rest, "__"):
+			delim := rest[:2]
+			if delim == "__" && prevIsWord {
+				break
+			}
+			if inner, n, ok := findClosing(runes[i+2:], delim); ok {
+				add(parseInline(inner, base|styleBold)...)
+				i += 2 + n + 2 - 1
+				continue
+			}
+
+		case strings.HasPrefix(rest, "~~"):
+			if inner, n, ok := findClosing(runes[i+2:], "~~"); ok {
+				add(parseInline(inner, base|styleStrike)...)
+				i += 2 + n + 2 - 1
+				continue
+			}
+
+		case c == '*' || c == '_':
+			if c == '_' && prevIsWord {
+				break
+			}
+			if i+1 < len(runes) && runes[i+1] == ' ' {
+				break
+			}
+			if inner, n, ok := findClosing(runes[i+1:], string(c)); ok {
+				add(parseInline(inner, base|styleItalic)...)
+				i += 1 + n + 1 - 1
+				continue
+			}
+
+		case c == '[' || (c == '!' && strings.HasPrefix(rest, "![")):
+			if m := linkRe.FindStringSubmatch(rest); m != nil {
+				label := m[1]
+				url := m[2]
+				if c == '!' {
+					if label == "" {
+						label = "image"
+					}
+					add(span{text: "[" + label + "]", style: base | styleLink})
+				} else {
+					add(parseInline(label, base|styleLink)...)
+				}
+				if url != "" && url != label {
+					add(span{text: " (" + url + ")", style: base | styleURL})
+				}
+				i += len([]rune(m[0])) - 1
+				continue
+			}
+
+		case c == '<':
+			if m := autoLinkRe.FindStringSubmatch(rest); m != nil {
+				add(span{text: m[1], style: base | styleLink})
+				i += len([]rune(m[0])) - 1
+				continue
+			}
+
+		case c == 'h' && !prevIsWord:
+			if m := bareURLRe.FindString(rest); m != "" {
+				add(span{text: m, style: base | styleLink})
+				i += len([]rune(m)) - 1
+				continue
+			}
+
+		case c == ':':
+			if m := emojiRe.FindStringSubmatch(rest); m != nil {
+				if e, ok := emojis[m[1]]; ok {
+					literal = append(literal, []rune(e)...)
+					i += len([]rune(m[0])) - 1
+					continue
+				}
+			}
+		}
+		literal = append(literal, c)
+	}
+	add()
+	return spans
+}
+
+// findClosing finds the closing delimiter and returns the text before it and the length of text in runes.
+func findClosing(runes []rune, delim string) (string, int, bool) {
+	text := string(runes)
+	if strings.HasPrefix(text, " ") {
+		return "", 0, false
+	}
+	offset := 0
+	for {
+		idx := strings.Index(text[offset:], delim)
+		if idx < 0 {
+			return "", 0, false
+		}
+		end := offset + idx
+		// Skip escaped delimiter and delimiter preceded by space
+		if end == 0 || text[end-1] == '\\' || text[end-1] == ' ' {
+			offset = end + len(delim)
+			continue
+		}
+		// Single delimiter must not be a part of double delimiter
+		if len(delim) == 1 && strings.HasPrefix(text[end:], delim+delim) {
+			offset = end + 2
+			continue
+		}
+		inner := text[:end]
+		return inner, len([]rune(inner)), true
+	}
+}
+
+func countPrefix(runes []rune, c rune) int {
+	n := 0
+	for n < len(runes) && runes[n] == c {
+		n++
+	}
+	return n
+}
+
+func isWordRune(c rune) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
@@ -0,0 +1,533 @@
+// Package markdown renders GitHub flavored markdown for terminal.
+package markdown
+
+import (
+	"regexp"
+	"strings"
+
+	runewidth "github.com/mattn/go-runewidth"
+)
+
+// defaultRuleWidth is the width of horizontal rule when the width is not limited
+const defaultRuleWidth = 40
+
+// Renderer renders markdown to the text for terminal.
+type Renderer struct {
+	// Width is the column to wrap the text. The text is not wrapped when it's zero
+	Width int
+	// Color enables ANSI escape sequences for styles and syntax highlighting
+	Color bool
+}
+
+// Render renders markdown with the renderer of the width and color.
+func Render(src string, width int, color bool) string {
+	r := &Renderer{Width: width, Color: color}
+	return r.Render(src)
+}
+
+// Render renders markdown.
+func (r *Renderer) Render(src string) string {
+	src = strings.Replace(src, "\r\n", "\n", -1)
+	src = strings.Replace(src, "\t", "    ", -1)
+	src = stripControl(src)
+	blocks := r.renderBlocks(strings.Split(src, "\n"))
+	return strings.Join(blocks, "\n\n")
+}
+
+var (
+	fenceRe          = regexp.MustCompile("^ {0,3}(`{3,}|~{3,})\\s*([^`\\s]*)")
+	headingRe        = regexp.MustCompile(`^ {0,3}(#{1,6})(?:\s+(.*?))?(?:\s+#+)?\s*$`)
+	setextRe         = regexp.MustCompile(`^ {0,3}(=+|-+)\s*$`)
+	ruleRe           = regexp.MustCompile(`^ {0,3}(?:(?:-\s*){3,}|(?:\*\s*){3,}|(?:_\s*){3,})$`)
+	quoteRe          = regexp.MustCompile(`^ {0,3}> ?(.*)$`)
+	listItemRe       = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	taskRe           = regexp.MustCompile(`^\[([ xX])\]\s+(.*)$`)
+	tableDelimiterRe = regexp.MustCompile(`^\s*\|?\s*:?-+:?\s*(?:\|\s*:?-+:?\s*)*\|?\s*$`)
+)
+
+// renderBlocks renders lines to the blocks, e.g. paragraphs, lists and code blocks.
+func (r *Renderer) renderBlocks(lines []string) []string {
+	blocks := []string{}
+	paragraph := []string{}
+	flush := func() {
+		if len(paragraph) > 0 {
+			blocks = append(blocks, r.renderParagraph(paragraph))
+			paragraph = []string{}
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if strings.TrimSpace(line) == "" {
+			flush()
+			continue
+		}
+
+		// Setext heading is the paragraph underlined by "=" or "-"
+		if len(paragraph) > 0 {
+			if m := setextRe.FindStringSubmatch(line); m != nil {
+				level := 1
+				if strings.HasPrefix(m[1], "-") {
+					level = 2
+				}
+				blocks = append(blocks, r.renderHeading(level, strings.Join(paragraph, " ")))
+				paragraph = []string{}
+				continue
+			}
+		}
+
+		if m := fenceRe.FindStringSubmatch(line); m != nil {
+			flush()
+			code := []string{}
+			i++
+			for ; i < len(lines); i++ {
+				if isClosingFence(lines[i], m[1]) {
+					break
+				}
+				code = append(code, lines[i])
+			}
+			blocks = append(blocks, r.renderCode(m[2], code))
+			continue
+		}
+
+		if m := headingRe.FindStringSubmatch(line); m != nil {
+			flush()
+			blocks = append(blocks, r.renderHeading(len(m[1]), m[2]))
+			continue
+		}
+
+		if ruleRe.MatchString(line) {
+			flush()
+			blocks = append(blocks, r.renderRule())
+			continue
+		}
+
+		if quoteRe.MatchString(line) {
+			flush()
+			quote := []string{}
+			for ; i < len(lines); i++ {
+				m := quoteRe.FindStringSubmatch(lines[i])
+				if m == nil {
+					break
+				}
+				quote = append(quote, m[1])
+			}
+			i--
+			blocks = append(blocks, r.renderQuote(quote))
+			continue
+		}
+
+		if len(paragraph) == 0 && strings.Contains(line, "|") && i+1 < len(lines) && tableDelimiterRe.MatchString(lines[i+1]) && strings.Contains(lines[i+1], "-") {
+			rows := [][]string{splitTableRow(line)}
+			align := tableAlign(splitTableRow(lines[i+1]))
+			for i += 2; i < len(lines); i++ {
+				if strings.TrimSpace(lines[i]) == "" || !strings.Contains(lines[i], "|") {
+					break
+				}
+				rows = append(rows, splitTableRow(lines[i]))
+			}
+			i--
+			blocks = append(blocks, r.renderTable(rows, align))
+			continue
+		}
+
+		if listItemRe.MatchString(line) {
+			flush()
+			items := []*listItem{}
+			separator := " "
+			for ; i < len(lines); i++ {
+				if strings.TrimSpace(lines[i]) == "" {
+					// Blank line in the list continues with the next item or
+					// the indented paragraph of the last item
+					if i+1 < len(lines) && listItemRe.MatchString(lines[i+1]) {
+						continue
+					}
+					if i+1 < len(lines) && strings.HasPrefix(lines[i+1], "  ") && strings.TrimSpace(lines[i+1]) != "" {
+						separator = "\n\n"
+						continue
+					}
+					break
+				}
+				if m := listItemRe.FindStringSubmatch(lines[i]); m != nil {
+					items = append(items, newListItem(m[1], m[2], m[3]))
+					separator = " "
+					continue
+				}
+				// Lazy continuation line of the last item
+				if fenceRe.MatchString(lines[i]) || headingRe.MatchString(lines[i]) || ruleRe.MatchString(lines[i]) {
+					break
+				}
+				last := items[len(items)-1]
+				last.text = last.text + separator + strings.TrimSpace(lines[i])
+				separator = " "
+			}
+			i--
+			blocks = append(blocks, r.renderList(items))
+			continue
+		}
+
+		// Indented code block can not interrupt a paragraph
+		if len(paragraph) == 0 && strings.HasPrefix(line, "    ") {
+			code := []string{}
+			for ; i < len(lines); i++ {
+				if strings.TrimSpace(lines[i]) != "" && !strings.HasPrefix(lines[i], "    ") {
+					break
+				}
+				code = append(code, strings.TrimPrefix(lines[i], "    "))
+			}
+			i--
+			for len(code) > 0 && strings.TrimSpace(code[len(code)-1]) == "" {
+				code = code[:len(code)-1]
+			}
+			blocks = append(blocks, r.renderCode("", code))
+			continue
+		}
+
+		paragraph = append(paragraph, line)
+	}
+	flush()
+	return blocks
+}
+
+func isClosingFence(line, fence string) bool {
+	trimmed := strings.TrimSpace(line)
+	if !strings.HasPrefix(trimmed, fence[:1]) {
+		return false
+	}
+	return strings.Trim(trimmed, fence[:1]) == "" && len(trimmed) >= len(fence)
+}
+
+func (r *Renderer) renderParagraph(lines []string) string {
+	text := []string{}
+	for _, line := range lines {
+		line = strings.TrimLeft(line, " ")
+		switch {
+		case strings.HasSuffix(line, "  "):
+			text = append(text, strings.TrimRight(line, " ")+"\n")
+		case strings.HasSuffix(line, "\\"):
+			text = append(text, strings.TrimSuffix(line, "\\")+"\n")
+		default:
+			text = append(text, line+" ")
+		}
+	}
+	joined := strings.TrimRight(strings.Join(text, ""), " \n")
+	return strings.Join(r.wrap(parseInline(joined, styleNone), "", ""), "\n")
+}
+
+func (r *Renderer) renderHeading(level int, text string) string {
+	prefix := strings.Repeat("#", level) + " "
+	spans := append([]span{{text: prefix, style: styleHeading}}, parseInline(text, styleHeading)...)
+	return strings.Join(r.wrap(spans, "", strings.Repeat(" ", len(prefix))), "\n")
+}
+
+func (r *Renderer) renderRule() string {
+	width := r.Width
+	if width <= 0 || width > 80 {
+		width = defaultRuleWidth
+	}
+	return r.style(strings.Repeat("─", width), styleQuote)
+}
+
+func (r *Renderer) renderQuote(lines []string) string {
+	inner := &Renderer{Width: r.Width - 2, Color: r.Color}
+	if r.Width <= 0 {
+		inner.Width = 0
+	}
+	rendered := strings.Join(inner.renderBlocks(lines), "\n\n")
+	bar := r.style("│", styleQuote)
+	results := []string{}
+	for _, line := range strings.Split(rendered, "\n") {
+		results = append(results, strings.TrimRight(bar+" "+line, " "))
+	}
+	return strings.Join(results, "\n")
+}
+
+func (r *Renderer) renderCode(lang string, lines []string) string {
+	results := make([]string, len(lines))
+	for i, line := range lines {
+		if r.Color {
+			line = highlight(lang, line)
+		}
+		results[i] = "    " + line
+	}
+	return strings.Join(results, "\n")
+}
+
+type listItem struct {
+	level   int
+	marker  string
+	task    bool
+	checked bool
+	text    string
+}
+
+func newListItem(indent, marker, text string) *listItem {
+	item := &listItem{
+		level:  runewidth.StringWidth(indent) / 2,
+		marker: marker,
+		text:   text,
+	}
+	if !strings.ContainsAny(marker, "0123456789") {
+		item.marker = "•"
+	}
+	if m := taskRe.FindStringSubmatch(text); m != nil {
+		item.task = true
+		item.checked = m[1] != " "
+		item.text = m[2]
+	}
+	return item
+}
+
+func (r *Renderer) renderList(items []*listItem) string {
+	results := []string{}
+	for _, item := range items {
+		indent := strings.Repeat("  ", item.level)
+		marker := r.style(item.marker, styleMarker)
+		width := runewidth.StringWidth(item.marker) + 1
+		if item.task {
+			if item.checked {
+				marker = marker + " " + r.style("[x]", styleChecked)
+			} else {
+				marker = marker + " " + r.style("[ ]", styleMarker)
+			}
+			width += 4
+		}
+		spans := parseInline(item.text, styleNone)
+		results = append(results, r.wrap(spans, indent+marker+" ", indent+strings.Repeat(" ", width))...)
+	}
+	return strings.Join(results, "\n")
+}
+
+const (
+	alignLeft = iota
+	alignCenter
+	alignRight
+)
+
+func splitTableRow(line string) []string {
+	line = strings.TrimSpace(line)
+	line = strings.TrimPrefix(line, "|")
+	if strings.HasSuffix(line, "|") && !strings.HasSuffix(line, "\\|") {
+		line = strings.TrimSuffix(line, "|")
+	}
+
+	cells := []string{}
+	cell := []rune{}
+	escaped := false
+	for _, c := range line {
+		switch {
+		case escaped:
+			if c != '|' {
+				cell = append(cell, '\\')
+			}
+			cell = append(cell, c)
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '|':
+			cells = append(cells, strings.TrimSpace(string(cell)))
+			cell = []rune{}
+		default:
+			cell = append(cell, c)
+		}
+	}
+	return append(cells, strings.TrimSpace(string(cell)))
+}
+
+func tableAlign(delimiters []string) []int {
+	align := make([]int, len(delimiters))
+	for i, d := range delimiters {
+		left := strings.HasPrefix(d, ":")
+		right := strings.HasSuffix(d, ":")
+		switch {
+		case left && right:
+			align[i] = alignCenter
+		case right:
+			align[i] = alignRight
+		default:
+			align[i] = alignLeft
+		}
+	}
+	return align
+}
+
+func (r *Renderer) renderTable(rows [][]string, align []int) string {
+	columns := len(align)
+	cells := make([][][]span, len(rows))
+	widths := make([]int, columns)
+	for i, row := range rows {
+		cells[i] = make([][]span, columns)
+		for j := 0; j < columns; j++ {
+			text := ""
+			if j < len(row) {
+				text = row[j]
+			}
+			base := styleNone
+			if i == 0 {
+				base = styleBold
+			}
+			cells[i][j] = parseInline(text, base)
+			if w := spansWidth(cells[i][j]); w > widths[j] {
+				widths[j] = w
+			}
+		}
+	}
+
+	results := []string{}
+	for i, row := range cells {
+		columnTexts := make([]string, columns)
+		for j, cell := range row {
+			pad := widths[j] - spansWidth(cell)
+			text := r.styleSpans(cell)
+			switch align[j] {
+			case alignRight:
+				text = strings.Repeat(" ", pad) + text
+			case alignCenter:
+				text = strings.Repeat(" ", pad/2) + text + strings.Repeat(" ", pad-pad/2)
+			default:
+				text = text + strings.Repeat(" ", pad)
+			}
+			columnTexts[j] = " " + text + " "
+		}
+		results = append(results, strings.TrimRight(strings.Join(columnTexts, r.style("│", styleQuote)), " "))
+
+		if i == 0 {
+			borders := make([]string, columns)
+			for j, w := range widths {
+				borders[j] = strings.Repeat("─", w+2)
+			}
+			results = append(results, r.style(strings.Join(borders, "┼"), styleQuote))
+		}
+	}
+	return strings.Join(results, "\n")
+}
+
+// wrap wraps spans by words to the width of renderer, and prefixes each lines.
+func (r *Renderer) wrap(spans []span, firstPrefix, restPrefix string) []string {
+	lines := []string{}
+	prefix := firstPrefix
+	current := ""
+	currentWidth := 0
+	avail := r.Width - runewidth.StringWidth(stripANSI(restPrefix))
+	pending := false
+	// lastWide is true after the wide character, e.g. CJK, that can break without space
+	lastWide := false
+
+	newLine := func() {
+		lines = append(lines, strings.TrimRight(prefix+current, " "))
+		prefix = restPrefix
+		current = ""
+		currentWidth = 0
+		pending = false
+	}
+
+	for _, w := range splitWords(spans) {
+		if w.lineBreak {
+			newLine()
+			continue
+		}
+		if w.space {
+			pending = currentWidth > 0
+			lastWide = false
+			continue
+		}
+		width := spansWidth(w.spans)
+		if pending && r.Width > 0 && currentWidth+1+width > avail {
+			newLine()
+		}
+		if !pending && (w.wide || lastWide) && r.Width > 0 && currentWidth > 0 && currentWidth+width > avail {
+			newLine()
+		}
+		lastWide = w.wide
+		if pending {
+			current += " "
+			currentWidth++
+			pending = false
+		}
+		current += r.styleSpans(w.spans)
+		currentWidth += width
+	}
+	newLine()
+	return lines
+}
+
+type word struct {
+	spans     []span
+	space     bool
+	lineBreak bool
+	// wide is the word of a wide character, the line can break before and after it
+	wide bool
+}
+
+// splitWords splits spans into words, spaces and line breaks keeping the style of spans.
+// Each wide character, e.g. CJK, is a word because the text of them has no spaces.
+func splitWords(spans []span) []word {
+	words := []word{}
+	current := []span{}
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, word{spans: current})
+			current = []span{}
+		}
+	}
+	for _, s := range spans {
+		text := []rune{}
+		for _, c := range s.text {
+			switch {
+			case c == ' ' || c == '\n':
+				if len(text) > 0 {
+					current = append(current, span{text: string(text), style: s.style})
+					text = []rune{}
+				}
+				flush()
+				if c == '\n' {
+					words = append(words, word{lineBreak: true})
+				} else {
+					words = append(words, word{space: true})
+				}
+			case runewidth.RuneWidth(c) == 2:
+				if len(text) > 0 {
+					current = append(current, span{text: string(text), style: s.style})
+					text = []rune{}
+				}
+				flush()
+				words = append(words, word{spans: []span{{text: string(c), style: s.style}}, wide: true})
+			default:
+				text = append(text, c)
+			}
+		}
+		if len(text) > 0 {
+			current = append(current, span{text: string(text), style: s.style})
+		}
+	}
+	flush()
+	return words
+}
+
+func spansWidth(spans []span) int {
+	width := 0
+	for _, s := range spans {
+		width += runewidth.StringWidth(s.text)
+	}
+	return width
+}
+
+// stripControl removes the control characters except line feed and tab, the
+// escape sequences of untrusted text must not reach the terminal.
+func stripControl(s string) string {
+	return strings.Map(func(c rune) rune {
+		if c == '\n' || c == '\t' {
+			return c
+		}
+		if c < 0x20 || (c >= 0x7f && c <= 0x9f) {
+			return -1
+		}
+		return c
+	}, s)
+}
+
+var ansiRe = regexp.MustCompile("\x1b\\[[0-9;]*m")
+
+func stripANSI(s string) string {
+	return ansiRe.ReplaceAllString(s, "")
+}
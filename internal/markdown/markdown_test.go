@@ -0,0 +1,121 @@
+package markdown
+
+import (
+	"testing"
+)
+
+func TestRender(t *testing.T) {
+	tests := []struct {
+		name  string
+		src   string
+		width int
+		want  string
+	}{
+		{
+			name:  "heading",
+			src:   "# Title\n\nSub\n---",
+			width: 0,
+			want:  "# Title\n\n## Sub",
+		},
+		{
+			name:  "paragraph wrap",
+			src:   "The quick brown fox\njumps over the lazy dog",
+			width: 16,
+			want:  "The quick brown\nfox jumps over\nthe lazy dog",
+		},
+		{
+			name:  "hard line break",
+			src:   "first  \nsecond",
+			width: 0,
+			want:  "first\nsecond",
+		},
+		{
+			name:  "inline",
+			src:   "**bold** *em* `a * b` ~~old~~ snake_case_name",
+			width: 0,
+			want:  "bold em a * b old snake_case_name",
+		},
+		{
+			name:  "link and emoji",
+			src:   "See [docs](https://example.com) :rocket: :unknown: <https://huc.dev>",
+			width: 0,
+			want:  "See docs (https://example.com) 🚀 :unknown: https://huc.dev",
+		},
+		{
+			name:  "task list",
+			src:   "- [ ] todo\n- [x] done and wrapped\n  - nested",
+			width: 16,
+			want:  "• [ ] todo\n• [x] done and\n      wrapped\n  • nested",
+		},
+		{
+			name:  "ordered list",
+			src:   "1. one\n2. two",
+			width: 0,
+			want:  "1. one\n2. two",
+		},
+		{
+			name:  "code block",
+			src:   "```go\nfunc main() {\n}\n```\ntext",
+			width: 0,
+			want:  "    func main() {\n    }\n\ntext",
+		},
+		{
+			name:  "quote",
+			src:   "> quoted\n> text",
+			width: 0,
+			want:  "│ quoted text",
+		},
+		{
+			name:  "table",
+			src:   "| Name | Count |\n|:-----|------:|\n| a | 1 |\n| long | 100 |",
+			width: 0,
+			want:  " Name │ Count\n──────┼───────\n a    │     1\n long │   100",
+		},
+		{
+			name:  "rule",
+			src:   "a\n\n***\n\nb",
+			width: 5,
+			want:  "a\n\n─────\n\nb",
+		},
+		{
+			name:  "empty heading",
+			src:   "a\n#\nb",
+			width: 0,
+			want:  "a\n\n#\n\nb",
+		},
+		{
+			name:  "wide characters wrap",
+			src:   "日本語の文章は空白なしで折り返す",
+			width: 10,
+			want:  "日本語の文\n章は空白な\nしで折り返\nす",
+		},
+		{
+			name:  "paragraph in list item",
+			src:   "- first\n\n  second paragraph\n- next",
+			width: 0,
+			want:  "• first\n\n  second paragraph\n• next",
+		},
+		{
+			name:  "control characters",
+			src:   "safe\x1b]0;title\x07 \x1b[31mred\x1b[0m\x00",
+			width: 0,
+			want:  "safe]0;title [31mred[0m",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Render(tt.src, tt.width, false); got != tt.want {
+				t.Errorf("Render() \nwant %q \ngot  %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestRender_Color(t *testing.T) {
+	src := "# Title\n\n```go\nreturn \"ok\" // done\n```"
+	want := "\x1b[1;36m#\x1b[0m \x1b[1;36mTitle\x1b[0m\n\n" +
+		"    \x1b[35mreturn\x1b[0m \x1b[32m\"ok\"\x1b[0m \x1b[90m// done\x1b[0m"
+	if got := Render(src, 0, true); got != want {
+		t.Errorf("Render() \nwant %q \ngot  %q", want, got)
+	}
+}
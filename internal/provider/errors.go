@@ -0,0 +1,45 @@
+package provider
+
+import "net/http"
+
+// NotFoundError is returned when the resource doesn't exist, or it's not
+// visible with the token, e.g. the issue of private repository.
+type NotFoundError struct {
+	Err error
+}
+
+func (e *NotFoundError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *NotFoundError) Unwrap() error {
+	return e.Err
+}
+
+// AuthError is returned when the token is invalid, expired or not granted
+// the scopes required by the API.
+type AuthError struct {
+	// Scopes are the scopes of token that the API requires
+	Scopes []string
+	Err    error
+}
+
+func (e *AuthError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *AuthError) Unwrap() error {
+	return e.Err
+}
+
+// StatusError returns the typed error of the status code of API response,
+// err is returned as it is for the other status.
+func StatusError(statusCode int, scopes []string, err error) error {
+	switch statusCode {
+	case http.StatusUnauthorized:
+		return &AuthError{Scopes: scopes, Err: err}
+	case http.StatusNotFound:
+		return &NotFoundError{Err: err}
+	}
+	return err
+}
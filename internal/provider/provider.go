@@ -0,0 +1,273 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/markdown"
+)
+
+// Names of the hosting service. It's set to "provider" of profile.
+const (
+	GitHub  = "github"
+	GitLab  = "gitlab"
+	Gitea   = "gitea"
+	Forgejo = "forgejo"
+)
+
+// Provider is the interface of the hosting service.
+type Provider interface {
+	ShowIssue(number int) (*Issue, error)
+	ListIssue(opt *ListIssueOption) ([]Issue, error)
+	ShowPullRequest(number int) (*PullRequest, error)
+	ListPullRequest(opt *ListPullRequestOption) ([]PullRequest, error)
+	ListRelease(opt *ListReleaseOption) ([]Release, error)
+	// ShowRelease returns the release of the tag, it's NotFoundError when the tag has no release
+	ShowRelease(tagName string) (*Release, error)
+
+	ListIssueComments(number int) ([]Comment, error)
+	ListPullRequestComments(number int) ([]Comment, error)
+	CommentIssue(number int, body string) error
+	CommentPullRequest(number int, body string) error
+	// EditIssueComment replaces the body of the comment of the ID in ListIssueComments
+	EditIssueComment(number int, commentID string, body string) error
+	EditPullRequestComment(number int, commentID string, body string) error
+	// CurrentUser returns the login of the user of the token
+	CurrentUser() (string, error)
+	CloseIssue(number int) error
+	ClosePullRequest(number int) error
+	// CreateIssue creates the issue, the labels and the assignees are added by LabelIssue and AssignIssue
+	CreateIssue(opt *CreateIssueOption) (*Issue, error)
+	CreatePullRequest(opt *CreatePullRequestOption) (*PullRequest, error)
+	// LabelIssue adds the labels to the issue, the labels must exist in the repository
+	LabelIssue(number int, labels []string) error
+	LabelPullRequest(number int, labels []string) error
+	// AssignIssue adds the users to the assignees of the issue
+	AssignIssue(number int, users []string) error
+	AssignPullRequest(number int, users []string) error
+	// ListLabels returns all labels of the repository
+	ListLabels() ([]Label, error)
+	CreateLabel(label *Label) error
+	// UpdateLabel updates the label of the name, it's renamed when the name of label is different
+	UpdateLabel(name string, label *Label) error
+	DeleteLabel(name string) error
+	// PullRequestRef returns the ref of the remote to fetch the head of pull request
+	PullRequestRef(number int) string
+
+	IssueURL(number int) string
+	PullRequestURL(number int) string
+	ReleaseURL(tagName string) string
+
+	RepositoryURL() string
+	// TreeURL returns the url of the file or the directory at ref, startLine and endLine is 0 when not specified
+	TreeURL(ref Ref, path string, startLine, endLine int) string
+	CommitsURL(ref Ref) string
+	SettingsURL() string
+	WikiURL() string
+	ActionsURL() string
+}
+
+// Ref is the branch or the commit to browse.
+type Ref struct {
+	Name   string
+	Commit bool
+}
+
+// Values of list options, these are same as the enum of GitHub GraphQL API.
+const (
+	DirectionAsc  = "ASC"
+	DirectionDesc = "DESC"
+
+	SortComments  = "COMMENTS"
+	SortCreatedAt = "CREATED_AT"
+	SortUpdatedAt = "UPDATED_AT"
+	SortName      = "NAME"
+
+	StateOpen   = "OPEN"
+	StateClosed = "CLOSED"
+	StateMerged = "MERGED"
+)
+
+// Issue is the issue of any hosting service.
+type Issue struct {
+	ID          string
+	Number      int
+	Author      string
+	PublishedAt time.Time
+	Title       string
+	Body        string
+}
+
+func (i *Issue) ToString() string {
+	return i.Render(0, false)
+}
+
+// Render returns the issue that the body is rendered as markdown wrapped by the width.
+func (i *Issue) Render(width int, color bool) string {
+	return i.format(markdown.Render(i.Body, width, color))
+}
+
+// ToRawString returns the issue that the body is raw markdown.
+func (i *Issue) ToRawString() string {
+	return i.format(i.Body)
+}
+
+func (i *Issue) format(body string) string {
+	return fmt.Sprintf("Issue Number: %d (%s)\nTitle: %s\n\n%s",
+		i.Number,
+		i.ID,
+		i.Title,
+		body,
+	)
+}
+
+// PullRequest is the pull request of GitHub, or the merge request of GitLab.
+type PullRequest struct {
+	ID          string
+	Number      int
+	Author      string
+	PublishedAt time.Time
+	Title       string
+	Body        string
+}
+
+func (i *PullRequest) ToString() string {
+	return i.Render(0, false)
+}
+
+// Render returns the pull request that the body is rendered as markdown wrapped by the width.
+func (i *PullRequest) Render(width int, color bool) string {
+	return i.format(markdown.Render(i.Body, width, color))
+}
+
+// ToRawString returns the pull request that the body is raw markdown.
+func (i *PullRequest) ToRawString() string {
+	return i.format(i.Body)
+}
+
+func (i *PullRequest) format(body string) string {
+	return fmt.Sprintf("Pull Request Number: %d (%s)\nTitle: %s\n\n%s",
+		i.Number,
+		i.ID,
+		i.Title,
+		body,
+	)
+}
+
+// Release is the release of any hosting service.
+type Release struct {
+	ID          string
+	Name        string
+	TagName     string
+	Description string
+}
+
+func (i *Release) ToString() string {
+	return i.Render(0, false)
+}
+
+// Render returns the release that the description is rendered as markdown wrapped by the width.
+func (i *Release) Render(width int, color bool) string {
+	return i.format(markdown.Render(i.Description, width, color))
+}
+
+// ToRawString returns the release that the description is raw markdown.
+func (i *Release) ToRawString() string {
+	return i.format(i.Description)
+}
+
+func (i *Release) format(description string) string {
+	return fmt.Sprintf("%s\nTitle: %s\n\n%s",
+		i.ID,
+		i.TagName,
+		description,
+	)
+}
+
+type ListIssueOption struct {
+	Num       int
+	Sort      string
+	Direction string
+	States    string
+}
+
+type ListPullRequestOption struct {
+	Num       int
+	Sort      string
+	Direction string
+	States    string
+}
+
+type ListReleaseOption struct {
+	Num       int
+	Sort      string
+	Direction string
+}
+
+type CreateIssueOption struct {
+	Title string
+	Body  string
+}
+
+type CreatePullRequestOption struct {
+	Title string
+	Body  string
+	// Base is the branch that the changes are merged into
+	Base string
+	// Head is the branch of the changes
+	Head string
+}
+
+// Comment is the comment of issue or pull request.
+type Comment struct {
+	ID          string
+	Author      string
+	PublishedAt time.Time
+	Body        string
+}
+
+// Label is the label of repository.
+type Label struct {
+	Name string
+	// Color is the hex code without "#" in lower case, e.g. "d73a4a"
+	Color       string
+	Description string
+}
+
+// RateLimiter is implemented by the provider that reports the quota of API.
+type RateLimiter interface {
+	RateLimit() (*RateLimit, error)
+}
+
+// RateLimit is the quota of API, Cost is the points of the request to get it.
+type RateLimit struct {
+	Limit     int
+	Cost      int
+	Remaining int
+	Used      int
+	ResetAt   time.Time
+}
+
+// FileReader is implemented by the provider that reads the files of the
+// default branch, e.g. the templates of issue when there is no checkout.
+type FileReader interface {
+	// ReadDir returns the names of files in the directory, it's NotFoundError when the directory doesn't exist
+	ReadDir(dir string) ([]string, error)
+	// ReadFile returns the contents of file, it's NotFoundError when the file doesn't exist
+	ReadFile(path string) ([]byte, error)
+}
+
+// ErrNotSupported is returned when the operation is not available for the resource.
+var ErrNotSupported = errors.New("not supported operation")
+
+// NotSupportedOptionError is returned when the hosting service has not the option.
+type NotSupportedOptionError struct {
+	Provider string
+	Option   string
+	Value    string
+}
+
+func (e *NotSupportedOptionError) Error() string {
+	return fmt.Sprintf("%s is not supported %s option, %s", e.Provider, e.Option, e.Value)
+}
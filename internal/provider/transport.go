@@ -0,0 +1,70 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+// HTTPClient returns httpClient, or http.DefaultClient when it's nil.
+func HTTPClient(httpClient *http.Client) *http.Client {
+	if httpClient == nil {
+		return http.DefaultClient
+	}
+	return httpClient
+}
+
+// NewHTTPTransport returns the transport that connects to the API through
+// proxy. HTTPS_PROXY, HTTP_PROXY and NO_PROXY are used when proxy is nil, and
+// the hosts in NO_PROXY are connected directly in any case. The connections
+// are dialed to unixSocket instead of the host when it's not empty.
+func NewHTTPTransport(proxy *url.URL, unixSocket string) *http.Transport {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if unixSocket != "" {
+		dial := func(ctx context.Context, _, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, "unix", unixSocket)
+		}
+		tr.Proxy = nil
+		tr.DialContext = dial
+		// The server on the socket speaks plain HTTP even for https URLs
+		tr.DialTLSContext = dial
+		return tr
+	}
+
+	cfg := httpproxy.FromEnvironment()
+	if proxy != nil {
+		cfg.HTTPProxy = proxy.String()
+		cfg.HTTPSProxy = proxy.String()
+	}
+	proxyFunc := cfg.ProxyFunc()
+	tr.Proxy = func(req *http.Request) (*url.URL, error) {
+		return proxyFunc(req.URL)
+	}
+	return tr
+}
+
+// ParseProxy returns the URL of proxy, "http", "https" and "socks5" are
+// supported. It's nil when proxy is empty.
+func ParseProxy(proxy string) (*url.URL, error) {
+	if proxy == "" {
+		return nil, nil
+	}
+	u, err := url.Parse(proxy)
+	if err != nil {
+		return nil, fmt.Errorf("Invalid proxy, '%s'", proxy)
+	}
+	switch u.Scheme {
+	case "http", "https", "socks5":
+	default:
+		return nil, fmt.Errorf("Invalid proxy scheme, '%s'", proxy)
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("Invalid proxy, '%s'", proxy)
+	}
+	return u, nil
+}
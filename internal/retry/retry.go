@@ -0,0 +1,328 @@
+// Package retry retries the API requests failed by the server errors and the rate limits.
+package retry
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RateLimitError is returned when the quota of API is exhausted, the request
+// is not retried when the quota is reset after MaxDelay.
+type RateLimitError struct {
+	Reset time.Time
+	now   time.Time
+}
+
+func (e *RateLimitError) Error() string {
+	if e.Reset.IsZero() {
+		return "API rate limit exceeded"
+	}
+	return fmt.Sprintf("API rate limit exceeded, it's reset at %s (in %s)",
+		e.Reset.Local().Format("15:04:05"),
+		e.Reset.Sub(e.now).Round(time.Second),
+	)
+}
+
+// RateLimit is the quota of API reported by the response headers.
+type RateLimit struct {
+	Limit     int
+	Remaining int
+	Reset     time.Time
+}
+
+// lowRateLimit is the ratio of remaining quota that the warning is written.
+const lowRateLimit = 0.1
+
+// Transport retries the requests with exponential backoff. The server errors
+// are retried only for the requests that don't change resources, GET and
+// GraphQL queries, because the changes may be applied before the errors.
+// The rate limited requests are retried after "Retry-After" or the reset of
+// quota because they are not processed.
+type Transport struct {
+	Base       http.RoundTripper
+	MaxRetries int
+	BaseDelay  time.Duration
+	// MaxDelay is the longest wait for a retry
+	MaxDelay time.Duration
+	// Warn is written the warning once when the quota runs low, nothing is written when it's nil
+	Warn io.Writer
+
+	mu        sync.Mutex
+	rateLimit *RateLimit
+	warned    bool
+	sleep     func(time.Duration)
+	now       func() time.Time
+}
+
+func NewTransport(base http.RoundTripper) *Transport {
+	return &Transport{
+		Base:       base,
+		MaxRetries: 3,
+		BaseDelay:  time.Second,
+		MaxDelay:   time.Minute,
+		now:        time.Now,
+	}
+}
+
+// RateLimit returns the quota reported by the last response, it's nil when
+// the hosting service doesn't report it.
+func (t *Transport) RateLimit() *RateLimit {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.rateLimit
+}
+
+func (t *Transport) base() http.RoundTripper {
+	if t.Base != nil {
+		return t.Base
+	}
+	return http.DefaultTransport
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	body, idempotent, err := readBody(req)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 0; ; attempt++ {
+		r := req
+		if body != nil {
+			r = req.Clone(req.Context())
+			r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		}
+
+		res, err := t.base().RoundTrip(r)
+		if err != nil {
+			if !idempotent || attempt >= t.MaxRetries {
+				return nil, err
+			}
+			if err := t.wait(req, t.backoff(attempt)); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		t.updateRateLimit(res)
+
+		delay, retry, err := t.check(res, idempotent, attempt)
+		if err != nil {
+			return nil, err
+		}
+		if !retry {
+			return res, nil
+		}
+		if attempt >= t.MaxRetries {
+			return res, nil
+		}
+		res.Body.Close()
+		if err := t.wait(req, delay); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// check returns the delay of retry when the response is failed temporarily.
+// The response is closed when it returns the error.
+func (t *Transport) check(res *http.Response, idempotent bool, attempt int) (time.Duration, bool, error) {
+	switch res.StatusCode {
+	case http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		if delay, ok := retryAfter(res.Header, t.now()); ok {
+			return delay, idempotent && delay <= t.MaxDelay, nil
+		}
+		return t.backoff(attempt), idempotent, nil
+	case http.StatusTooManyRequests, http.StatusForbidden:
+	case http.StatusOK:
+		// GraphQL API responds the exhausted quota with 200
+		if !isGraphQL(res.Request) || !hasRateLimitedError(res) {
+			return 0, false, nil
+		}
+		return t.checkQuota(res, attempt)
+	default:
+		return 0, false, nil
+	}
+
+	if delay, ok := retryAfter(res.Header, t.now()); ok {
+		if delay > t.MaxDelay {
+			res.Body.Close()
+			return 0, false, &RateLimitError{Reset: t.now().Add(delay), now: t.now()}
+		}
+		return delay, true, nil
+	}
+	if res.Header.Get("X-RateLimit-Remaining") == "0" {
+		return t.checkQuota(res, attempt)
+	}
+	// The secondary rate limit of GitHub has no headers for the retry
+	if res.StatusCode == http.StatusTooManyRequests || isSecondaryRateLimit(res) {
+		return t.backoff(attempt), true, nil
+	}
+	return 0, false, nil
+}
+
+// checkQuota waits the reset of quota when it's soon.
+func (t *Transport) checkQuota(res *http.Response, attempt int) (time.Duration, bool, error) {
+	reset := rateLimitReset(res.Header)
+	delay := reset.Sub(t.now())
+	if reset.IsZero() || delay > t.MaxDelay {
+		res.Body.Close()
+		return 0, false, &RateLimitError{Reset: reset, now: t.now()}
+	}
+	if delay < 0 {
+		delay = t.backoff(attempt)
+	}
+	return delay, true, nil
+}
+
+func (t *Transport) backoff(attempt int) time.Duration {
+	delay := t.BaseDelay << uint(attempt)
+	if delay > t.MaxDelay || delay <= 0 {
+		return t.MaxDelay
+	}
+	return delay
+}
+
+// wait returns the error when the request is canceled while waiting.
+func (t *Transport) wait(req *http.Request, delay time.Duration) error {
+	if t.sleep != nil {
+		t.sleep(delay)
+		return req.Context().Err()
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-req.Context().Done():
+		return req.Context().Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// updateRateLimit records the quota of response headers, and warns when it
+// runs low. The exhausted quota is not warned because it's the error.
+func (t *Transport) updateRateLimit(res *http.Response) {
+	// GitLab reports the quota without "X-" prefix
+	prefix := "X-RateLimit-"
+	if res.Header.Get(prefix+"Remaining") == "" {
+		prefix = "RateLimit-"
+	}
+	remaining, err := strconv.Atoi(res.Header.Get(prefix + "Remaining"))
+	if err != nil {
+		return
+	}
+	limit, _ := strconv.Atoi(res.Header.Get(prefix + "Limit"))
+	rateLimit := &RateLimit{
+		Limit:     limit,
+		Remaining: remaining,
+		Reset:     rateLimitReset(res.Header),
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.rateLimit = rateLimit
+	if t.Warn == nil || t.warned || remaining == 0 || float64(remaining) >= float64(limit)*lowRateLimit {
+		return
+	}
+	t.warned = true
+	msg := fmt.Sprintf("Warning: API rate limit is running low, %d of %d requests remaining", remaining, limit)
+	if !rateLimit.Reset.IsZero() {
+		msg += fmt.Sprintf(", it's reset at %s", rateLimit.Reset.Local().Format("15:04:05"))
+	}
+	fmt.Fprintln(t.Warn, msg)
+}
+
+// readBody reads the body to send it again, it returns true when the request
+// doesn't change resources.
+func readBody(req *http.Request) ([]byte, bool, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, req.Method == "GET" || req.Method == "HEAD", nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, false, err
+	}
+	if req.Method != "POST" || !isGraphQL(req) {
+		return body, false, nil
+	}
+	var payload struct {
+		Query string `json:"query"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return body, false, nil
+	}
+	return body, !strings.HasPrefix(strings.TrimSpace(payload.Query), "mutation"), nil
+}
+
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	v := header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+	if sec, err := strconv.Atoi(v); err == nil {
+		return time.Duration(sec) * time.Second, true
+	}
+	if at, err := http.ParseTime(v); err == nil {
+		return at.Sub(now), true
+	}
+	return 0, false
+}
+
+func rateLimitReset(header http.Header) time.Time {
+	v := header.Get("X-RateLimit-Reset")
+	if v == "" {
+		v = header.Get("RateLimit-Reset")
+	}
+	sec, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(sec, 0)
+}
+
+func isGraphQL(req *http.Request) bool {
+	return req != nil && strings.HasSuffix(req.URL.Path, "/graphql")
+}
+
+// peekBody returns the body and restores it to be read again.
+func peekBody(res *http.Response) []byte {
+	body, err := ioutil.ReadAll(res.Body)
+	res.Body.Close()
+	if err != nil {
+		body = nil
+	}
+	res.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body
+}
+
+func isSecondaryRateLimit(res *http.Response) bool {
+	body := strings.ToLower(string(peekBody(res)))
+	return strings.Contains(body, "secondary rate limit") || strings.Contains(body, "abuse")
+}
+
+func hasRateLimitedError(res *http.Response) bool {
+	var body struct {
+		Errors []struct {
+			Type string `json:"type"`
+		} `json:"errors"`
+	}
+	b := peekBody(res)
+	if !bytes.Contains(b, []byte("RATE_LIMITED")) {
+		return false
+	}
+	if err := json.Unmarshal(b, &body); err != nil {
+		return false
+	}
+	for _, e := range body.Errors {
+		if e.Type == "RATE_LIMITED" {
+			return true
+		}
+	}
+	return false
+}
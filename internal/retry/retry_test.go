@@ -0,0 +1,260 @@
+package retry
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// response is the response of test server, the status and headers are
+// responded in order of requests.
+type response struct {
+	status int
+	header map[string]string
+	body   string
+}
+
+func setupTestServer(t *testing.T, responses []response) (*httptest.Server, *int) {
+	count := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		res := responses[len(responses)-1]
+		if count < len(responses) {
+			res = responses[count]
+		}
+		count++
+		for k, v := range res.header {
+			w.Header().Set(k, v)
+		}
+		w.WriteHeader(res.status)
+		w.Write([]byte(res.body))
+	}))
+	return ts, &count
+}
+
+func newTestTransport(now time.Time) (*Transport, *[]time.Duration) {
+	var waits []time.Duration
+	tr := NewTransport(http.DefaultTransport)
+	tr.sleep = func(d time.Duration) { waits = append(waits, d) }
+	tr.now = func() time.Time { return now }
+	return tr, &waits
+}
+
+func TestTransport_RoundTrip(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	reset := strconv.FormatInt(now.Add(30*time.Second).Unix(), 10)
+	farReset := strconv.FormatInt(now.Add(time.Hour).Unix(), 10)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		responses    []response
+		wantStatus   int
+		wantErr      bool
+		wantRequests int
+		wantWaits    []time.Duration
+	}{
+		{
+			name:         "server error",
+			method:       "GET",
+			path:         "/issues",
+			responses:    []response{{status: 500}, {status: 502}, {status: 503}, {status: 200}},
+			wantStatus:   200,
+			wantRequests: 4,
+			wantWaits:    []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name:         "server error after max retries",
+			method:       "GET",
+			path:         "/issues",
+			responses:    []response{{status: 503}},
+			wantStatus:   503,
+			wantRequests: 4,
+			wantWaits:    []time.Duration{time.Second, 2 * time.Second, 4 * time.Second},
+		},
+		{
+			name:         "server error of mutation",
+			method:       "POST",
+			path:         "/graphql",
+			body:         `{"query":"mutation{closeIssue{clientMutationId}}"}`,
+			responses:    []response{{status: 502}},
+			wantStatus:   502,
+			wantRequests: 1,
+		},
+		{
+			name:         "internal server error of post",
+			method:       "POST",
+			path:         "/repos/owner/repo/issues",
+			body:         `{"title":"bug"}`,
+			responses:    []response{{status: 500}},
+			wantStatus:   500,
+			wantRequests: 1,
+		},
+		{
+			name:         "server error of query",
+			method:       "POST",
+			path:         "/graphql",
+			body:         `{"query":"query{viewer{login}}"}`,
+			responses:    []response{{status: 502}, {status: 200}},
+			wantStatus:   200,
+			wantRequests: 2,
+			wantWaits:    []time.Duration{time.Second},
+		},
+		{
+			name:         "retry after",
+			method:       "POST",
+			path:         "/repos/owner/repo/issues",
+			responses:    []response{{status: 429, header: map[string]string{"Retry-After": "10"}}, {status: 201}},
+			wantStatus:   201,
+			wantRequests: 2,
+			wantWaits:    []time.Duration{10 * time.Second},
+		},
+		{
+			name:         "secondary rate limit",
+			method:       "GET",
+			path:         "/issues",
+			responses:    []response{{status: 403, body: `{"message":"You have exceeded a secondary rate limit."}`}, {status: 200}},
+			wantStatus:   200,
+			wantRequests: 2,
+			wantWaits:    []time.Duration{time.Second},
+		},
+		{
+			name:         "forbidden",
+			method:       "GET",
+			path:         "/issues",
+			responses:    []response{{status: 403, body: `{"message":"Resource not accessible"}`}},
+			wantStatus:   403,
+			wantRequests: 1,
+		},
+		{
+			name:   "quota is reset soon",
+			method: "GET",
+			path:   "/issues",
+			responses: []response{
+				{status: 403, header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": reset}},
+				{status: 200},
+			},
+			wantStatus:   200,
+			wantRequests: 2,
+			wantWaits:    []time.Duration{30 * time.Second},
+		},
+		{
+			name:         "quota is exhausted",
+			method:       "GET",
+			path:         "/issues",
+			responses:    []response{{status: 403, header: map[string]string{"X-RateLimit-Remaining": "0", "X-RateLimit-Reset": farReset}}},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name:         "quota of GraphQL is exhausted",
+			method:       "POST",
+			path:         "/graphql",
+			body:         `{"query":"query{viewer{login}}"}`,
+			responses:    []response{{status: 200, header: map[string]string{"X-RateLimit-Reset": farReset}, body: `{"errors":[{"type":"RATE_LIMITED"}]}`}},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+	}
+	for _, tt := range tests {
+		ts, count := setupTestServer(t, tt.responses)
+		tr, waits := newTestTransport(now)
+
+		req, err := http.NewRequest(tt.method, ts.URL+tt.path, strings.NewReader(tt.body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if tt.body == "" {
+			req.Body = nil
+		}
+		res, err := tr.RoundTrip(req)
+		ts.Close()
+
+		if (err != nil) != tt.wantErr {
+			t.Errorf("%s: RoundTrip() error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if err == nil {
+			res.Body.Close()
+			if res.StatusCode != tt.wantStatus {
+				t.Errorf("%s: want status %d, got %d", tt.name, tt.wantStatus, res.StatusCode)
+			}
+		}
+		if *count != tt.wantRequests {
+			t.Errorf("%s: want %d requests, got %d", tt.name, tt.wantRequests, *count)
+		}
+		if !reflect.DeepEqual(*waits, tt.wantWaits) {
+			t.Errorf("%s: waits \nwant %#v \ngot  %#v", tt.name, tt.wantWaits, *waits)
+		}
+	}
+}
+
+func TestTransport_Canceled(t *testing.T) {
+	ts, count := setupTestServer(t, []response{{status: 503}})
+	defer ts.Close()
+	tr := NewTransport(http.DefaultTransport)
+	tr.BaseDelay = time.Hour
+	tr.MaxDelay = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", ts.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tr.RoundTrip(req); err != context.DeadlineExceeded {
+		t.Errorf("want %v, got %v", context.DeadlineExceeded, err)
+	}
+	if *count != 1 {
+		t.Errorf("want 1 request, got %d", *count)
+	}
+}
+
+func TestTransport_RateLimit(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)
+	reset := strconv.FormatInt(now.Add(42*time.Minute).Unix(), 10)
+	ts, _ := setupTestServer(t, []response{
+		{status: http.StatusOK, header: map[string]string{"X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "4999", "X-RateLimit-Reset": reset}},
+		{status: http.StatusOK, header: map[string]string{"RateLimit-Limit": "5000", "RateLimit-Remaining": "400", "RateLimit-Reset": reset}},
+		{status: http.StatusOK, header: map[string]string{"X-RateLimit-Limit": "5000", "X-RateLimit-Remaining": "399", "X-RateLimit-Reset": reset}},
+	})
+	defer ts.Close()
+	tr, _ := newTestTransport(now)
+	var warn bytes.Buffer
+	tr.Warn = &warn
+
+	wantRemaining := []int{4999, 400, 399}
+	for _, want := range wantRemaining {
+		res, err := (&http.Client{Transport: tr}).Get(ts.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+		got := tr.RateLimit()
+		if got == nil || got.Remaining != want || got.Limit != 5000 || got.Reset.Unix() != now.Add(42*time.Minute).Unix() {
+			t.Errorf("RateLimit() want %d of 5000 remaining, got %#v", want, got)
+		}
+	}
+
+	// The warning is written once
+	want := "Warning: API rate limit is running low, 400 of 5000 requests remaining, it's reset at 00:42:00\n"
+	if warn.String() != want {
+		t.Errorf("warning \nwant %q \ngot  %q", want, warn.String())
+	}
+}
+
+func TestRateLimitError(t *testing.T) {
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.Local)
+	err := &RateLimitError{Reset: now.Add(42 * time.Minute), now: now}
+	want := "API rate limit exceeded, it's reset at 00:42:00 (in 42m0s)"
+	if err.Error() != want {
+		t.Errorf("Error() \nwant %q \ngot  %q", want, err.Error())
+	}
+}
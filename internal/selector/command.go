@@ -0,0 +1,152 @@
+package selector
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// PreviewDirEnv is the environment variable of the directory that has the
+// previews of items, "huc preview" reads the preview from it.
+const PreviewDirEnv = "HUC_PREVIEW_DIR"
+
+const previewItemsFile = "items"
+
+// CommandSelector selects items by the external command, e.g. fzf, peco and skim.
+// Items are written to stdin of the command line by line, and the selected lines
+// are read from stdout.
+type CommandSelector struct {
+	Command string
+	Err     io.Writer
+}
+
+func NewCommandSelector(command string) *CommandSelector {
+	return &CommandSelector{
+		Command: command,
+		Err:     os.Stderr,
+	}
+}
+
+func (s *CommandSelector) Select(items []string, opt *Option) ([]int, error) {
+	// The command receives lines, so newlines in the item are replaced
+	lines := make([]string, len(items))
+	for i, item := range items {
+		lines[i] = strings.Replace(item, "\n", " ", -1)
+	}
+
+	env := os.Environ()
+	if opt.Preview != nil {
+		dir, err := writePreviews(lines, opt.Preview)
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+		env = append(env, PreviewDirEnv+"="+dir)
+	}
+
+	cmd := shellCommand(s.Command)
+	cmd.Env = env
+	cmd.Stdin = strings.NewReader(strings.Join(lines, "\n") + "\n")
+	cmd.Stderr = s.Err
+	out := &bytes.Buffer{}
+	cmd.Stdout = out
+	if err := cmd.Run(); err != nil {
+		// fzf exits with 1 for no match and 130 for interrupted
+		if exitErr, ok := err.(*exec.ExitError); ok && out.Len() == 0 {
+			return nil, ErrAbort
+		} else if ok {
+			return nil, fmt.Errorf("selector exited with error, %s", exitErr)
+		}
+		return nil, fmt.Errorf("cannot run selector, %s", err)
+	}
+
+	indices := matchLines(lines, strings.Split(strings.TrimRight(out.String(), "\n"), "\n"))
+	if len(indices) == 0 {
+		return nil, ErrAbort
+	}
+	if !opt.Multi {
+		indices = indices[:1]
+	}
+	return indices, nil
+}
+
+func shellCommand(command string) *exec.Cmd {
+	if runtime.GOOS == "windows" {
+		return exec.Command("cmd", "/c", command)
+	}
+	return exec.Command("sh", "-c", command)
+}
+
+// matchLines returns the indices of selected lines. The same lines are matched in order.
+func matchLines(lines, selected []string) []int {
+	used := make([]bool, len(lines))
+	indices := []int{}
+	for _, s := range selected {
+		for i, line := range lines {
+			if !used[i] && line == s {
+				used[i] = true
+				indices = append(indices, i)
+				break
+			}
+		}
+	}
+	return indices
+}
+
+// writePreviews writes the preview of each item to the file of the index in a temporary directory.
+func writePreviews(lines []string, preview func(i, width, height int) string) (string, error) {
+	dir, err := ioutil.TempDir("", "huc-preview")
+	if err != nil {
+		return "", fmt.Errorf("cannot create preview directory, %s", err)
+	}
+	width, height, err := terminal.GetSize(int(os.Stdin.Fd()))
+	if err != nil {
+		width, height = 80, 24
+	}
+
+	items := strings.Join(lines, "\n") + "\n"
+	if err := ioutil.WriteFile(filepath.Join(dir, previewItemsFile), []byte(items), 0600); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("cannot write preview, %s", err)
+	}
+	for i := range lines {
+		contents := preview(i, width, height)
+		if err := ioutil.WriteFile(filepath.Join(dir, strconv.Itoa(i)), []byte(contents), 0600); err != nil {
+			os.RemoveAll(dir)
+			return "", fmt.Errorf("cannot write preview, %s", err)
+		}
+	}
+	return dir, nil
+}
+
+// ReadPreview reads the preview of the item from the preview directory. The
+// item is the zero-based index of the item or the line of the item.
+func ReadPreview(dir, item string) (string, error) {
+	index, err := strconv.Atoi(item)
+	if err != nil {
+		b, err := ioutil.ReadFile(filepath.Join(dir, previewItemsFile))
+		if err != nil {
+			return "", fmt.Errorf("cannot read preview, %s", err)
+		}
+		indices := matchLines(strings.Split(string(b), "\n"), []string{item})
+		if len(indices) == 0 {
+			return "", fmt.Errorf("Not found preview of the item, '%s'", item)
+		}
+		index = indices[0]
+	}
+
+	b, err := ioutil.ReadFile(filepath.Join(dir, strconv.Itoa(index)))
+	if err != nil {
+		return "", fmt.Errorf("cannot read preview, %s", err)
+	}
+	return string(b), nil
+}
@@ -0,0 +1,39 @@
+package selector
+
+import (
+	"github.com/ktr0731/go-fuzzyfinder"
+)
+
+// FuzzyFinderSelector selects items by go-fuzzyfinder.
+type FuzzyFinderSelector struct{}
+
+func (s *FuzzyFinderSelector) Select(items []string, opt *Option) ([]int, error) {
+	itemFunc := func(i int) string {
+		return items[i]
+	}
+	opts := []fuzzyfinder.Option{}
+	if opt.Preview != nil {
+		opts = append(opts, fuzzyfinder.WithPreviewWindow(opt.Preview))
+	}
+
+	if !opt.Multi {
+		idx, err := fuzzyfinder.Find(items, itemFunc, opts...)
+		if err != nil {
+			return nil, convertError(err)
+		}
+		return []int{idx}, nil
+	}
+
+	indices, err := fuzzyfinder.FindMulti(items, itemFunc, opts...)
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return indices, nil
+}
+
+func convertError(err error) error {
+	if err.Error() == fuzzyfinder.ErrAbort.Error() {
+		return ErrAbort
+	}
+	return err
+}
@@ -0,0 +1,77 @@
+package selector
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// PromptSelector selects items by the numbered prompt for dumb terminals.
+type PromptSelector struct {
+	In  io.Reader
+	Out io.Writer
+}
+
+func NewPromptSelector() *PromptSelector {
+	return &PromptSelector{
+		In:  os.Stdin,
+		Out: os.Stderr,
+	}
+}
+
+func (s *PromptSelector) Select(items []string, opt *Option) ([]int, error) {
+	if len(items) == 0 {
+		return nil, ErrAbort
+	}
+	for i, item := range items {
+		fmt.Fprintf(s.Out, "%3d) %s\n", i+1, item)
+	}
+
+	message := "Select a number"
+	if opt.Multi {
+		message = "Select numbers separated by space or comma"
+	}
+
+	reader := bufio.NewReader(s.In)
+	for {
+		fmt.Fprintf(s.Out, "%s (empty to abort): ", message)
+		line, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		line = strings.TrimSpace(line)
+		if line == "" || line == "q" {
+			return nil, ErrAbort
+		}
+
+		indices, parseErr := parseNumbers(line, len(items))
+		if parseErr == nil && !opt.Multi && len(indices) > 1 {
+			parseErr = fmt.Errorf("Select only one number")
+		}
+		if parseErr == nil {
+			return indices, nil
+		}
+		fmt.Fprintln(s.Out, parseErr)
+		if err == io.EOF {
+			return nil, ErrAbort
+		}
+	}
+}
+
+func parseNumbers(line string, max int) ([]int, error) {
+	fields := strings.FieldsFunc(line, func(c rune) bool {
+		return c == ' ' || c == ','
+	})
+	indices := []int{}
+	for _, f := range fields {
+		n, err := strconv.Atoi(f)
+		if err != nil || n < 1 || n > max {
+			return nil, fmt.Errorf("Invalid number, '%s'", f)
+		}
+		indices = append(indices, n-1)
+	}
+	return indices, nil
+}
@@ -0,0 +1,57 @@
+// Package selector selects items of the list by fuzzy finder, external command or prompt.
+package selector
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"golang.org/x/crypto/ssh/terminal"
+)
+
+// Names of the built-in selector. Any other name is run as an external command.
+const (
+	FuzzyFinder = "fuzzyfinder"
+	Prompt      = "prompt"
+)
+
+// ErrAbort is returned when the user aborts selecting.
+var ErrAbort = errors.New("abort")
+
+// Selector selects items and returns the indices of selected items.
+type Selector interface {
+	Select(items []string, opt *Option) ([]int, error)
+}
+
+// Option is the option of selecting.
+type Option struct {
+	// Multi enables selecting multiple items
+	Multi bool
+	// Preview returns the contents of preview window of the item. The width
+	// and height are the size of screen, i is -1 when there is no item.
+	Preview func(i, width, height int) string
+}
+
+// New returns the selector of the name. Numbered prompt is used for dumb
+// terminals when the name is empty.
+func New(name string) Selector {
+	switch strings.TrimSpace(name) {
+	case "":
+		if isDumbTerminal() {
+			return NewPromptSelector()
+		}
+		return &FuzzyFinderSelector{}
+	case FuzzyFinder:
+		return &FuzzyFinderSelector{}
+	case Prompt:
+		return NewPromptSelector()
+	}
+	return NewCommandSelector(name)
+}
+
+func isDumbTerminal() bool {
+	if os.Getenv("TERM") == "dumb" {
+		return true
+	}
+	return !terminal.IsTerminal(int(os.Stdin.Fd()))
+}
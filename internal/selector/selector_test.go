@@ -0,0 +1,117 @@
+package selector
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestCommandSelector_Select(t *testing.T) {
+	items := []string{"1 first", "2 second", "3 third"}
+	tests := []struct {
+		name    string
+		command string
+		multi   bool
+		want    []int
+		wantErr error
+	}{
+		{name: "single", command: "grep second", want: []int{1}},
+		{name: "multi", command: "grep -v second", multi: true, want: []int{0, 2}},
+		{name: "first of multi lines", command: "grep -v second", want: []int{0}},
+		{name: "no match", command: "grep fourth", wantErr: ErrAbort},
+		{name: "interrupted", command: "exit 130", wantErr: ErrAbort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &CommandSelector{Command: tt.command, Err: &bytes.Buffer{}}
+			got, err := s.Select(items, &Option{Multi: tt.multi})
+			if err != tt.wantErr {
+				t.Fatalf("Select() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() \nwant %#v \ngot  %#v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCommandSelector_Preview(t *testing.T) {
+	s := &CommandSelector{
+		// Select the line of the preview that contains "second"
+		Command: `for i in 0 1 2; do grep -q second "$HUC_PREVIEW_DIR/$i" && sed -n "$((i+1))p"; done; true`,
+		Err:     &bytes.Buffer{},
+	}
+	got, err := s.Select([]string{"a", "b", "c"}, &Option{
+		Preview: func(i, w, h int) string {
+			return []string{"first", "second", "third"}[i]
+		},
+	})
+	if err != nil {
+		t.Fatalf("Select() error = %v", err)
+	}
+	if want := []int{1}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Select() \nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestReadPreview(t *testing.T) {
+	dir, err := writePreviews([]string{"1 first", "2 second"}, func(i, w, h int) string {
+		return fmt.Sprintf("preview %d", i)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	tests := []struct {
+		item string
+		want string
+	}{
+		{item: "1", want: "preview 1"},
+		{item: "1 first", want: "preview 0"},
+	}
+	for _, tt := range tests {
+		got, err := ReadPreview(dir, tt.item)
+		if err != nil {
+			t.Fatalf("ReadPreview(%q) error = %v", tt.item, err)
+		}
+		if got != tt.want {
+			t.Errorf("ReadPreview(%q) \nwant %q \ngot  %q", tt.item, tt.want, got)
+		}
+	}
+	if _, err := ReadPreview(dir, "unknown"); err == nil {
+		t.Errorf("ReadPreview() want error of unknown item")
+	}
+}
+
+func TestPromptSelector_Select(t *testing.T) {
+	items := []string{"first", "second", "third"}
+	tests := []struct {
+		name    string
+		input   string
+		multi   bool
+		want    []int
+		wantErr error
+	}{
+		{name: "single", input: "2\n", want: []int{1}},
+		{name: "multi", input: "1, 3\n", multi: true, want: []int{0, 2}},
+		{name: "retry", input: "4\n3\n", want: []int{2}},
+		{name: "multiple numbers for single", input: "1 2\n", wantErr: ErrAbort},
+		{name: "abort", input: "\n", wantErr: ErrAbort},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &PromptSelector{In: strings.NewReader(tt.input), Out: &bytes.Buffer{}}
+			got, err := s.Select(items, &Option{Multi: tt.multi})
+			if err != tt.wantErr {
+				t.Fatalf("Select() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Select() \nwant %#v \ngot  %#v", tt.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,264 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/nsf/termbox-go"
+)
+
+// prompt reads a line in the status bar.
+type prompt struct {
+	label  string
+	input  []rune
+	submit func(a *App, text string)
+}
+
+var helpLines = []string{
+	"Keybindings",
+	"",
+	"  Tab, 1-3   Switch tab",
+	"  j, k       Move cursor",
+	"  g, G       Move to top, bottom",
+	"  J, K       Scroll detail",
+	"  Enter, o   Open in browser",
+	"  c          Checkout pull request",
+	"  m          Comment",
+	"  x          Close",
+	"  l          Add labels",
+	"  a          Add assignees",
+	"  r          Refresh",
+	"  ?          Toggle help",
+	"  q          Quit",
+}
+
+// handleKey handles the key event and returns true to quit.
+func (a *App) handleKey(ev termbox.Event) bool {
+	if a.prompt != nil {
+		a.handlePromptKey(ev)
+		return false
+	}
+
+	t := a.currentTab()
+	a.message = ""
+	switch {
+	case ev.Ch == 'q' || ev.Key == termbox.KeyCtrlC:
+		return true
+	case ev.Key == termbox.KeyEsc:
+		a.help = false
+	case ev.Ch == '?':
+		a.help = !a.help
+	case ev.Key == termbox.KeyTab:
+		a.switchTab((a.current + 1) % len(a.tabs))
+	case ev.Ch >= '1' && ev.Ch <= '3':
+		a.switchTab(int(ev.Ch - '1'))
+	case ev.Ch == 'j' || ev.Key == termbox.KeyArrowDown:
+		t.move(1)
+		a.loadComments()
+	case ev.Ch == 'k' || ev.Key == termbox.KeyArrowUp:
+		t.move(-1)
+		a.loadComments()
+	case ev.Ch == 'g' || ev.Key == termbox.KeyHome:
+		t.move(-len(t.entries))
+		a.loadComments()
+	case ev.Ch == 'G' || ev.Key == termbox.KeyEnd:
+		t.move(len(t.entries))
+		a.loadComments()
+	case ev.Ch == 'J' || ev.Key == termbox.KeyPgdn || ev.Key == termbox.KeyCtrlD:
+		t.scroll += 10
+	case ev.Ch == 'K' || ev.Key == termbox.KeyPgup || ev.Key == termbox.KeyCtrlU:
+		t.scroll -= 10
+		if t.scroll < 0 {
+			t.scroll = 0
+		}
+	case ev.Ch == 'r':
+		a.refresh(t)
+		a.message = "Refreshing " + strings.ToLower(t.name)
+	case ev.Ch == 'o' || ev.Key == termbox.KeyEnter:
+		a.open(t)
+	case ev.Ch == 'c':
+		a.checkout(t)
+	case ev.Ch == 'm':
+		a.startComment(t)
+	case ev.Ch == 'x':
+		a.startClose(t)
+	case ev.Ch == 'l':
+		a.startLabel(t)
+	case ev.Ch == 'a':
+		a.startAssign(t)
+	}
+	return false
+}
+
+func (a *App) handlePromptKey(ev termbox.Event) {
+	p := a.prompt
+	switch {
+	case ev.Key == termbox.KeyEsc || ev.Key == termbox.KeyCtrlC:
+		a.prompt = nil
+		a.message = "Canceled"
+	case ev.Key == termbox.KeyEnter:
+		a.prompt = nil
+		p.submit(a, strings.TrimSpace(string(p.input)))
+	case ev.Key == termbox.KeyBackspace || ev.Key == termbox.KeyBackspace2:
+		if len(p.input) > 0 {
+			p.input = p.input[:len(p.input)-1]
+		}
+	case ev.Key == termbox.KeySpace:
+		p.input = append(p.input, ' ')
+	case ev.Ch != 0:
+		p.input = append(p.input, ev.Ch)
+	}
+}
+
+func (a *App) switchTab(index int) {
+	if index < 0 || index >= len(a.tabs) {
+		return
+	}
+	a.current = index
+	a.loadComments()
+}
+
+// target returns the selected item that is an issue or a pull request.
+func (a *App) target(t *tab) *entry {
+	e := t.selected()
+	if e == nil {
+		a.message = "No item is selected"
+		return nil
+	}
+	if t.kind == tabReleases {
+		a.message = fmt.Sprintf("Release is %s", provider.ErrNotSupported)
+		return nil
+	}
+	return e
+}
+
+func (a *App) open(t *tab) {
+	e := t.selected()
+	if e == nil {
+		return
+	}
+	a.background(nil, func() (string, error) {
+		if err := a.Browser.Open(e.url); err != nil {
+			return "", fmt.Errorf("cannot open browser, %s", err)
+		}
+		return "Opened " + e.url, nil
+	})
+}
+
+// checkout fetches the head of the pull request to the local branch "pr-<number>" and switches to it.
+func (a *App) checkout(t *tab) {
+	if t.kind != tabPullRequests {
+		a.message = "Checkout is available only for pull requests"
+		return
+	}
+	e := a.target(t)
+	if e == nil {
+		return
+	}
+	branch := fmt.Sprintf("pr-%d", e.number)
+	ref := a.Provider.PullRequestRef(e.number)
+	a.background(nil, func() (string, error) {
+		if out, err := a.runGit("fetch", a.Remote, "+"+ref+":"+branch); err != nil {
+			return "", fmt.Errorf("cannot fetch %s, %s", ref, strings.TrimSpace(string(out)))
+		}
+		if out, err := a.runGit("checkout", branch); err != nil {
+			return "", fmt.Errorf("cannot checkout %s, %s", branch, strings.TrimSpace(string(out)))
+		}
+		return "Switched to branch " + branch, nil
+	})
+}
+
+func (a *App) startComment(t *tab) {
+	e := a.target(t)
+	if e == nil {
+		return
+	}
+	a.suspend = func(a *App) {
+		body, err := a.edit("COMMENT.md", "")
+		if err != nil {
+			a.message = err.Error()
+			return
+		}
+		if strings.TrimSpace(body) == "" {
+			a.message = "Aborted by empty comment"
+			return
+		}
+		reload := func(a *App) {
+			delete(t.comments, e.number)
+			a.loadComments()
+		}
+		a.background(reload, func() (string, error) {
+			if err := a.comment(t.kind, e.number, body); err != nil {
+				return "", fmt.Errorf("cannot comment, %s", err)
+			}
+			return fmt.Sprintf("Commented on #%d", e.number), nil
+		})
+	}
+}
+
+func (a *App) startClose(t *tab) {
+	e := a.target(t)
+	if e == nil {
+		return
+	}
+	a.prompt = &prompt{
+		label: fmt.Sprintf("Close #%d? [y/N]: ", e.number),
+		submit: func(a *App, text string) {
+			if text != "y" && text != "Y" {
+				a.message = "Canceled"
+				return
+			}
+			a.background(refresher(t), func() (string, error) {
+				if err := a.close(t.kind, e.number); err != nil {
+					return "", fmt.Errorf("cannot close, %s", err)
+				}
+				return fmt.Sprintf("Closed #%d", e.number), nil
+			})
+		},
+	}
+}
+
+func (a *App) startLabel(t *tab) {
+	e := a.target(t)
+	if e == nil {
+		return
+	}
+	a.prompt = &prompt{
+		label: fmt.Sprintf("Add labels to #%d: ", e.number),
+		submit: func(a *App, text string) {
+			labels := splitList(text)
+			if len(labels) == 0 {
+				return
+			}
+			a.background(refresher(t), func() (string, error) {
+				if err := a.label(t.kind, e.number, labels); err != nil {
+					return "", fmt.Errorf("cannot add labels, %s", err)
+				}
+				return fmt.Sprintf("Added labels to #%d", e.number), nil
+			})
+		},
+	}
+}
+
+func (a *App) startAssign(t *tab) {
+	e := a.target(t)
+	if e == nil {
+		return
+	}
+	a.prompt = &prompt{
+		label: fmt.Sprintf("Add assignees to #%d: ", e.number),
+		submit: func(a *App, text string) {
+			users := splitList(text)
+			if len(users) == 0 {
+				return
+			}
+			a.background(refresher(t), func() (string, error) {
+				if err := a.assign(t.kind, e.number, users); err != nil {
+					return "", fmt.Errorf("cannot add assignees, %s", err)
+				}
+				return fmt.Sprintf("Added assignees to #%d", e.number), nil
+			})
+		},
+	}
+}
@@ -0,0 +1,137 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/markdown"
+	"github.com/lighttiger2505/huc/internal/provider"
+)
+
+// entry is the item of list, an issue, a pull request or a release.
+type entry struct {
+	// number is 0 for release
+	number      int
+	tag         string
+	title       string
+	author      string
+	publishedAt time.Time
+	body        string
+	url         string
+}
+
+func (e *entry) label() string {
+	if e.number == 0 {
+		return e.tag + " " + e.title
+	}
+	return fmt.Sprintf("#%d %s", e.number, e.title)
+}
+
+type comments struct {
+	items   []provider.Comment
+	loading bool
+	err     error
+}
+
+type tab struct {
+	kind    int
+	name    string
+	entries []*entry
+	cursor  int
+	// offset is the first row of the list in the screen
+	offset int
+	// scroll is the first line of the detail in the screen
+	scroll   int
+	loading  bool
+	err      error
+	comments map[int]*comments
+}
+
+func newTab(kind int, name string) *tab {
+	return &tab{
+		kind:     kind,
+		name:     name,
+		entries:  []*entry{},
+		comments: map[int]*comments{},
+	}
+}
+
+func (t *tab) selected() *entry {
+	if t.cursor < 0 || t.cursor >= len(t.entries) {
+		return nil
+	}
+	return t.entries[t.cursor]
+}
+
+// setEntries replaces the entries keeping the cursor on the same item.
+func (t *tab) setEntries(entries []*entry) {
+	var selectedLabel string
+	if e := t.selected(); e != nil {
+		selectedLabel = e.label()
+	}
+
+	t.entries = entries
+	t.comments = map[int]*comments{}
+	for i, e := range entries {
+		if e.label() == selectedLabel {
+			t.cursor = i
+			return
+		}
+	}
+	t.move(0)
+}
+
+// move moves the cursor by delta within the entries.
+func (t *tab) move(delta int) {
+	cursor := t.cursor + delta
+	if cursor >= len(t.entries) {
+		cursor = len(t.entries) - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	if cursor != t.cursor {
+		t.scroll = 0
+	}
+	t.cursor = cursor
+}
+
+// detailLines returns the lines of the selected item that the body is rendered as markdown.
+func (t *tab) detailLines(width int) []string {
+	e := t.selected()
+	if e == nil {
+		return []string{}
+	}
+
+	lines := []string{e.label()}
+	if e.author != "" {
+		lines = append(lines, fmt.Sprintf("@%s opened at %s", e.author, e.publishedAt.Format("2006-01-02 15:04")))
+	}
+	lines = append(lines, "")
+	if strings.TrimSpace(e.body) == "" {
+		lines = append(lines, "No description provided.")
+	} else {
+		lines = append(lines, strings.Split(markdown.Render(e.body, width, false), "\n")...)
+	}
+
+	if t.kind == tabReleases {
+		return lines
+	}
+
+	lines = append(lines, "")
+	c, ok := t.comments[e.number]
+	switch {
+	case !ok || c.loading:
+		lines = append(lines, "── Loading comments...")
+	case c.err != nil:
+		lines = append(lines, "── Cannot load comments, "+c.err.Error())
+	default:
+		lines = append(lines, fmt.Sprintf("── Comments (%d)", len(c.items)))
+		for _, comment := range c.items {
+			lines = append(lines, "", fmt.Sprintf("@%s commented at %s", comment.Author, comment.PublishedAt.Format("2006-01-02 15:04")))
+			lines = append(lines, strings.Split(markdown.Render(comment.Body, width, false), "\n")...)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,348 @@
+// Package tui is the full-screen interface to triage issues, pull requests and releases.
+package tui
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/lighttiger2505/huc/internal/cmdutil"
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/nsf/termbox-go"
+)
+
+// Kinds of tab
+const (
+	tabIssues = iota
+	tabPullRequests
+	tabReleases
+)
+
+// listNum is the number of items to fetch for each tab
+const listNum = 50
+
+// App is the state of the full-screen interface. All states are changed on
+// the goroutine of the event loop, the network requests run on the other
+// goroutines and send the functions to apply the results.
+type App struct {
+	Provider provider.Provider
+	Browser  cmdutil.URLOpener
+	// Title is shown at the right of tabs, e.g. the name of repository
+	Title string
+	// Remote is the git remote to fetch the head of pull requests
+	Remote string
+	// RefreshInterval is the interval of background refresh, it's disabled when zero
+	RefreshInterval time.Duration
+
+	tabs    []*tab
+	current int
+	prompt  *prompt
+	message string
+	help    bool
+
+	results chan func(*App)
+	// suspend is run after the terminal is restored, e.g. to run the editor
+	suspend func(*App)
+	runGit  func(args ...string) ([]byte, error)
+	edit    func(filename, text string) (string, error)
+}
+
+// New returns App that has tabs of issues, pull requests and releases.
+func New(p provider.Provider, browser cmdutil.URLOpener) *App {
+	return &App{
+		Provider: p,
+		Browser:  browser,
+		Remote:   "origin",
+		tabs: []*tab{
+			newTab(tabIssues, "Issues"),
+			newTab(tabPullRequests, "Pull Requests"),
+			newTab(tabReleases, "Releases"),
+		},
+		results: make(chan func(*App), 16),
+		runGit: func(args ...string) ([]byte, error) {
+			return exec.Command("git", args...).CombinedOutput()
+		},
+		edit: cmdutil.EditText,
+	}
+}
+
+// Run runs the event loop until the user quits.
+func (a *App) Run() error {
+	if err := termbox.Init(); err != nil {
+		return fmt.Errorf("cannot initialize terminal, %s", err)
+	}
+	defer func() {
+		// Terminal is not initialized when it's failed to restore after suspended
+		if termbox.IsInit {
+			termbox.Close()
+		}
+	}()
+
+	var tick <-chan time.Time
+	if a.RefreshInterval > 0 {
+		ticker := time.NewTicker(a.RefreshInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	events := pollEvents()
+	for _, t := range a.tabs {
+		a.refresh(t)
+	}
+
+	for {
+		a.draw()
+		select {
+		case ev := <-events:
+			switch ev.Type {
+			case termbox.EventKey:
+				if quit := a.handleKey(ev); quit {
+					termbox.Interrupt()
+					return nil
+				}
+			case termbox.EventError:
+				return ev.Err
+			}
+		case apply := <-a.results:
+			apply(a)
+		case <-tick:
+			for _, t := range a.tabs {
+				a.refresh(t)
+			}
+		}
+
+		if a.suspend != nil {
+			var err error
+			if events, err = a.runSuspended(events); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// pollEvents sends terminal events to the channel until it's interrupted.
+func pollEvents() <-chan termbox.Event {
+	events := make(chan termbox.Event)
+	go func() {
+		for {
+			ev := termbox.PollEvent()
+			events <- ev
+			if ev.Type == termbox.EventInterrupt {
+				return
+			}
+		}
+	}()
+	return events
+}
+
+// runSuspended restores the terminal and runs the suspended function, e.g. the editor.
+func (a *App) runSuspended(events <-chan termbox.Event) (<-chan termbox.Event, error) {
+	termbox.Interrupt()
+	for ev := range events {
+		if ev.Type == termbox.EventInterrupt {
+			break
+		}
+	}
+	termbox.Close()
+
+	fn := a.suspend
+	a.suspend = nil
+	fn(a)
+
+	if err := termbox.Init(); err != nil {
+		return nil, fmt.Errorf("cannot initialize terminal, %s", err)
+	}
+	return pollEvents(), nil
+}
+
+func (a *App) currentTab() *tab {
+	return a.tabs[a.current]
+}
+
+// refresh fetches the items of the tab in background.
+func (a *App) refresh(t *tab) {
+	t.loading = true
+	go func() {
+		entries, err := a.fetch(t.kind)
+		a.results <- func(a *App) {
+			t.loading = false
+			t.err = err
+			if err != nil {
+				a.message = fmt.Sprintf("cannot load %s, %s", strings.ToLower(t.name), err)
+				return
+			}
+			t.setEntries(entries)
+			a.loadComments()
+		}
+	}()
+}
+
+func (a *App) fetch(kind int) ([]*entry, error) {
+	entries := []*entry{}
+	switch kind {
+	case tabIssues:
+		issues, err := a.Provider.ListIssue(&provider.ListIssueOption{
+			Num:       listNum,
+			Sort:      provider.SortCreatedAt,
+			Direction: provider.DirectionDesc,
+			States:    provider.StateOpen,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, i := range issues {
+			entries = append(entries, &entry{
+				number:      i.Number,
+				title:       i.Title,
+				author:      i.Author,
+				publishedAt: i.PublishedAt,
+				body:        i.Body,
+				url:         a.Provider.IssueURL(i.Number),
+			})
+		}
+	case tabPullRequests:
+		pullRequests, err := a.Provider.ListPullRequest(&provider.ListPullRequestOption{
+			Num:       listNum,
+			Sort:      provider.SortCreatedAt,
+			Direction: provider.DirectionDesc,
+			States:    provider.StateOpen,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, pr := range pullRequests {
+			entries = append(entries, &entry{
+				number:      pr.Number,
+				title:       pr.Title,
+				author:      pr.Author,
+				publishedAt: pr.PublishedAt,
+				body:        pr.Body,
+				url:         a.Provider.PullRequestURL(pr.Number),
+			})
+		}
+	case tabReleases:
+		releases, err := a.Provider.ListRelease(&provider.ListReleaseOption{
+			Num:       listNum,
+			Sort:      provider.SortCreatedAt,
+			Direction: provider.DirectionDesc,
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range releases {
+			entries = append(entries, &entry{
+				tag:   r.TagName,
+				title: r.Name,
+				body:  r.Description,
+				url:   a.Provider.ReleaseURL(r.TagName),
+			})
+		}
+	}
+	return entries, nil
+}
+
+// loadComments fetches the comments of the selected item in background when they are not loaded.
+func (a *App) loadComments() {
+	t := a.currentTab()
+	e := t.selected()
+	if e == nil || t.kind == tabReleases {
+		return
+	}
+	if _, ok := t.comments[e.number]; ok {
+		return
+	}
+
+	c := &comments{loading: true}
+	t.comments[e.number] = c
+	go func() {
+		items, err := a.listComments(t.kind, e.number)
+		a.results <- func(a *App) {
+			c.loading = false
+			c.items = items
+			c.err = err
+		}
+	}()
+}
+
+func (a *App) listComments(kind, number int) ([]provider.Comment, error) {
+	switch kind {
+	case tabIssues:
+		return a.Provider.ListIssueComments(number)
+	case tabPullRequests:
+		return a.Provider.ListPullRequestComments(number)
+	}
+	return nil, provider.ErrNotSupported
+}
+
+func (a *App) comment(kind, number int, body string) error {
+	switch kind {
+	case tabIssues:
+		return a.Provider.CommentIssue(number, body)
+	case tabPullRequests:
+		return a.Provider.CommentPullRequest(number, body)
+	}
+	return provider.ErrNotSupported
+}
+
+func (a *App) close(kind, number int) error {
+	switch kind {
+	case tabIssues:
+		return a.Provider.CloseIssue(number)
+	case tabPullRequests:
+		return a.Provider.ClosePullRequest(number)
+	}
+	return provider.ErrNotSupported
+}
+
+func (a *App) label(kind, number int, labels []string) error {
+	switch kind {
+	case tabIssues:
+		return a.Provider.LabelIssue(number, labels)
+	case tabPullRequests:
+		return a.Provider.LabelPullRequest(number, labels)
+	}
+	return provider.ErrNotSupported
+}
+
+func (a *App) assign(kind, number int, users []string) error {
+	switch kind {
+	case tabIssues:
+		return a.Provider.AssignIssue(number, users)
+	case tabPullRequests:
+		return a.Provider.AssignPullRequest(number, users)
+	}
+	return provider.ErrNotSupported
+}
+
+// background runs the action in background and shows the message of the result.
+// after is called on the event loop when the action succeeds.
+func (a *App) background(after func(a *App), action func() (string, error)) {
+	go func() {
+		message, err := action()
+		a.results <- func(a *App) {
+			if err != nil {
+				a.message = err.Error()
+				return
+			}
+			a.message = message
+			if after != nil {
+				after(a)
+			}
+		}
+	}()
+}
+
+// refresher returns the function to refresh the tab after the action.
+func refresher(t *tab) func(a *App) {
+	return func(a *App) {
+		a.refresh(t)
+	}
+}
+
+// splitList splits the comma or space separated list, e.g. "bug, ui".
+func splitList(s string) []string {
+	return strings.FieldsFunc(s, func(c rune) bool {
+		return c == ',' || c == ' '
+	})
+}
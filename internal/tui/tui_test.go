@@ -0,0 +1,187 @@
+package tui
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/provider"
+	"github.com/nsf/termbox-go"
+)
+
+// fakeProvider implements the methods used by App, the others panic by the nil embedded interface.
+type fakeProvider struct {
+	provider.Provider
+	issues []provider.Issue
+	closed []int
+	labels map[int][]string
+}
+
+func (p *fakeProvider) ListIssue(opt *provider.ListIssueOption) ([]provider.Issue, error) {
+	return p.issues, nil
+}
+
+func (p *fakeProvider) IssueURL(number int) string {
+	return fmt.Sprintf("https://github.com/owner/repo/issues/%d", number)
+}
+
+func (p *fakeProvider) ListIssueComments(number int) ([]provider.Comment, error) {
+	return []provider.Comment{{Author: "user", Body: "comment"}}, nil
+}
+
+func (p *fakeProvider) CloseIssue(number int) error {
+	p.closed = append(p.closed, number)
+	return nil
+}
+
+func (p *fakeProvider) LabelIssue(number int, labels []string) error {
+	p.labels[number] = labels
+	return nil
+}
+
+func newTestApp() (*App, *fakeProvider) {
+	p := &fakeProvider{
+		issues: []provider.Issue{
+			{Number: 3, Title: "third", Author: "user", Body: "**body**"},
+			{Number: 2, Title: "second", Author: "user"},
+			{Number: 1, Title: "first", Author: "user"},
+		},
+		labels: map[int][]string{},
+	}
+	a := New(p, nil)
+	a.refresh(a.tabs[tabIssues])
+	a.apply()
+	// Comments of the selected issue
+	a.apply()
+	return a, p
+}
+
+// apply applies the result of background task.
+func (a *App) apply() {
+	(<-a.results)(a)
+}
+
+func key(ch rune) termbox.Event {
+	return termbox.Event{Type: termbox.EventKey, Ch: ch}
+}
+
+func TestApp_refresh(t *testing.T) {
+	a, _ := newTestApp()
+	tab := a.tabs[tabIssues]
+	if tab.loading {
+		t.Errorf("tab is still loading")
+	}
+	got := []string{}
+	for _, e := range tab.entries {
+		got = append(got, e.label())
+	}
+	want := []string{"#3 third", "#2 second", "#1 first"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("entries \nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestApp_handleKey_Move(t *testing.T) {
+	a, _ := newTestApp()
+	tab := a.tabs[tabIssues]
+	tests := []struct {
+		ch   rune
+		want int
+	}{
+		{ch: 'j', want: 1},
+		{ch: 'j', want: 2},
+		{ch: 'j', want: 2},
+		{ch: 'k', want: 1},
+		{ch: 'g', want: 0},
+		{ch: 'G', want: 2},
+	}
+	for _, tt := range tests {
+		if quit := a.handleKey(key(tt.ch)); quit {
+			t.Fatalf("handleKey(%q) quit", tt.ch)
+		}
+		if tab.cursor != tt.want {
+			t.Errorf("cursor after %q \nwant %d \ngot  %d", tt.ch, tt.want, tab.cursor)
+		}
+	}
+	if quit := a.handleKey(key('q')); !quit {
+		t.Errorf("handleKey('q') want quit")
+	}
+}
+
+func TestApp_handleKey_Close(t *testing.T) {
+	a, p := newTestApp()
+	a.handleKey(key('j'))
+	a.apply()
+
+	a.handleKey(key('x'))
+	if a.prompt == nil {
+		t.Fatalf("prompt is not shown")
+	}
+	a.handleKey(key('y'))
+	a.handleKey(termbox.Event{Type: termbox.EventKey, Key: termbox.KeyEnter})
+	a.apply()
+
+	if want := []int{2}; !reflect.DeepEqual(p.closed, want) {
+		t.Errorf("closed \nwant %#v \ngot  %#v", want, p.closed)
+	}
+	if want := "Closed #2"; a.message != want {
+		t.Errorf("message \nwant %q \ngot  %q", want, a.message)
+	}
+}
+
+func TestApp_handleKey_CloseCanceled(t *testing.T) {
+	a, p := newTestApp()
+	a.handleKey(key('x'))
+	a.handleKey(termbox.Event{Type: termbox.EventKey, Key: termbox.KeyEnter})
+	if len(p.closed) != 0 {
+		t.Errorf("closed without confirmation, %v", p.closed)
+	}
+}
+
+func TestApp_handleKey_Label(t *testing.T) {
+	a, p := newTestApp()
+	a.handleKey(key('l'))
+	for _, ch := range "bug, ui" {
+		a.handleKey(key(ch))
+	}
+	a.handleKey(termbox.Event{Type: termbox.EventKey, Key: termbox.KeyEnter})
+	a.apply()
+
+	want := map[int][]string{3: {"bug", "ui"}}
+	if !reflect.DeepEqual(p.labels, want) {
+		t.Errorf("labels \nwant %#v \ngot  %#v", want, p.labels)
+	}
+}
+
+func TestApp_handleKey_ReleaseNotSupported(t *testing.T) {
+	a, _ := newTestApp()
+	a.current = tabReleases
+	a.tabs[tabReleases].setEntries([]*entry{{tag: "v1.0.0", title: "release"}})
+
+	a.handleKey(key('x'))
+	if a.prompt != nil {
+		t.Errorf("prompt is shown for release")
+	}
+	if want := "Release is not supported operation"; a.message != want {
+		t.Errorf("message \nwant %q \ngot  %q", want, a.message)
+	}
+}
+
+func TestTab_detailLines(t *testing.T) {
+	a, _ := newTestApp()
+	got := strings.Join(a.tabs[tabIssues].detailLines(40), "\n")
+	for _, want := range []string{"#3 third", "body", "── Comments (1)", "@user commented", "comment"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("detail lines do not contain %q \ngot  %q", want, got)
+		}
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	got := splitList(" bug, ui  help wanted")
+	want := []string{"bug", "ui", "help", "wanted"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("splitList() \nwant %#v \ngot  %#v", want, got)
+	}
+}
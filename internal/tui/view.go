@@ -0,0 +1,161 @@
+package tui
+
+import (
+	"fmt"
+
+	runewidth "github.com/mattn/go-runewidth"
+	"github.com/nsf/termbox-go"
+)
+
+const (
+	colorDefault = termbox.ColorDefault
+	colorDim     = termbox.ColorBlue
+)
+
+func (a *App) draw() {
+	termbox.Clear(colorDefault, colorDefault)
+	width, height := termbox.Size()
+	if width < 10 || height < 4 {
+		termbox.Flush()
+		return
+	}
+
+	a.drawTabs(width)
+
+	listWidth := width * 2 / 5
+	if listWidth < 20 {
+		listWidth = width / 2
+	}
+	bodyHeight := height - 2
+	a.drawList(0, 1, listWidth, bodyHeight)
+	for y := 1; y < height-1; y++ {
+		termbox.SetCell(listWidth, y, '│', colorDim, colorDefault)
+	}
+	a.drawDetail(listWidth+2, 1, width-listWidth-3, bodyHeight)
+	a.drawStatus(height-1, width)
+	termbox.Flush()
+}
+
+func (a *App) drawTabs(width int) {
+	x := 0
+	for i, t := range a.tabs {
+		label := fmt.Sprintf(" %d:%s (%d) ", i+1, t.name, len(t.entries))
+		if t.loading {
+			label = fmt.Sprintf(" %d:%s (...) ", i+1, t.name)
+		}
+		fg, bg := colorDefault, colorDefault
+		if i == a.current {
+			fg, bg = termbox.ColorDefault|termbox.AttrReverse|termbox.AttrBold, colorDefault
+		}
+		x += drawText(x, 0, width-x, label, fg, bg)
+		x += drawText(x, 0, width-x, " ", colorDefault, colorDefault)
+	}
+	if a.Title != "" {
+		titleWidth := runewidth.StringWidth(a.Title)
+		if width-titleWidth-1 > x {
+			drawText(width-titleWidth-1, 0, titleWidth, a.Title, termbox.AttrBold, colorDefault)
+		}
+	}
+}
+
+func (a *App) drawList(x, y, width, height int) {
+	t := a.currentTab()
+	if len(t.entries) == 0 {
+		switch {
+		case t.loading:
+			drawText(x+1, y, width-1, "Loading...", colorDim, colorDefault)
+		case t.err != nil:
+			drawText(x+1, y, width-1, "Error, press r to retry", termbox.ColorRed, colorDefault)
+		default:
+			drawText(x+1, y, width-1, "No "+t.name, colorDim, colorDefault)
+		}
+		return
+	}
+
+	// Keep the cursor in the screen
+	if t.cursor < t.offset {
+		t.offset = t.cursor
+	}
+	if t.cursor >= t.offset+height {
+		t.offset = t.cursor - height + 1
+	}
+
+	for row := 0; row < height && t.offset+row < len(t.entries); row++ {
+		i := t.offset + row
+		fg, bg := colorDefault, colorDefault
+		if i == t.cursor {
+			fg = colorDefault | termbox.AttrReverse
+			fillLine(x, y+row, width, fg, bg)
+		}
+		drawText(x+1, y+row, width-1, t.entries[i].label(), fg, bg)
+	}
+}
+
+func (a *App) drawDetail(x, y, width, height int) {
+	if width < 1 {
+		return
+	}
+	lines := helpLines
+	t := a.currentTab()
+	if !a.help {
+		lines = t.detailLines(width)
+		maxScroll := len(lines) - height
+		if maxScroll < 0 {
+			maxScroll = 0
+		}
+		if t.scroll > maxScroll {
+			t.scroll = maxScroll
+		}
+		lines = lines[t.scroll:]
+	}
+
+	for row := 0; row < height && row < len(lines); row++ {
+		fg := colorDefault
+		if row == 0 && (a.help || t.scroll == 0) {
+			fg = termbox.AttrBold
+		}
+		drawText(x, y+row, width, lines[row], fg, colorDefault)
+	}
+}
+
+func (a *App) drawStatus(y, width int) {
+	if a.prompt != nil {
+		x := drawText(0, y, width, a.prompt.label, termbox.AttrBold, colorDefault)
+		x += drawText(x, y, width-x, string(a.prompt.input), colorDefault, colorDefault)
+		termbox.SetCursor(x, y)
+		return
+	}
+	termbox.HideCursor()
+
+	if a.message != "" {
+		drawText(0, y, width, a.message, termbox.ColorYellow, colorDefault)
+		return
+	}
+	drawText(0, y, width, "q:quit  ?:help  tab:switch  o:open  c:checkout  m:comment  x:close  l:label  a:assign  r:refresh", colorDim, colorDefault)
+}
+
+// drawText draws the text truncated by width and returns the drawn width.
+func drawText(x, y, width int, text string, fg, bg termbox.Attribute) int {
+	drawn := 0
+	for _, c := range text {
+		w := runewidth.RuneWidth(c)
+		if c == '\t' {
+			c, w = ' ', 1
+		}
+		if w == 0 {
+			continue
+		}
+		if drawn+w > width {
+			break
+		}
+		termbox.SetCell(x+drawn, y, c, fg, bg)
+		drawn += w
+	}
+	return drawn
+}
+
+func fillLine(x, y, width int, fg, bg termbox.Attribute) {
+	for i := 0; i < width; i++ {
+		termbox.SetCell(x+i, y, ' ', fg, bg)
+	}
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"fmt"
+	"sync"
+)
+
+// The prompts of UI, they are matched with Answer.Prompt.
+const (
+	PromptAsk         = "Ask"
+	PromptAskSecret   = "AskSecret"
+	PromptConfirm     = "Confirm"
+	PromptSelect      = "Select"
+	PromptMultiSelect = "MultiSelect"
+	PromptEditor      = "Editor"
+)
+
+// Answer is the scripted answer of a prompt.
+type Answer struct {
+	// Prompt is the expected prompt, e.g. PromptConfirm. Any prompt is answered when it's empty
+	Prompt string
+	// Query is the expected query, or the filename of Editor. Any query is answered when it's empty
+	Query string
+	// Text is the input of Ask and AskSecret, or the edited text of Editor
+	Text string
+	// Yes is the answer of Confirm
+	Yes bool
+	// Indices are the selected options of Select and MultiSelect
+	Indices []int
+	// Err is returned instead of the answer, e.g. ErrInterrupted
+	Err error
+}
+
+// ScriptedUi answers the prompts by Answers in order without the user, it's
+// the UI of tests. The messages are recorded to Messages and Errors.
+type ScriptedUi struct {
+	Answers  []Answer
+	Messages []string
+	Errors   []string
+
+	l sync.Mutex
+}
+
+func (s *ScriptedUi) next(prompt, query string) (*Answer, error) {
+	s.l.Lock()
+	defer s.l.Unlock()
+
+	if len(s.Answers) == 0 {
+		return nil, fmt.Errorf("Not found answer, %s '%s'", prompt, query)
+	}
+	a := s.Answers[0]
+	if a.Prompt != "" && a.Prompt != prompt {
+		return nil, fmt.Errorf("Invalid prompt, want %s, got %s '%s'", a.Prompt, prompt, query)
+	}
+	if a.Query != "" && a.Query != query {
+		return nil, fmt.Errorf("Invalid query, want '%s', got '%s'", a.Query, query)
+	}
+	s.Answers = s.Answers[1:]
+	if a.Err != nil {
+		return nil, a.Err
+	}
+	return &a, nil
+}
+
+func (s *ScriptedUi) Ask(query string) (string, error) {
+	a, err := s.next(PromptAsk, query)
+	if err != nil {
+		return "", err
+	}
+	return a.Text, nil
+}
+
+func (s *ScriptedUi) AskSecret(query string) (string, error) {
+	a, err := s.next(PromptAskSecret, query)
+	if err != nil {
+		return "", err
+	}
+	return a.Text, nil
+}
+
+func (s *ScriptedUi) Confirm(query string, def bool) (bool, error) {
+	a, err := s.next(PromptConfirm, query)
+	if err != nil {
+		return false, err
+	}
+	return a.Yes, nil
+}
+
+func (s *ScriptedUi) Select(query string, options []string) (int, error) {
+	a, err := s.next(PromptSelect, query)
+	if err != nil {
+		return 0, err
+	}
+	if len(a.Indices) != 1 || a.Indices[0] >= len(options) {
+		return 0, fmt.Errorf("Invalid indices, %v of %d options", a.Indices, len(options))
+	}
+	return a.Indices[0], nil
+}
+
+func (s *ScriptedUi) MultiSelect(query string, options []string) ([]int, error) {
+	a, err := s.next(PromptMultiSelect, query)
+	if err != nil {
+		return nil, err
+	}
+	for _, i := range a.Indices {
+		if i >= len(options) {
+			return nil, fmt.Errorf("Invalid indices, %v of %d options", a.Indices, len(options))
+		}
+	}
+	return a.Indices, nil
+}
+
+func (s *ScriptedUi) Editor(filename, text string) (string, error) {
+	a, err := s.next(PromptEditor, filename)
+	if err != nil {
+		return "", err
+	}
+	return a.Text, nil
+}
+
+func (s *ScriptedUi) Say(message string) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.Messages = append(s.Messages, message)
+}
+
+func (s *ScriptedUi) Message(message string) {
+	s.Say(message)
+}
+
+func (s *ScriptedUi) Error(message string) {
+	s.l.Lock()
+	defer s.l.Unlock()
+	s.Errors = append(s.Errors, message)
+}
+
+func (s *ScriptedUi) Machine(t string, args ...string) {}
+
+// Unanswered returns the answers that are not used.
+func (s *ScriptedUi) Unanswered() []Answer {
+	s.l.Lock()
+	defer s.l.Unlock()
+	return s.Answers
+}
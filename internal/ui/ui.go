@@ -8,11 +8,26 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"sync"
+
+	"github.com/lighttiger2505/huc/internal/selector"
+	"golang.org/x/crypto/ssh/terminal"
 )
 
 type UI interface {
 	Ask(string) (string, error)
+	// AskSecret asks without echo, e.g. the private token
+	AskSecret(string) (string, error)
+	// Confirm asks yes or no, def is the answer of empty input
+	Confirm(query string, def bool) (bool, error)
+	// Select asks one of the options and returns the index
+	Select(query string, options []string) (int, error)
+	// MultiSelect asks the options and returns the indices, they are empty when nothing is selected
+	MultiSelect(query string, options []string) ([]int, error)
+	// Editor opens the text in the editor and returns the edited text, the
+	// filename is the name of temporary file, e.g. "COMMENT.md"
+	Editor(filename, text string) (string, error)
 	Say(string)
 	Message(string)
 	Error(string)
@@ -23,30 +38,91 @@ type BasicUi struct {
 	Reader      io.Reader
 	Writer      io.Writer
 	ErrorWriter io.Writer
+	// Selector selects the options of Select and MultiSelect, the fuzzy
+	// finder is used when it's nil
+	Selector selector.Selector
+	// EditText opens the text in the editor, Editor fails when it's nil
+	EditText func(filename, text string) (string, error)
+	// NonInteractive disables the prompts. Confirm answers yes and the other
+	// prompts fail with ErrNonInteractive.
+	NonInteractive bool
+
 	l           sync.Mutex
 	interrupted bool
 	scanner     *bufio.Scanner
 }
 
+// ErrInterrupted is returned when the user interrupts the input by Ctrl-C.
+var ErrInterrupted = errors.New("interrupted")
+
+// ErrNonInteractive is returned when the prompt is required in the non-interactive mode.
+var ErrNonInteractive = errors.New("cannot ask in non-interactive mode")
+
 func NewBasicUi() *BasicUi {
 	return &BasicUi{
 		Reader:      os.Stdin,
 		Writer:      os.Stdout,
-		ErrorWriter: os.Stdout,
+		ErrorWriter: os.Stderr,
 	}
 }
 
 func (rw *BasicUi) Ask(query string) (string, error) {
+	if rw.NonInteractive {
+		return "", fmt.Errorf("%w, %s", ErrNonInteractive, query)
+	}
+	return rw.ask(query, nil)
+}
+
+// AskSecret reads the input without echo when the reader is a terminal,
+// otherwise it reads a line as Ask, e.g. the token piped by scripts.
+func (rw *BasicUi) AskSecret(query string) (string, error) {
+	if rw.NonInteractive {
+		return "", fmt.Errorf("%w, %s", ErrNonInteractive, query)
+	}
+	f, ok := rw.Reader.(*os.File)
+	if !ok || !terminal.IsTerminal(int(f.Fd())) {
+		return rw.ask(query, nil)
+	}
+
+	fd := int(f.Fd())
+	state, err := terminal.GetState(fd)
+	if err != nil {
+		return "", err
+	}
+	read := func() (string, error) {
+		b, err := terminal.ReadPassword(fd)
+		// The newline of input is not echoed either
+		fmt.Fprintln(rw.Writer)
+		return string(b), err
+	}
+	line, err := rw.ask(query, read)
+	if err == ErrInterrupted {
+		// The reading goroutine never restores the echo after the interrupt
+		terminal.Restore(fd, state)
+	}
+	return line, err
+}
+
+func (rw *BasicUi) ask(query string, read func() (string, error)) (string, error) {
 	rw.l.Lock()
 	defer rw.l.Unlock()
 
 	if rw.interrupted {
-		return "", errors.New("interrupted")
+		return "", ErrInterrupted
 	}
 
 	if rw.scanner == nil {
 		rw.scanner = bufio.NewScanner(rw.Reader)
 	}
+	if read == nil {
+		read = func() (string, error) {
+			var line string
+			if rw.scanner.Scan() {
+				line = rw.scanner.Text()
+			}
+			return line, rw.scanner.Err()
+		}
+	}
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, os.Interrupt)
 	defer signal.Stop(sigCh)
@@ -58,22 +134,25 @@ func (rw *BasicUi) Ask(query string) (string, error) {
 		}
 	}
 
-	result := make(chan string, 1)
+	type answer struct {
+		line string
+		err  error
+	}
+	result := make(chan answer, 1)
 	go func() {
-		var line string
-		if rw.scanner.Scan() {
-			line = rw.scanner.Text()
-		}
-		if err := rw.scanner.Err(); err != nil {
+		line, err := read()
+		if err != nil {
 			log.Printf("ui: scan err: %s", err)
-			return
 		}
-		result <- line
+		result <- answer{line: line, err: err}
 	}()
 
 	select {
-	case line := <-result:
-		return line, nil
+	case a := <-result:
+		if a.err != nil {
+			return "", fmt.Errorf("cannot read the answer, %w", a.err)
+		}
+		return a.line, nil
 	case <-sigCh:
 		// Print a newline so that any further output starts properly
 		// on a new line.
@@ -82,8 +161,79 @@ func (rw *BasicUi) Ask(query string) (string, error) {
 		// Mark that we were interrupted so future Ask calls fail.
 		rw.interrupted = true
 
-		return "", errors.New("interrupted")
+		return "", ErrInterrupted
+	}
+}
+
+// Confirm asks until the answer is yes or no, it's yes in the non-interactive mode.
+func (rw *BasicUi) Confirm(query string, def bool) (bool, error) {
+	if rw.NonInteractive {
+		return true, nil
+	}
+	suffix := "[y/N]"
+	if def {
+		suffix = "[Y/n]"
+	}
+	for {
+		answer, err := rw.ask(query+" "+suffix, nil)
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+		rw.Error(fmt.Sprintf("Invalid answer, '%s'. Please enter y or n", answer))
+	}
+}
+
+func (rw *BasicUi) Select(query string, options []string) (int, error) {
+	indices, err := rw.selectOptions(query, options, false)
+	if err != nil {
+		return 0, err
+	}
+	return indices[0], nil
+}
+
+func (rw *BasicUi) MultiSelect(query string, options []string) ([]int, error) {
+	return rw.selectOptions(query, options, true)
+}
+
+func (rw *BasicUi) selectOptions(query string, options []string, multi bool) ([]int, error) {
+	if rw.NonInteractive {
+		return nil, fmt.Errorf("%w, %s", ErrNonInteractive, query)
+	}
+	s := rw.Selector
+	if s == nil {
+		s = selector.New("")
+	}
+	rw.Error(query)
+	indices, err := s.Select(options, &selector.Option{Multi: multi})
+	if err != nil {
+		return nil, err
+	}
+	// The empty selection of multiple options is the answer, e.g. no labels
+	if len(indices) == 0 {
+		if multi {
+			return []int{}, nil
+		}
+		return nil, selector.ErrAbort
+	}
+	return indices, nil
+}
+
+func (rw *BasicUi) Editor(filename, text string) (string, error) {
+	if rw.NonInteractive {
+		return "", fmt.Errorf("%w, %s", ErrNonInteractive, filename)
+	}
+	if rw.EditText == nil {
+		return "", fmt.Errorf("Not found editor")
 	}
+	return rw.EditText(filename, text)
 }
 
 func (rw *BasicUi) Say(message string) {
@@ -0,0 +1,180 @@
+package ui
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/lighttiger2505/huc/internal/selector"
+)
+
+type mockSelector struct {
+	indices []int
+	opt     *selector.Option
+}
+
+func (s *mockSelector) Select(items []string, opt *selector.Option) ([]int, error) {
+	s.opt = opt
+	return s.indices, nil
+}
+
+func TestBasicUi_Confirm(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		def        bool
+		want       bool
+		wantOutput string
+		wantErrOut string
+	}{
+		{name: "yes", input: "y\n", def: false, want: true, wantOutput: "Delete? [y/N] "},
+		{name: "no", input: "No\n", def: true, want: false, wantOutput: "Delete? [Y/n] "},
+		{name: "default yes", input: "\n", def: true, want: true, wantOutput: "Delete? [Y/n] "},
+		{name: "default at EOF", input: "", def: false, want: false, wantOutput: "Delete? [y/N] "},
+		{
+			name:       "retry invalid answer",
+			input:      "maybe\nyes\n",
+			def:        false,
+			want:       true,
+			wantOutput: "Delete? [y/N] Delete? [y/N] ",
+			wantErrOut: "Invalid answer, 'maybe'. Please enter y or n\n",
+		},
+	}
+	for _, tt := range tests {
+		out, errOut := &bytes.Buffer{}, &bytes.Buffer{}
+		ui := &BasicUi{Reader: strings.NewReader(tt.input), Writer: out, ErrorWriter: errOut}
+		got, err := ui.Confirm("Delete?", tt.def)
+		if err != nil {
+			t.Fatalf("%s: %s", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: answer \nwant %#v \ngot  %#v", tt.name, tt.want, got)
+		}
+		if out.String() != tt.wantOutput {
+			t.Errorf("%s: output \nwant %#v \ngot  %#v", tt.name, tt.wantOutput, out.String())
+		}
+		if errOut.String() != tt.wantErrOut {
+			t.Errorf("%s: error output \nwant %#v \ngot  %#v", tt.name, tt.wantErrOut, errOut.String())
+		}
+	}
+}
+
+func TestBasicUi_AskSecret(t *testing.T) {
+	// The input that is not a terminal is read as a line, e.g. the token piped by scripts
+	out := &bytes.Buffer{}
+	ui := &BasicUi{Reader: strings.NewReader("secret-token\n"), Writer: out}
+	got, err := ui.AskSecret("Please enter GitLab private token:")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "secret-token" {
+		t.Errorf("answer \nwant %#v \ngot  %#v", "secret-token", got)
+	}
+	if want := "Please enter GitLab private token: "; out.String() != want {
+		t.Errorf("output \nwant %#v \ngot  %#v", want, out.String())
+	}
+}
+
+func TestBasicUi_Select(t *testing.T) {
+	s := &mockSelector{indices: []int{2, 0}}
+	errOut := &bytes.Buffer{}
+	ui := &BasicUi{Reader: strings.NewReader(""), Writer: &bytes.Buffer{}, ErrorWriter: errOut, Selector: s}
+
+	got, err := ui.MultiSelect("Select labels", []string{"bug", "docs", "feature"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{2, 0}; !reflect.DeepEqual(want, got) {
+		t.Errorf("indices \nwant %#v \ngot  %#v", want, got)
+	}
+	if !s.opt.Multi {
+		t.Errorf("MultiSelect must select multiple items")
+	}
+	if want := "Select labels\n"; errOut.String() != want {
+		t.Errorf("error output \nwant %#v \ngot  %#v", want, errOut.String())
+	}
+
+	s.indices = nil
+	if _, err := ui.Select("Select a label", []string{"bug"}); err != selector.ErrAbort {
+		t.Errorf("error of empty selection \nwant %#v \ngot  %#v", selector.ErrAbort, err)
+	}
+	got, err = ui.MultiSelect("Select labels", []string{"bug"})
+	if err != nil {
+		t.Fatalf("error of empty multiple selection, %s", err)
+	}
+	if want := []int{}; !reflect.DeepEqual(want, got) {
+		t.Errorf("indices of empty multiple selection \nwant %#v \ngot  %#v", want, got)
+	}
+}
+
+func TestBasicUi_ReadError(t *testing.T) {
+	ui := &BasicUi{Writer: &bytes.Buffer{}}
+	_, err := ui.ask("Token:", func() (string, error) {
+		return "", io.ErrUnexpectedEOF
+	})
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("error \nwant %#v \ngot  %#v", io.ErrUnexpectedEOF, err)
+	}
+}
+
+func TestBasicUi_NonInteractive(t *testing.T) {
+	ui := &BasicUi{
+		Reader:         strings.NewReader("never read\n"),
+		Writer:         &bytes.Buffer{},
+		Selector:       &mockSelector{indices: []int{0}},
+		EditText:       func(filename, text string) (string, error) { return text, nil },
+		NonInteractive: true,
+	}
+
+	yes, err := ui.Confirm("Delete?", false)
+	if err != nil || !yes {
+		t.Errorf("Confirm must answer yes, got %#v, %v", yes, err)
+	}
+	prompts := map[string]func() error{
+		"Ask":         func() error { _, err := ui.Ask("Title:"); return err },
+		"AskSecret":   func() error { _, err := ui.AskSecret("Token:"); return err },
+		"Select":      func() error { _, err := ui.Select("Select", []string{"a"}); return err },
+		"MultiSelect": func() error { _, err := ui.MultiSelect("Select", []string{"a"}); return err },
+		"Editor":      func() error { _, err := ui.Editor("COMMENT.md", ""); return err },
+	}
+	for name, prompt := range prompts {
+		if err := prompt(); !errors.Is(err, ErrNonInteractive) {
+			t.Errorf("%s: want ErrNonInteractive, got %v", name, err)
+		}
+	}
+}
+
+func TestScriptedUi(t *testing.T) {
+	ui := &ScriptedUi{
+		Answers: []Answer{
+			{Prompt: PromptAskSecret, Query: "Token:", Text: "secret"},
+			{Prompt: PromptConfirm, Yes: true},
+			{Prompt: PromptSelect, Indices: []int{1}},
+		},
+	}
+
+	token, err := ui.AskSecret("Token:")
+	if err != nil || token != "secret" {
+		t.Errorf("AskSecret \nwant %#v \ngot  %#v, %v", "secret", token, err)
+	}
+	if _, err := ui.Ask("Title:"); err == nil {
+		t.Errorf("Ask must fail for the answer of Confirm")
+	}
+	yes, err := ui.Confirm("Delete?", false)
+	if err != nil || !yes {
+		t.Errorf("Confirm \nwant %#v \ngot  %#v, %v", true, yes, err)
+	}
+	i, err := ui.Select("Select", []string{"a", "b"})
+	if err != nil || i != 1 {
+		t.Errorf("Select \nwant %#v \ngot  %#v, %v", 1, i, err)
+	}
+	if _, err := ui.Editor("COMMENT.md", ""); err == nil {
+		t.Errorf("Editor must fail without the answer")
+	}
+	if len(ui.Unanswered()) != 0 {
+		t.Errorf("unanswered %#v", ui.Unanswered())
+	}
+}